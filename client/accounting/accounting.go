@@ -0,0 +1,152 @@
+// Package accounting converts RTWire transactions into double entry journal
+// entries that can be imported into accounting systems.
+//
+// Entries are produced by Journal and written by an Exporter. CSVExporter
+// writes the general journal import format accepted by QuickBooks and most
+// other accounting packages.
+package accounting
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// Rate returns the fiat price of one bitcoin at time t.
+type Rate func(t time.Time) (float64, error)
+
+// Chart names the ledger accounts that journal lines are posted to.
+type Chart struct {
+	// Wallet is the asset account holding the RTWire balance.
+	Wallet string
+
+	// Deposits is credited when bitcoins arrive from outside RTWire.
+	Deposits string
+
+	// Withdrawals is debited when bitcoins are sent outside RTWire.
+	Withdrawals string
+
+	// Transfers is the clearing account for transfers between RTWire
+	// accounts.
+	Transfers string
+}
+
+// Line is one side of a journal entry. Debit and Credit are in fiat minor
+// units, for example cents.
+type Line struct {
+	Account string
+	Debit   int64
+	Credit  int64
+	Satoshi int64
+}
+
+// Entry is a balanced journal entry for a single RTWire transaction.
+type Entry struct {
+	Date  time.Time
+	TxID  int64
+	Memo  string
+	Lines []Line
+}
+
+// Exporter writes journal entries to an accounting system.
+type Exporter interface {
+	Export(entries []Entry) error
+}
+
+// Journal converts the transactions of accountID into journal entries using
+// rate to value each transaction at the time it was created.
+func Journal(accountID int64, txns []client.Transaction, chart Chart,
+	rate Rate) ([]Entry, error) {
+
+	entries := make([]Entry, 0, len(txns))
+	for _, tx := range txns {
+		price, err := rate(tx.Created)
+		if err != nil {
+			return nil, err
+		}
+		fiat := toFiat(int64(tx.Value), price)
+
+		var counter, memo string
+		incoming := false
+		switch tx.Type {
+		case "credit":
+			counter, memo, incoming = chart.Deposits, "deposit", true
+		case "debit":
+			counter, memo = chart.Withdrawals, "withdrawal"
+		case "transfer":
+			counter = chart.Transfers
+			incoming = tx.ToAccountID == accountID
+			if incoming {
+				memo = fmt.Sprintf("transfer from account %d", tx.FromAccountID)
+			} else {
+				memo = fmt.Sprintf("transfer to account %d", tx.ToAccountID)
+			}
+		default:
+			return nil, fmt.Errorf("tx %d: unknown type %q", tx.ID, tx.Type)
+		}
+
+		wallet := Line{Account: chart.Wallet, Satoshi: int64(tx.Value)}
+		other := Line{Account: counter, Satoshi: int64(tx.Value)}
+		if incoming {
+			wallet.Debit, other.Credit = fiat, fiat
+		} else {
+			wallet.Credit, other.Debit = fiat, fiat
+		}
+
+		entries = append(entries, Entry{
+			Date:  tx.Created,
+			TxID:  tx.ID,
+			Memo:  memo,
+			Lines: []Line{wallet, other},
+		})
+	}
+	return entries, nil
+}
+
+// CSVExporter writes entries as a general journal CSV file with one row per
+// line.
+type CSVExporter struct {
+	w *csv.Writer
+}
+
+// NewCSVExporter creates an Exporter writing to w.
+func NewCSVExporter(w io.Writer) *CSVExporter {
+	return &CSVExporter{w: csv.NewWriter(w)}
+}
+
+// Export writes a header followed by entries.
+func (e *CSVExporter) Export(entries []Entry) error {
+	if err := e.w.Write([]string{
+		"JournalNo", "JournalDate", "AccountName", "Debits", "Credits",
+		"Description",
+	}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			if err := e.w.Write([]string{
+				strconv.FormatInt(entry.TxID, 10),
+				entry.Date.UTC().Format("2006-01-02"),
+				line.Account,
+				formatMinor(line.Debit),
+				formatMinor(line.Credit),
+				fmt.Sprintf("%s (%d sat)", entry.Memo, line.Satoshi),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func formatMinor(v int64) string {
+	if v == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d.%02d", v/100, v%100)
+}
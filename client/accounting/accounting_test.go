@@ -0,0 +1,78 @@
+package accounting_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/accounting"
+)
+
+func TestJournal(t *testing.T) {
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	txns := []client.Transaction{{
+		ID:      1,
+		Type:    "credit",
+		Value:   100000000,
+		Created: created,
+	}, {
+		ID:            2,
+		Type:          "transfer",
+		FromAccountID: 7,
+		ToAccountID:   8,
+		Value:         50000,
+		Created:       created,
+	}}
+
+	chart := accounting.Chart{
+		Wallet:    "RTWire",
+		Deposits:  "Customer Deposits",
+		Transfers: "Internal Transfers",
+	}
+	rate := func(time.Time) (float64, error) { return 10000, nil }
+
+	entries, err := accounting.Journal(7, txns, chart, rate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatal("expected two entries", len(entries))
+	}
+
+	for _, entry := range entries {
+		var debits, credits int64
+		for _, line := range entry.Lines {
+			debits += line.Debit
+			credits += line.Credit
+		}
+		if debits != credits {
+			t.Fatalf("unbalanced entry %+v", entry)
+		}
+	}
+
+	if entries[0].Lines[0].Debit != 1000000 {
+		t.Fatal("incorrect deposit value", entries[0].Lines[0].Debit)
+	}
+
+	// Transfer out of account 7 credits the wallet.
+	if entries[1].Lines[0].Credit != 500 {
+		t.Fatal("incorrect transfer value", entries[1].Lines[0].Credit)
+	}
+
+	var buf bytes.Buffer
+	if err := accounting.NewCSVExporter(&buf).Export(entries); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected header and four lines\n%s", buf.String())
+	}
+	if lines[1] != "1,2020-01-02,RTWire,10000.00,,deposit (100000000 sat)" {
+		t.Fatalf("unexpected line %q", lines[1])
+	}
+}
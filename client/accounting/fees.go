@@ -0,0 +1,63 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// DebitCost is the estimated cost of sending a debit, in satoshi and in fiat
+// minor units at the rate when it was estimated.
+type DebitCost struct {
+	FeePerByte int64
+	Fee        int64
+	Total      int64
+
+	Rate      float64
+	FeeFiat   int64
+	TotalFiat int64
+}
+
+// EstimateDebitCost estimates the network fee of a debit of value satoshi
+// whose transaction is sizeEstimate bytes, using the most recent fee from
+// RTWire, and converts it to fiat with rate. Total is the value plus the fee.
+func EstimateDebitCost(ctx context.Context, c client.Client, rate Rate,
+	value, sizeEstimate int64) (DebitCost, error) {
+
+	fees, err := c.FeesContext(ctx)
+	if err != nil {
+		return DebitCost{}, err
+	}
+	if len(fees) == 0 {
+		return DebitCost{}, errors.New("no fees available")
+	}
+	latest := fees[0]
+	for _, fee := range fees[1:] {
+		if fee.BlockHeight > latest.BlockHeight {
+			latest = fee
+		}
+	}
+
+	price, err := rate(time.Now())
+	if err != nil {
+		return DebitCost{}, err
+	}
+
+	fee := latest.FeePerByte * sizeEstimate
+	return DebitCost{
+		FeePerByte: latest.FeePerByte,
+		Fee:        fee,
+		Total:      value + fee,
+		Rate:       price,
+		FeeFiat:    toFiat(fee, price),
+		TotalFiat:  toFiat(value+fee, price),
+	}, nil
+}
+
+// toFiat converts satoshi to fiat minor units at price per bitcoin.
+func toFiat(satoshi int64, price float64) int64 {
+	return int64(math.Round(float64(satoshi) * price / 1e6))
+}
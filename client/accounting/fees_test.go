@@ -0,0 +1,47 @@
+package accounting_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/accounting"
+)
+
+func TestEstimateDebitCost(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "fees", "payload": [
+				{"feePerByte": 50, "blockHeight": 100},
+				{"feePerByte": 20, "blockHeight": 101}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+	rate := func(time.Time) (float64, error) { return 10000, nil }
+
+	cost, err := accounting.EstimateDebitCost(context.Background(), cl, rate,
+		1000000, 250)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := accounting.DebitCost{
+		FeePerByte: 20,
+		Fee:        5000,
+		Total:      1005000,
+		Rate:       10000,
+		FeeFiat:    50,
+		TotalFiat:  10050,
+	}
+	if cost != expected {
+		t.Fatalf("unexpected cost %+v", cost)
+	}
+}
@@ -0,0 +1,259 @@
+package client
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	errAddressEncoding = errors.New("not a base58 or bech32 address")
+	errAddressChecksum = errors.New("bad checksum")
+	errAddressProgram  = errors.New("bad witness program")
+	errAddressLength   = errors.New("not a 20 byte hash")
+	errAddressVersion  = errors.New("unknown address version")
+)
+
+// AddressType is a kind of bitcoin address, set with AddressFormat when
+// creating an address.
+type AddressType string
+
+const (
+	// P2PKH is a legacy pay to public key hash address, starting with 1 on
+	// mainnet. It is created unless another type is requested.
+	P2PKH AddressType = "p2pkh"
+
+	// P2SHSegwit is a segwit address wrapped in pay to script hash, starting
+	// with 3 on mainnet, for wallets unable to send to bech32 addresses.
+	P2SHSegwit AddressType = "p2sh-p2wpkh"
+
+	// Bech32 is a native segwit address, starting with bc1q on mainnet. It is
+	// the cheapest to spend from.
+	Bech32 AddressType = "p2wpkh"
+
+	// P2TR is a taproot address, encoded with bech32m and starting with bc1p
+	// on mainnet.
+	P2TR AddressType = "p2tr"
+)
+
+// typeOf returns the type of addr, a valid address, or "" if it is none of
+// the types RTWire creates.
+func typeOf(addr string) AddressType {
+	lower := strings.ToLower(addr)
+	for _, hrp := range []string{"bc1", "tb1", "bcrt1"} {
+		if !strings.HasPrefix(lower, hrp) {
+			continue
+		}
+		if checkSegwit(addr) != nil {
+			return ""
+		}
+		// The witness version follows the separator.
+		switch lower[len(hrp)] {
+		case 'q':
+			return Bech32
+		case 'p':
+			return P2TR
+		}
+		return ""
+	}
+	payload, err := decodeBase58Check(addr)
+	if err != nil || len(payload) == 0 {
+		return ""
+	}
+	switch payload[0] {
+	case 0x00, 0x6f:
+		return P2PKH
+	case 0x05, 0xc4:
+		return P2SHSegwit
+	}
+	return ""
+}
+
+// checkAddress verifies the checksum of a base58check, bech32 or bech32m
+// encoded bitcoin address. It does not check which of the built-in networks
+// the address is for, but base58check addresses must have the version byte of
+// one of them.
+func checkAddress(addr string) error {
+	lower := strings.ToLower(addr)
+	for _, hrp := range []string{"bc1", "tb1", "bcrt1"} {
+		if strings.HasPrefix(lower, hrp) {
+			return checkSegwit(addr)
+		}
+	}
+	version, err := base58Address(addr)
+	if err != nil {
+		return err
+	}
+	for _, n := range []Network{MainNet, TestNet3, Signet, RegTest} {
+		if version == n.P2PKH || version == n.P2SH {
+			return nil
+		}
+	}
+	return errAddressVersion
+}
+
+// base58Address decodes a base58check address and returns its version byte.
+// Its payload must be the version byte followed by a 20 byte hash, so that
+// other base58check strings, such as WIF private keys, are not taken for
+// addresses.
+func base58Address(addr string) (byte, error) {
+	payload, err := decodeBase58Check(addr)
+	if err != nil {
+		return 0, err
+	}
+	if len(payload) != 21 {
+		return 0, errAddressLength
+	}
+	return payload[0], nil
+}
+
+// checkSegwit verifies a segwit address as defined in BIP 173 and BIP 350.
+// Version 0 witness programs use the bech32 checksum and later versions, such
+// as Taproot's version 1, use bech32m.
+func checkSegwit(addr string) error {
+	_, data, checksum, err := decodeBech32(addr)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 || data[0] > 16 {
+		return errAddressProgram
+	}
+	version := data[0]
+	if (version == 0) != (checksum == bech32Const) {
+		return errAddressChecksum
+	}
+
+	program, ok := convertBits(data[1:], 5, 8)
+	switch {
+	case !ok || len(program) < 2 || len(program) > 40:
+		return errAddressProgram
+	case version == 0 && len(program) != 20 && len(program) != 32:
+		return errAddressProgram
+	}
+	return nil
+}
+
+// convertBits regroups values of from bits into values of to bits, reporting
+// false if the padding left over is not zero or longer than from bits.
+func convertBits(data []byte, from, to uint) ([]byte, bool) {
+	var (
+		acc  uint
+		bits uint
+		out  []byte
+	)
+	for _, v := range data {
+		acc = acc<<from | uint(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&(1<<to-1)))
+		}
+	}
+	if bits >= from || acc&(1<<bits-1) != 0 {
+		return nil, false
+	}
+	return out, true
+}
+
+// decodeBase58Check decodes a base58check string, returning its payload,
+// including the version byte, without the checksum.
+func decodeBase58Check(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errAddressEncoding
+	}
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, r := range s {
+		i := strings.IndexRune(base58Alphabet, r)
+		if i < 0 {
+			return nil, errAddressEncoding
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+
+	// Leading '1's encode leading zero bytes.
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+	b := append(make([]byte, zeros), n.Bytes()...)
+	if len(b) < 5 {
+		return nil, errAddressEncoding
+	}
+
+	payload, checksum := b[:len(b)-4], b[len(b)-4:]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if string(second[:4]) != string(checksum) {
+		return nil, errAddressChecksum
+	}
+	return payload, nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// The checksum constants of bech32 (BIP 173) and bech32m (BIP 350).
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+// bech32Polymod computes the BCH checksum of values as defined in BIP 173.
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd,
+		0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// decodeBech32 decodes a bech32 or bech32m string, returning its human
+// readable part, its data as 5 bit values without the checksum and the
+// checksum constant it was encoded with.
+func decodeBech32(s string) (string, []byte, uint32, error) {
+	if len(s) > 90 || (strings.ToLower(s) != s && strings.ToUpper(s) != s) {
+		return "", nil, 0, errAddressEncoding
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, 0, errAddressEncoding
+	}
+	hrp := s[:sep]
+
+	data := make([]byte, 0, len(s)-sep-1)
+	for _, r := range s[sep+1:] {
+		i := strings.IndexRune(bech32Charset, r)
+		if i < 0 {
+			return "", nil, 0, errAddressEncoding
+		}
+		data = append(data, byte(i))
+	}
+
+	values := make([]byte, 0, len(hrp)*2+1+len(data))
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	values = append(values, data...)
+	checksum := bech32Polymod(values)
+	if checksum != bech32Const && checksum != bech32mConst {
+		return "", nil, 0, errAddressChecksum
+	}
+	return hrp, data[:len(data)-6], checksum, nil
+}
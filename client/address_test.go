@@ -0,0 +1,236 @@
+package client_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestAddressChecksum(t *testing.T) {
+
+	hc := &http.Client{Transport: client.RoundTripperFunc(
+		func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("sent")
+		})}
+	mainnet := client.New(hc, client.MainNetURL, "user", "pass")
+	testnet := client.New(hc, client.TestNet3URL, "user", "pass")
+
+	tests := []struct {
+		addr  string
+		valid bool
+	}{
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", true},
+		{"mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", true},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", true},
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", true},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+			true},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+			true},
+		{"bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4dekq08ge8ztwac72sfr9rusxg3297",
+			true},
+		{"tb1pqqqqp399et2xygdj5xreqhjjvcmzhxw4aywxecjdzew6hylgvsesf3hn0c",
+			true},
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", false},
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfN0", false},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", false},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7KV8f3t4", false},
+		{"bc1", false},
+		// Version 1 with a bech32 rather than bech32m checksum.
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd",
+			false},
+		// Version 0 with a bech32m checksum.
+		{"tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47",
+			false},
+		// A WIF private key and a 19 byte hash are not addresses.
+		{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", false},
+		{"11GsChQR2U32pvwJcDNPoYHhGcnz5Rv", false},
+		// Version 17 does not exist.
+		{"BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R",
+			false},
+	}
+	for _, test := range tests {
+		// Addresses are checked against the client's network.
+		cl := mainnet
+		if test.addr[0] == 'm' || strings.HasPrefix(test.addr, "tb1") {
+			cl = testnet
+		}
+		_, err := cl.Debit(1, 1, test.addr, 1)
+		var verr *client.ValidationError
+		if invalid := errors.As(err, &verr); invalid == test.valid {
+			t.Errorf("%s: expected valid %v, got %v", test.addr,
+				test.valid, err)
+		}
+	}
+}
+
+func TestAddressNetwork(t *testing.T) {
+
+	hc := &http.Client{Transport: client.RoundTripperFunc(
+		func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("sent")
+		})}
+	mainnet := client.New(hc, client.MainNetURL, "user", "pass")
+	testnet := client.New(hc, client.TestNet3URL, "user", "pass")
+
+	tests := []struct {
+		cl   client.Client
+		addr string
+	}{
+		{mainnet, "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"},
+		{mainnet, "2MzQwSSnBHWHqSAqtTVQ6v47XtaisrJa1Vc"},
+		{mainnet, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"},
+		{mainnet, "bcrt1q6rhpng9evdsfnn833a4f4vej0asu6dk5srld6x"},
+		{testnet, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},
+		{testnet, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"},
+		{testnet, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"},
+		{testnet, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb"},
+	}
+	for _, test := range tests {
+		_, err := test.cl.Debit(1, 1, test.addr, 1)
+		if !errors.Is(err, client.ErrInvalidAddress) {
+			t.Errorf("%s: expected ErrInvalidAddress, got %v", test.addr,
+				err)
+		}
+	}
+	outputs := []client.Output{{Address: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn",
+		Value: 1}}
+	if _, err := mainnet.DebitMany(1, 1, outputs); !errors.Is(err,
+		client.ErrInvalidAddress) {
+		t.Error("expected outputs checked against the network", err)
+	}
+}
+
+func TestAddressWithoutNetwork(t *testing.T) {
+
+	hc := &http.Client{Transport: client.RoundTripperFunc(
+		func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("sent")
+		})}
+	// The network of a URL it doesn't recognize is not known.
+	cl := client.New(hc, "http://localhost:8080/v1/custom", "user", "pass")
+
+	tests := []struct {
+		addr  string
+		valid bool
+	}{
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		{"2MzQwSSnBHWHqSAqtTVQ6v47XtaisrJa1Vc", true},
+		{"bcrt1q6rhpng9evdsfnn833a4f4vej0asu6dk5srld6x", true},
+		{"5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ", false},
+		// A 20 byte hash with a version byte of no built-in network.
+		{"LKDyUEtTR1HXamkiEphisSiBJu6o3ZPE34", false},
+	}
+	for _, test := range tests {
+		_, err := cl.Debit(1, 1, test.addr, 1)
+		if errors.Is(err, client.ErrInvalidAddress) == test.valid {
+			t.Errorf("%s: expected valid %v, got %v", test.addr,
+				test.valid, err)
+		}
+	}
+}
+
+func TestAddressFormat(t *testing.T) {
+
+	var requested string
+	returned := "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Type string `json:"type"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			requested = body.Type
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"type": "addresses",
+				"payload": [{"address": %q}]}`, returned)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	addr, err := cl.CreateAddress(1, client.AddressFormat(client.Bech32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != returned || requested != "p2wpkh" {
+		t.Fatal("expected a bech32 address to be requested", requested, addr)
+	}
+
+	// RTWire ignoring the requested type is an error.
+	returned = "1BoatSLRHtKNngkdXEeobR76b53LETtpyT"
+	if _, err := cl.CreateAddress(1,
+		client.AddressFormat(client.P2SHSegwit)); err == nil {
+		t.Fatal("expected a legacy address to be refused")
+	}
+	returned = "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0"
+	if _, err := cl.CreateAddress(1,
+		client.AddressFormat(client.P2TR)); err != nil || requested != "p2tr" {
+		t.Fatal("expected a taproot address", err, requested)
+	}
+	returned = "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"
+	if _, err := cl.CreateAddress(1,
+		client.AddressFormat(client.P2SHSegwit)); err != nil {
+		t.Fatal(err)
+	}
+
+	// No type is sent unless one is requested.
+	if _, err := cl.CreateAddress(1); err != nil || requested != "" {
+		t.Fatal("expected the default address type", err, requested)
+	}
+
+	var verr *client.ValidationError
+	if _, err := cl.CreateAddress(1,
+		client.AddressFormat("p2pk")); !errors.As(err, &verr) {
+		t.Fatal("expected unknown types to be refused", err)
+	}
+}
+
+func TestAddressExpiry(t *testing.T) {
+
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body = nil
+			json.NewDecoder(r.Body).Decode(&body)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "addresses", "payload": [
+				{"address": "1BoatSLRHtKNngkdXEeobR76b53LETtpyT"}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	if _, err := cl.CreateAddress(1, client.SingleUse(),
+		client.ExpireAfter(90*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if body["singleUse"] != true || body["expiresIn"] != float64(5400) {
+		t.Fatal("expected single use and expiry to be requested", body)
+	}
+
+	var verr *client.ValidationError
+	if _, err := cl.CreateAddress(1,
+		client.ExpireAfter(time.Millisecond)); !errors.As(err, &verr) {
+		t.Fatal("expected expiry under a second to be refused", err)
+	}
+
+	var addr client.Address
+	if err := json.Unmarshal([]byte(`{"address": "a", "singleUse": true,
+		"expires": 1500000000}`), &addr); err != nil {
+		t.Fatal(err)
+	}
+	if !addr.SingleUse || !addr.Expires.Equal(time.Unix(1500000000, 0)) {
+		t.Fatal("expected expiry decoded", addr)
+	}
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrInvalidAmount is returned from ParseAmount and ParseBTC if the amount
+	// is malformed, negative or more precise than one satoshi.
+	ErrInvalidAmount = errors.New("invalid amount")
+
+	// ErrAmbiguousAmount is returned from ParseAmount and ParseBTC if the
+	// amount could be read differently depending on locale, for example
+	// "1,000" which is either one or one thousand.
+	ErrAmbiguousAmount = errors.New("ambiguous amount")
+
+	// ErrAmountOverflow is returned from Amount arithmetic whose result does
+	// not fit in an int64.
+	ErrAmountOverflow = errors.New("amount overflow")
+)
+
+// Amount is a value in satoshi, such as an account balance or the value of a
+// transfer. It is encoded in JSON as an integer number of satoshi, as RTWire
+// does.
+type Amount int64
+
+// satoshiPerBTC is the number of satoshi in one bitcoin.
+const satoshiPerBTC = 100000000
+
+// FromBTC converts btc bitcoin to an Amount, rounded to the nearest satoshi.
+// ErrInvalidAmount is returned if btc is not finite or out of range. Prefer
+// ParseBTC for amounts entered as text, which is exact.
+func FromBTC(btc float64) (Amount, error) {
+	sat := math.Round(btc * satoshiPerBTC)
+	if math.IsNaN(sat) || sat >= math.MaxInt64 || sat < math.MinInt64 {
+		return 0, fmt.Errorf("%w: %v BTC", ErrInvalidAmount, btc)
+	}
+	return Amount(sat), nil
+}
+
+// ToBTC returns a in bitcoin. The result is only approximate beyond about 90
+// million bitcoin, far above the bitcoin supply.
+func (a Amount) ToBTC() float64 {
+	return float64(a) / satoshiPerBTC
+}
+
+// String formats a in bitcoin with all eight decimals, for example
+// "0.00010000 BTC".
+func (a Amount) String() string {
+	sign, sat := "", uint64(a)
+	if a < 0 {
+		sign, sat = "-", uint64(-a)
+	}
+	return fmt.Sprintf("%s%d.%08d BTC", sign, sat/satoshiPerBTC,
+		sat%satoshiPerBTC)
+}
+
+// Add returns a+b, or ErrAmountOverflow if it does not fit in an int64.
+func (a Amount) Add(b Amount) (Amount, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, ErrAmountOverflow
+	}
+	return sum, nil
+}
+
+// Sub returns a-b, or ErrAmountOverflow if it does not fit in an int64.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	diff := a - b
+	if (b > 0 && diff > a) || (b < 0 && diff < a) {
+		return 0, ErrAmountOverflow
+	}
+	return diff, nil
+}
+
+// Mul returns a*n, or ErrAmountOverflow if it does not fit in an int64.
+func (a Amount) Mul(n int64) (Amount, error) {
+	if a == 0 || n == 0 {
+		return 0, nil
+	}
+	product := a * Amount(n)
+	if product/Amount(n) != a || (n == -1 && a == math.MinInt64) {
+		return 0, ErrAmountOverflow
+	}
+	return product, nil
+}
+
+// Unit is a bitcoin denomination used when parsing amounts.
+type Unit int
+
+const (
+	// Satoshi is the smallest bitcoin denomination.
+	Satoshi Unit = iota
+
+	// MilliBTC is one thousandth of a bitcoin, 100,000 satoshi.
+	MilliBTC
+
+	// BTC is one bitcoin, 100,000,000 satoshi.
+	BTC
+)
+
+// decimals returns the number of decimal places a unit has in satoshi.
+func (u Unit) decimals() int {
+	switch u {
+	case MilliBTC:
+		return 5
+	case BTC:
+		return 8
+	default:
+		return 0
+	}
+}
+
+func (u Unit) String() string {
+	switch u {
+	case Satoshi:
+		return "sat"
+	case MilliBTC:
+		return "mbtc"
+	case BTC:
+		return "btc"
+	default:
+		return fmt.Sprintf("Unit(%d)", int(u))
+	}
+}
+
+// ParseUnit returns the unit named by s which can be one of sat, mbtc or btc.
+func ParseUnit(s string) (Unit, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "sat", "sats", "satoshi":
+		return Satoshi, nil
+	case "mbtc":
+		return MilliBTC, nil
+	case "btc":
+		return BTC, nil
+	default:
+		return 0, fmt.Errorf("unknown unit %q", s)
+	}
+}
+
+// ParseBTC parses s, a value in bitcoin, and returns its value in satoshi. See
+// ParseAmount for the accepted formats.
+func ParseBTC(s string) (Amount, error) {
+	return ParseAmount(s, BTC)
+}
+
+// ParseAmount parses s, a value denominated in unit, and returns its value in
+// satoshi. Either "." or "," is accepted as the decimal separator so "0,001"
+// and "0.001" are equivalent. Digit grouping is not accepted and any value
+// which could be read as either a decimal or a grouped number, such as "1,000"
+// or "1.000", is rejected with ErrAmbiguousAmount. Write "1" or "1000" instead.
+func ParseAmount(s string, unit Unit) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty", ErrInvalidAmount)
+	}
+
+	sep := strings.IndexAny(s, ".,")
+	if sep >= 0 && strings.LastIndexAny(s, ".,") != sep {
+		return 0, fmt.Errorf("%w: %q has more than one separator",
+			ErrAmbiguousAmount, s)
+	}
+
+	whole, frac := s, ""
+	if sep >= 0 {
+		whole, frac = s[:sep], s[sep+1:]
+		if whole == "" && frac == "" {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	// A separator followed by exactly three digits could be a thousands
+	// separator unless the whole part is zero.
+	if len(frac) == 3 && strings.TrimLeft(whole, "0") != "" {
+		return 0, fmt.Errorf("%w: %q", ErrAmbiguousAmount, s)
+	}
+
+	decimals := unit.decimals()
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > decimals {
+		return 0, fmt.Errorf("%w: %q is more precise than one satoshi",
+			ErrInvalidAmount, s)
+	}
+	digits := whole + frac + strings.Repeat("0", decimals-len(frac))
+
+	var value int64
+	for _, d := range digits {
+		n := int64(d - '0')
+		if value > (math.MaxInt64-n)/10 {
+			return 0, fmt.Errorf("%w: %q is too large", ErrInvalidAmount, s)
+		}
+		value = value*10 + n
+	}
+	return Amount(value), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+package client_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestParseAmount(t *testing.T) {
+
+	tests := []struct {
+		in   string
+		unit client.Unit
+		want client.Amount
+		err  error
+	}{
+		{"0.001", client.BTC, 100000, nil},
+		{"0,001", client.BTC, 100000, nil},
+		{",5", client.BTC, 50000000, nil},
+		{"1", client.BTC, 100000000, nil},
+		{"1.5", client.MilliBTC, 150000, nil},
+		{"0.00000001", client.BTC, 1, nil},
+		{"1000", client.Satoshi, 1000, nil},
+		{" 12 ", client.Satoshi, 12, nil},
+		{"1,000", client.BTC, 0, client.ErrAmbiguousAmount},
+		{"1.000", client.Satoshi, 0, client.ErrAmbiguousAmount},
+		{"1,000.50", client.BTC, 0, client.ErrAmbiguousAmount},
+		{"0.000000001", client.BTC, 0, client.ErrInvalidAmount},
+		{"1.5", client.Satoshi, 0, client.ErrInvalidAmount},
+		{"-1", client.BTC, 0, client.ErrInvalidAmount},
+		{"", client.BTC, 0, client.ErrInvalidAmount},
+		{".", client.BTC, 0, client.ErrInvalidAmount},
+		{"1e3", client.BTC, 0, client.ErrInvalidAmount},
+		{"999999999999", client.BTC, 0, client.ErrInvalidAmount},
+	}
+
+	for _, test := range tests {
+		got, err := client.ParseAmount(test.in, test.unit)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("%q %v: unexpected error %v", test.in, test.unit, err)
+		}
+		if got != test.want {
+			t.Fatalf("%q %v: expected %d got %d", test.in, test.unit, test.want, got)
+		}
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+
+	for s, want := range map[string]client.Unit{
+		"sat":  client.Satoshi,
+		"mBTC": client.MilliBTC,
+		"BTC":  client.BTC,
+	} {
+		got, err := client.ParseUnit(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("%q: expected %v got %v", s, want, got)
+		}
+	}
+
+	if _, err := client.ParseUnit("bits"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestAmount(t *testing.T) {
+
+	for a, want := range map[client.Amount]string{
+		10000:            "0.00010000 BTC",
+		0:                "0.00000000 BTC",
+		-150000000:       "-1.50000000 BTC",
+		client.MaxSupply: "21000000.00000000 BTC",
+		math.MinInt64:    "-92233720368.54775808 BTC",
+	} {
+		if got := a.String(); got != want {
+			t.Errorf("%d: expected %s got %s", int64(a), want, got)
+		}
+	}
+
+	a, err := client.FromBTC(0.0001)
+	if err != nil || a != 10000 || a.ToBTC() != 0.0001 {
+		t.Fatal("unexpected conversion", a, err)
+	}
+	if _, err := client.FromBTC(math.NaN()); !errors.Is(err,
+		client.ErrInvalidAmount) {
+		t.Fatal("expected ErrInvalidAmount", err)
+	}
+	if _, err := client.FromBTC(1e12); !errors.Is(err,
+		client.ErrInvalidAmount) {
+		t.Fatal("expected ErrInvalidAmount", err)
+	}
+
+	if sum, err := client.Amount(5).Add(7); err != nil || sum != 12 {
+		t.Fatal("unexpected sum", sum, err)
+	}
+	if diff, err := client.Amount(5).Sub(7); err != nil || diff != -2 {
+		t.Fatal("unexpected difference", diff, err)
+	}
+	if product, err := client.Amount(5).Mul(-7); err != nil ||
+		product != -35 {
+		t.Fatal("unexpected product", product, err)
+	}
+	overflows := []func() (client.Amount, error){
+		func() (client.Amount, error) {
+			return client.Amount(math.MaxInt64).Add(1)
+		},
+		func() (client.Amount, error) {
+			return client.Amount(math.MinInt64).Sub(1)
+		},
+		func() (client.Amount, error) {
+			return client.Amount(math.MaxInt64 / 2).Mul(3)
+		},
+		func() (client.Amount, error) {
+			return client.Amount(math.MinInt64).Mul(-1)
+		},
+	}
+	for i, overflow := range overflows {
+		if _, err := overflow(); err != client.ErrAmountOverflow {
+			t.Errorf("%d: expected ErrAmountOverflow, got %v", i, err)
+		}
+	}
+
+	// Amounts are encoded in satoshi, as RTWire does.
+	b, err := json.Marshal(client.Account{ID: 1, Balance: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"id":1,"balance":10000}` {
+		t.Fatal("unexpected JSON", string(b))
+	}
+	var acc client.Account
+	if err := json.Unmarshal(b, &acc); err != nil || acc.Balance != 10000 {
+		t.Fatal("unexpected account", acc, err)
+	}
+}
@@ -0,0 +1,276 @@
+// Package audit keeps a tamper-evident record of the mutating calls, such as
+// transfers, debits and hook changes, made through a client.
+//
+// Calls are recorded by the middleware of a Log and periodically sealed into
+// bundles. Each bundle holds the SHA-256 hash of the bundle before it, so that
+// removing, reordering or editing a bundle breaks the chain, and may be
+// signed so that the chain cannot be rewritten without the key:
+//
+//	log := audit.NewLog(sink, audit.Ed25519(key))
+//	cl = client.WithCallMiddleware(cl, log.Middleware())
+//	go log.Run(ctx, time.Hour)
+//
+// Bundles read back from the sink are checked with Verify.
+package audit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// ErrBrokenChain is matched by the error of Verify for bundles that are
+// missing, out of order, altered or not signed by the key.
+var ErrBrokenChain = errors.New("audit chain broken")
+
+// mutating lists the prefixes of the names of Client methods that change
+// state at RTWire.
+var mutating = []string{"Create", "Update", "Delete", "Close", "Transfer",
+	"Debit", "Fund"}
+
+// Record is a mutating call made through a client.
+type Record struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+
+	// Args is the JSON encoding of the call's Args, kept encoded so that a
+	// bundle hashes the same once read back from its sink.
+	Args json.RawMessage `json:"args"`
+
+	// Error is the error the call returned, if any. A failed call is recorded
+	// as it may still have reached RTWire.
+	Error string `json:"error,omitempty"`
+}
+
+// Bundle is a sealed sequence of records.
+type Bundle struct {
+	// Seq numbers bundles from one.
+	Seq     int64     `json:"seq"`
+	Opened  time.Time `json:"opened"`
+	Sealed  time.Time `json:"sealed"`
+	Records []Record  `json:"records"`
+
+	// Prev is the Hash of the previous bundle, empty for the first.
+	Prev string `json:"prev"`
+
+	// Hash is the hex SHA-256 hash of the bundle's other fields, excluding
+	// Signature.
+	Hash string `json:"hash"`
+
+	// Signature signs Hash, if the log has a Signer.
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// digest returns the hash of b, computed over its JSON encoding without Hash
+// and Signature.
+func (b Bundle) digest() ([]byte, error) {
+	b.Hash, b.Signature = "", nil
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// Signer signs the hash of a bundle.
+type Signer interface {
+	Sign(digest []byte) ([]byte, error)
+}
+
+// Ed25519 returns a Signer signing with key.
+func Ed25519(key ed25519.PrivateKey) Signer {
+	return ed25519Signer{key}
+}
+
+type ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+func (s ed25519Signer) Sign(digest []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, digest), nil
+}
+
+// Sink stores sealed bundles, for example in write-once object storage.
+type Sink interface {
+	Store(ctx context.Context, b Bundle) error
+}
+
+// SinkFunc adapts a function to the Sink interface.
+type SinkFunc func(ctx context.Context, b Bundle) error
+
+// Store calls f(ctx, b).
+func (f SinkFunc) Store(ctx context.Context, b Bundle) error {
+	return f(ctx, b)
+}
+
+// Log records mutating calls and seals them into bundles stored in a Sink.
+// It is safe for concurrent use.
+type Log struct {
+	sink   Sink
+	signer Signer
+
+	// sealing serialises Seal, keeping the chain in order.
+	sealing sync.Mutex
+
+	mu      sync.Mutex
+	seq     int64
+	prev    string
+	opened  time.Time
+	records []Record
+}
+
+// NewLog returns a Log storing bundles in sink, signed by signer unless it is
+// nil. The chain starts a new sequence unless continued with Resume.
+func NewLog(sink Sink, signer Signer) *Log {
+	return &Log{sink: sink, signer: signer, opened: time.Now().UTC()}
+}
+
+// Resume continues the chain after last, the last bundle stored, for example
+// when a process restarts.
+func (l *Log) Resume(last Bundle) {
+	l.mu.Lock()
+	l.seq, l.prev = last.Seq, last.Hash
+	l.mu.Unlock()
+}
+
+// Middleware returns a client.CallMiddleware recording every mutating call
+// once it returns.
+func (l *Log) Middleware() client.CallMiddleware {
+	return func(next client.CallHandler) client.CallHandler {
+		return func(ctx context.Context, call client.Call) error {
+			err := next(ctx, call)
+			if isMutating(call.Method) {
+				r := Record{Time: time.Now().UTC(), Method: call.Method,
+					Args: encodeArgs(call.Args)}
+				if err != nil {
+					r.Error = err.Error()
+				}
+				l.mu.Lock()
+				l.records = append(l.records, r)
+				l.mu.Unlock()
+			}
+			return err
+		}
+	}
+}
+
+// encodeArgs returns the JSON encoding of args, or of their formatting if
+// they can't be encoded.
+func encodeArgs(args []interface{}) json.RawMessage {
+	data, err := json.Marshal(args)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(args...))
+	}
+	return data
+}
+
+func isMutating(method string) bool {
+	for _, prefix := range mutating {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Seal seals the records made since the last bundle, which may be none, into
+// a bundle and stores it. If the bundle cannot be signed or stored its
+// records are kept for the next Seal and the chain does not advance.
+func (l *Log) Seal(ctx context.Context) (Bundle, error) {
+	l.sealing.Lock()
+	defer l.sealing.Unlock()
+
+	l.mu.Lock()
+	b := Bundle{
+		Seq:     l.seq + 1,
+		Opened:  l.opened,
+		Sealed:  time.Now().UTC(),
+		Records: l.records,
+		Prev:    l.prev,
+	}
+	l.records = nil
+	l.mu.Unlock()
+
+	if b.Records == nil {
+		b.Records = []Record{}
+	}
+	if err := l.seal(ctx, &b); err != nil {
+		l.mu.Lock()
+		l.records = append(b.Records, l.records...)
+		l.mu.Unlock()
+		return Bundle{}, fmt.Errorf("seal bundle %d: %w", b.Seq, err)
+	}
+
+	l.mu.Lock()
+	l.seq, l.prev, l.opened = b.Seq, b.Hash, b.Sealed
+	l.mu.Unlock()
+	return b, nil
+}
+
+func (l *Log) seal(ctx context.Context, b *Bundle) error {
+	digest, err := b.digest()
+	if err != nil {
+		return err
+	}
+	b.Hash = hex.EncodeToString(digest)
+	if l.signer != nil {
+		if b.Signature, err = l.signer.Sign(digest); err != nil {
+			return err
+		}
+	}
+	return l.sink.Store(ctx, *b)
+}
+
+// Run seals a bundle every interval until ctx is done, returning the first
+// error from Seal. Records made after the last bundle can be sealed with
+// Seal when Run returns.
+func (l *Log) Run(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+			if _, err := l.Seal(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Verify checks that bundles, in order, form an unbroken chain and, unless
+// key is nil, that each is signed by key. The first bundle may continue an
+// earlier chain.
+func Verify(bundles []Bundle, key ed25519.PublicKey) error {
+	for i, b := range bundles {
+		if i > 0 && (b.Seq != bundles[i-1].Seq+1 ||
+			b.Prev != bundles[i-1].Hash) {
+			return fmt.Errorf("%w: bundle %d does not follow bundle %d",
+				ErrBrokenChain, b.Seq, bundles[i-1].Seq)
+		}
+		digest, err := b.digest()
+		if err != nil {
+			return err
+		}
+		if hex.EncodeToString(digest) != b.Hash {
+			return fmt.Errorf("%w: bundle %d altered", ErrBrokenChain,
+				b.Seq)
+		}
+		if key != nil && !ed25519.Verify(key, digest, b.Signature) {
+			return fmt.Errorf("%w: bundle %d not signed by key",
+				ErrBrokenChain, b.Seq)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package audit_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/audit"
+	"github.com/rtwire/go/client/clientmock"
+)
+
+func TestLog(t *testing.T) {
+
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored [][]byte
+	failing := false
+	sink := audit.SinkFunc(func(ctx context.Context, b audit.Bundle) error {
+		if failing {
+			return errors.New("unavailable")
+		}
+		data, err := json.Marshal(b)
+		stored = append(stored, data)
+		return err
+	})
+	log := audit.NewLog(sink, audit.Ed25519(key))
+
+	m := &clientmock.Client{
+		TransferFunc: func(ctx context.Context, txID, from, to int64,
+			value client.Amount, options ...client.Option) (
+			client.Transaction, error) {
+			return client.Transaction{ID: txID}, nil
+		},
+	}
+	cl := client.WithCallMiddleware(m, log.Middleware())
+	ctx := context.Background()
+
+	cl.Transfer(1, 2, 3, 10)
+	cl.Account(2)
+	cl.Debit(4, 2, "1BoatSLRHtKNngkdXEeobR76b53LETtpyT", 5)
+	b, err := log.Seal(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Records) != 2 || string(b.Records[0].Args) != "[1,2,3,10]" ||
+		b.Records[1].Error == "" {
+		t.Fatalf("expected mutating calls recorded %+v", b.Records)
+	}
+
+	// Records are kept for the next bundle if the sink fails.
+	cl.Transfer(5, 2, 3, 10)
+	failing = true
+	if _, err := log.Seal(ctx); err == nil {
+		t.Fatal("expected sink error")
+	}
+	failing = false
+	if b, err = log.Seal(ctx); err != nil || b.Seq != 2 ||
+		len(b.Records) != 1 {
+		t.Fatalf("expected failed bundle sealed again %+v %v", b, err)
+	}
+
+	var bundles []audit.Bundle
+	for _, data := range stored {
+		var b audit.Bundle
+		if err := json.Unmarshal(data, &b); err != nil {
+			t.Fatal(err)
+		}
+		bundles = append(bundles, b)
+	}
+	if err := audit.Verify(bundles, pub); err != nil {
+		t.Fatal(err)
+	}
+
+	bundles[0].Records[0].Method = "Account"
+	if err := audit.Verify(bundles, pub); !errors.Is(err,
+		audit.ErrBrokenChain) {
+		t.Fatal("expected altered bundle to be detected", err)
+	}
+	if err := audit.Verify(bundles[1:2], nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := audit.Verify([]audit.Bundle{bundles[1], bundles[1]},
+		nil); !errors.Is(err, audit.ErrBrokenChain) {
+		t.Fatal("expected repeated bundle to be detected", err)
+	}
+}
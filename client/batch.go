@@ -0,0 +1,215 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// defaultBatchConcurrency is the number of requests CreateAccounts makes at
+// once unless set with WithBatchConcurrency.
+const defaultBatchConcurrency = 4
+
+// WithBatchConcurrency sets the number of requests CreateAccounts makes at
+// once. Requests still pass through any limiter set with WithRateLimit.
+func WithBatchConcurrency(n int) ClientOption {
+	return func(c *client) {
+		c.concurrency = n
+	}
+}
+
+// BatchStatus is the outcome of a single item in a batch operation.
+type BatchStatus int
+
+const (
+	// BatchNotAttempted means the item was not sent, for example because an
+	// earlier item failed.
+	BatchNotAttempted BatchStatus = iota
+
+	// BatchSucceeded means the item was applied.
+	BatchSucceeded
+
+	// BatchFailed means the item was sent and failed.
+	BatchFailed
+
+	// BatchReverted means the item was applied and then undone because
+	// another item of an all-or-nothing batch failed.
+	BatchReverted
+)
+
+func (s BatchStatus) String() string {
+	switch s {
+	case BatchNotAttempted:
+		return "not attempted"
+	case BatchSucceeded:
+		return "succeeded"
+	case BatchFailed:
+		return "failed"
+	case BatchReverted:
+		return "reverted"
+	default:
+		return fmt.Sprintf("BatchStatus(%d)", int(s))
+	}
+}
+
+// BatchItem is the result of one item in a batch operation. Index is the
+// position of the item in the batch request.
+type BatchItem struct {
+	Index  int
+	Status BatchStatus
+	TxID   int64
+	Err    error
+}
+
+// BatchResult is returned from batch operations and holds one BatchItem per
+// requested item, in request order. DebitMany returns a Transaction instead:
+// its outputs are paid by a single bitcoin transaction, so they succeed or
+// fail together and there is no partial failure to report.
+type BatchResult struct {
+	Items []BatchItem
+
+	// err is set when the batch was refused as a whole, for example because
+	// its size was invalid, and there are no items.
+	err error
+}
+
+func newBatchResult(n int) BatchResult {
+	items := make([]BatchItem, n)
+	for i := range items {
+		items[i].Index = i
+	}
+	return BatchResult{Items: items}
+}
+
+func (r BatchResult) set(i int, txID int64, err error) {
+	r.Items[i].TxID = txID
+	r.Items[i].Err = err
+	if err != nil {
+		r.Items[i].Status = BatchFailed
+	} else {
+		r.Items[i].Status = BatchSucceeded
+	}
+}
+
+// Failed returns the indexes of the items that did not succeed, including
+// those that were not attempted.
+func (r BatchResult) Failed() []int {
+	var failed []int
+	for _, item := range r.Items {
+		if item.Status != BatchSucceeded {
+			failed = append(failed, item.Index)
+		}
+	}
+	return failed
+}
+
+// Err returns nil if every item succeeded, otherwise a *MultiError holding
+// every item that did not succeed. If the batch was refused as a whole, for
+// example with a *ValidationError for a negative size, that error is returned.
+func (r BatchResult) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+	multi := &MultiError{Total: len(r.Items)}
+	for _, i := range failed {
+		multi.Items = append(multi.Items, r.Items[i])
+	}
+	return multi
+}
+
+// MultiError is the error of a batch operation whose items did not all
+// succeed. Items holds the BatchItem of each such item, in request order, so
+// that failures can be mapped back to the requests by their Index. It
+// marshals to JSON with each item's error as its message, RTWire error code
+// and invalid field, where known.
+type MultiError struct {
+	// Total is the number of items in the batch.
+	Total int
+	Items []BatchItem
+}
+
+// Error describes the first item that did not succeed.
+func (e *MultiError) Error() string {
+	if len(e.Items) == 0 {
+		return fmt.Sprintf("0 of %d items failed", e.Total)
+	}
+	first := e.Items[0]
+	if first.Err == nil {
+		return fmt.Sprintf("%d of %d items failed: item %d %v",
+			len(e.Items), e.Total, first.Index, first.Status)
+	}
+	return fmt.Sprintf("%d of %d items failed: item %d: %v",
+		len(e.Items), e.Total, first.Index, first.Err)
+}
+
+// Unwrap returns the errors of the items, so that errors.Is and errors.As
+// match an error of any item.
+func (e *MultiError) Unwrap() []error {
+	var errs []error
+	for _, item := range e.Items {
+		if item.Err != nil {
+			errs = append(errs, item.Err)
+		}
+	}
+	return errs
+}
+
+type itemErrorJSON struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	TxID   int64  `json:"txID,omitempty"`
+	Error  string `json:"error,omitempty"`
+	Code   string `json:"code,omitempty"`
+	Field  string `json:"field,omitempty"`
+}
+
+// MarshalJSON encodes e as an object holding total and a list of items, each
+// with its index, status and, if set, txID, error, code and field.
+func (e *MultiError) MarshalJSON() ([]byte, error) {
+	items := make([]itemErrorJSON, len(e.Items))
+	for i, item := range e.Items {
+		j := itemErrorJSON{
+			Index:  item.Index,
+			Status: item.Status.String(),
+			TxID:   item.TxID,
+		}
+		if item.Err != nil {
+			j.Error = item.Err.Error()
+		}
+		var apiErr *APIError
+		if errors.As(item.Err, &apiErr) {
+			j.Code = apiErr.Code
+		}
+		var vErr *ValidationError
+		if errors.As(item.Err, &vErr) {
+			j.Field = vErr.Field
+		}
+		items[i] = j
+	}
+	return json.Marshal(struct {
+		Total int             `json:"total"`
+		Items []itemErrorJSON `json:"items"`
+	}{e.Total, items})
+}
+
+// Retry calls fn for each item that did not succeed and returns a new result
+// with those items updated. Items that already succeeded are left untouched.
+// A batch refused as a whole has no items to retry and is returned unchanged,
+// so that Err still reports why it was refused.
+func (r BatchResult) Retry(
+	fn func(index int) (txID int64, err error)) BatchResult {
+	if r.err != nil {
+		return r
+	}
+	retried := BatchResult{Items: make([]BatchItem, len(r.Items))}
+	copy(retried.Items, r.Items)
+	for _, i := range r.Failed() {
+		txID, err := fn(i)
+		retried.set(i, txID, err)
+	}
+	return retried
+}
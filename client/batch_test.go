@@ -0,0 +1,188 @@
+package client_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestCreateAddressesRetry(t *testing.T) {
+
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.Contains(r.URL.Path, "/accounts/2/") && failing {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"type": "errors",
+					"payload": [{"message": "unavailable"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"type": "addresses",
+				"payload": [{"address": "addr"}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	addrs, result := cl.CreateAddresses([]int64{1, 2, 3})
+	if result.Err() == nil {
+		t.Fatal("expected error")
+	}
+
+	failed := result.Failed()
+	if len(failed) != 1 || failed[0] != 1 {
+		t.Fatalf("expected item 1 to fail %+v", failed)
+	}
+	if result.Items[1].Status != client.BatchFailed {
+		t.Fatal("expected failed status")
+	}
+	if addrs[0] != "addr" || addrs[1] != "" || addrs[2] != "addr" {
+		t.Fatalf("incorrect addresses %v", addrs)
+	}
+
+	failing = false
+	retries := 0
+	result = result.Retry(func(i int) (int64, error) {
+		retries++
+		addr, err := cl.CreateAddress(int64(i + 1))
+		addrs[i] = addr
+		return 0, err
+	})
+	if err := result.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if retries != 1 {
+		t.Fatal("expected only the failed item to be retried", retries)
+	}
+	if addrs[1] != "addr" {
+		t.Fatal("address not set on retry")
+	}
+}
+
+func TestRetryRefusedBatch(t *testing.T) {
+
+	cl := client.New(http.DefaultClient, client.MainNetURL, "user", "pass")
+	_, result := cl.CreateAccounts(-1)
+	var verr *client.ValidationError
+	if !errors.As(result.Err(), &verr) {
+		t.Fatal("expected refused batch", result.Err())
+	}
+
+	retries := 0
+	result = result.Retry(func(i int) (int64, error) {
+		retries++
+		return 0, nil
+	})
+	if !errors.As(result.Err(), &verr) || retries != 0 {
+		t.Fatal("expected the refusal to be kept", result.Err(), retries)
+	}
+}
+
+func TestCreateAccounts(t *testing.T) {
+
+	var (
+		mu                  sync.Mutex
+		id, active, maxSeen int
+	)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			id++
+			n := id
+			active++
+			if active > maxSeen {
+				maxSeen = active
+			}
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				active--
+				mu.Unlock()
+			}()
+
+			w.Header().Set("Content-Type", "application/json")
+			if n == 5 {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `{"type": "errors",
+					"payload": [{"message": "unavailable"}]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"type": "accounts",
+				"payload": [{"id": %d}]}`, n)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithBatchConcurrency(3))
+
+	accs, result := cl.CreateAccounts(20)
+	if len(accs) != 20 {
+		t.Fatal("expected 20 accounts", len(accs))
+	}
+	if failed := result.Failed(); len(failed) != 1 {
+		t.Fatalf("expected one failure %v", failed)
+	}
+	ids := map[int64]bool{}
+	for i, acc := range accs {
+		if result.Items[i].Status == client.BatchSucceeded {
+			ids[acc.ID] = true
+		}
+	}
+	if len(ids) != 19 || ids[5] {
+		t.Fatalf("expected 19 distinct accounts %v", ids)
+	}
+	if maxSeen > 3 {
+		t.Fatal("expected at most three requests at once", maxSeen)
+	}
+}
+
+func TestMultiError(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.Contains(r.URL.Path, "/accounts/2/") {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"type": "errors", "payload": [{
+					"code": "not found", "message": "no account"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"type": "addresses",
+				"payload": [{"address": "addr"}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	_, result := cl.CreateAddresses([]int64{1, 2, 3})
+	err := result.Err()
+	var multi *client.MultiError
+	if !errors.As(err, &multi) || multi.Total != 3 ||
+		len(multi.Items) != 1 || multi.Items[0].Index != 1 {
+		t.Fatal("expected item 1 in the error", err)
+	}
+	if !errors.Is(err, client.ErrNotFound) {
+		t.Fatal("expected the item's error to match", err)
+	}
+
+	b, err := json.Marshal(multi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"total":3,"items":[{"index":1,"status":"failed",` +
+		`"error":"no account","code":"not found"}]}`
+	if string(b) != expected {
+		t.Fatalf("expected %s got %s", expected, b)
+	}
+}
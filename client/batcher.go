@@ -0,0 +1,128 @@
+package client
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Batcher groups hook events into batches before passing them to a handler,
+// for example to write many events to a slow store at once. A batch is handled
+// once it holds size events or window has passed since its first event,
+// whichever comes first.
+//
+// Add, and so ServeHTTP, blocks until every event passed to it has been
+// handled. A hook delivery is therefore only acknowledged to RTWire once its
+// events are handled and a failed batch causes RTWire to deliver them again.
+type Batcher struct {
+	handler func([]TransactionEvent) error
+	size    int
+	window  time.Duration
+
+	mu      sync.Mutex
+	current *batch
+}
+
+type batch struct {
+	events []TransactionEvent
+	timer  *time.Timer
+	done   chan struct{}
+	err    error
+}
+
+// NewBatcher creates a Batcher calling handler with at most size events at a
+// time, waiting at most window for a batch to fill.
+func NewBatcher(size int, window time.Duration,
+	handler func([]TransactionEvent) error) *Batcher {
+	if size < 1 {
+		size = 1
+	}
+	return &Batcher{
+		handler: handler,
+		size:    size,
+		window:  window,
+	}
+}
+
+// Add adds events to the current batch and waits for them to be handled. The
+// first error from the handler for the batches containing events is returned.
+func (b *Batcher) Add(events []TransactionEvent) error {
+	var batches []*batch
+
+	b.mu.Lock()
+	for _, event := range events {
+		if b.current == nil {
+			cur := &batch{done: make(chan struct{})}
+			cur.timer = time.AfterFunc(b.window, func() { b.flushBatch(cur) })
+			b.current = cur
+		}
+		cur := b.current
+		cur.events = append(cur.events, event)
+		if len(batches) == 0 || batches[len(batches)-1] != cur {
+			batches = append(batches, cur)
+		}
+		if len(cur.events) >= b.size {
+			b.current = nil
+			cur.timer.Stop()
+			go b.handle(cur)
+		}
+	}
+	b.mu.Unlock()
+
+	var err error
+	for _, cur := range batches {
+		<-cur.done
+		if err == nil {
+			err = cur.err
+		}
+	}
+	return err
+}
+
+// Flush handles the current batch immediately, for example on shutdown.
+func (b *Batcher) Flush() {
+	b.mu.Lock()
+	cur := b.current
+	b.mu.Unlock()
+	if cur != nil {
+		b.flushBatch(cur)
+		<-cur.done
+	}
+}
+
+// flushBatch handles cur if it is still the current batch.
+func (b *Batcher) flushBatch(cur *batch) {
+	b.mu.Lock()
+	if b.current != cur {
+		b.mu.Unlock()
+		return
+	}
+	b.current = nil
+	cur.timer.Stop()
+	b.mu.Unlock()
+	b.handle(cur)
+}
+
+func (b *Batcher) handle(cur *batch) {
+	cur.err = b.handler(cur.events)
+	close(cur.done)
+}
+
+// ServeHTTP receives RTWire hook deliveries and adds their transaction events
+// to the batcher. Other kinds of event are acknowledged and dropped. A 500
+// response is returned if the events could not be handled so that RTWire
+// retries the delivery.
+func (b *Batcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	events, err := UnmarshalEvents(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	txEvents := transactionEvents(events)
+	if len(txEvents) == 0 {
+		return
+	}
+	if err := b.Add(txEvents); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,118 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestBatcher(t *testing.T) {
+
+	var (
+		mu      sync.Mutex
+		batches [][]client.TransactionEvent
+	)
+	b := client.NewBatcher(3, 20*time.Millisecond,
+		func(events []client.TransactionEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			batches = append(batches, events)
+			return nil
+		})
+
+	events := func(n int) []client.TransactionEvent {
+		return make([]client.TransactionEvent, n)
+	}
+
+	// Five events fill one batch and start another flushed by the window.
+	start := time.Now()
+	if err := b.Add(events(5)); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Fatal("expected to wait for the window")
+	}
+
+	if len(batches) != 2 || len(batches[0]) != 3 || len(batches[1]) != 2 {
+		t.Fatalf("unexpected batches %v", batches)
+	}
+
+	// Concurrent deliveries share a batch.
+	batches = nil
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := b.Add(events(1)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(batches) != 1 {
+		t.Fatalf("expected one batch %v", batches)
+	}
+}
+
+func TestBatcherError(t *testing.T) {
+
+	errStore := errors.New("store unavailable")
+	b := client.NewBatcher(10, time.Hour,
+		func(events []client.TransactionEvent) error {
+			return errStore
+		})
+
+	done := make(chan error)
+	go func() {
+		done <- b.Add(make([]client.TransactionEvent, 1))
+	}()
+
+	// Give Add time to queue its event before flushing.
+	time.Sleep(10 * time.Millisecond)
+	b.Flush()
+	if err := <-done; err != errStore {
+		t.Fatal("expected store error", err)
+	}
+}
+
+func TestBatcherServeHTTP(t *testing.T) {
+
+	var got []client.TransactionEvent
+	b := client.NewBatcher(1, time.Hour,
+		func(events []client.TransactionEvent) error {
+			got = append(got, events...)
+			return nil
+		})
+
+	deliver := func(body string) int {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		b.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Other kinds of event are acknowledged so RTWire doesn't retry them.
+	if code := deliver(`{"type": "accounts",
+		"payload": [{"id": 1}]}`); code != http.StatusOK {
+		t.Fatal("expected status ok", code)
+	}
+	if len(got) != 0 {
+		t.Fatal("expected no transaction events", got)
+	}
+
+	if code := deliver(`{"type": "transactions",
+		"payload": [{"id": 2}]}`); code != http.StatusOK {
+		t.Fatal("expected status ok", code)
+	}
+	if len(got) != 1 || got[0].ID != 2 {
+		t.Fatal("expected transaction event", got)
+	}
+}
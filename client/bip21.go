@@ -0,0 +1,126 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPaymentURI is returned from ParsePaymentURI for URIs that are not
+// BIP 21 bitcoin payment URIs or that require a parameter it doesn't support.
+var ErrInvalidPaymentURI = errors.New("invalid payment URI")
+
+// PaymentURI is a BIP 21 payment URI, such as
+// bitcoin:1BoatSLRHtKNngkdXEeobR76b53LETtpyT?amount=0.001&label=Shop, which
+// wallets open to prefill a payment, for example from a QR code at checkout.
+type PaymentURI struct {
+	Address string
+
+	// Amount is the amount requested in satoshi, or zero if none is.
+	Amount Amount
+
+	// Label names the recipient and Message describes the payment.
+	Label   string
+	Message string
+}
+
+// String returns u encoded as a bitcoin: URI. The amount is written in
+// bitcoin with a point and no more decimals than needed, as BIP 21 requires.
+func (u PaymentURI) String() string {
+	var b strings.Builder
+	b.WriteString("bitcoin:")
+	b.WriteString(u.Address)
+	sep := byte('?')
+	param := func(key, value string) {
+		b.WriteByte(sep)
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(value)
+		sep = '&'
+	}
+	if u.Amount > 0 {
+		param("amount", formatBTC(u.Amount))
+	}
+	if u.Label != "" {
+		param("label", escapeURIParam(u.Label))
+	}
+	if u.Message != "" {
+		param("message", escapeURIParam(u.Message))
+	}
+	return b.String()
+}
+
+// formatBTC formats satoshi as bitcoin, for example 100000 as "0.001".
+func formatBTC(satoshi Amount) string {
+	s := fmt.Sprintf("%d.%08d", satoshi/1e8, satoshi%1e8)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// escapeURIParam percent encodes s. Spaces are encoded as %20 rather than +,
+// which BIP 21 does not give a meaning.
+func escapeURIParam(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// ParsePaymentURI parses a BIP 21 payment URI, checking the address and the
+// amount. Parameters other than amount, label and message are ignored unless
+// their name starts with "req-", which BIP 21 requires a wallet to refuse.
+func ParsePaymentURI(s string) (PaymentURI, error) {
+	scheme, rest, ok := strings.Cut(s, ":")
+	if !ok || !strings.EqualFold(scheme, "bitcoin") {
+		return PaymentURI{}, fmt.Errorf("%w: not a bitcoin URI",
+			ErrInvalidPaymentURI)
+	}
+	addr, rawQuery, _ := strings.Cut(rest, "?")
+	if err := checkAddress(addr); err != nil {
+		return PaymentURI{}, fmt.Errorf("%w: address %s: %v",
+			ErrInvalidPaymentURI, addr, err)
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return PaymentURI{}, fmt.Errorf("%w: %v", ErrInvalidPaymentURI, err)
+	}
+
+	u := PaymentURI{
+		Address: addr,
+		Label:   query.Get("label"),
+		Message: query.Get("message"),
+	}
+	for key := range query {
+		if strings.HasPrefix(key, "req-") {
+			return PaymentURI{}, fmt.Errorf("%w: unsupported parameter %s",
+				ErrInvalidPaymentURI, key)
+		}
+	}
+	if amount := query.Get("amount"); amount != "" {
+		if u.Amount, err = parseURIAmount(amount); err != nil {
+			return PaymentURI{}, fmt.Errorf("%w: amount %q: %v",
+				ErrInvalidPaymentURI, amount, err)
+		}
+	}
+	return u, nil
+}
+
+// parseURIAmount parses a BIP 21 amount, a decimal number of bitcoin with a
+// point as its separator, and returns it in satoshi. Unlike ParseBTC it
+// accepts values such as "1.000", which are unambiguous in a URI.
+func parseURIAmount(s string) (Amount, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole+frac == "" || !isDigits(whole) || !isDigits(frac) ||
+		len(frac) > 8 {
+		return 0, ErrInvalidAmount
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	// No amount can exceed the 21 million bitcoin that will ever exist.
+	btc, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || btc > 21e6 {
+		return 0, ErrInvalidAmount
+	}
+	sats, _ := strconv.ParseInt(frac+strings.Repeat("0", 8-len(frac)), 10,
+		64)
+	return Amount(btc*1e8 + sats), nil
+}
@@ -0,0 +1,79 @@
+package client_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestPaymentURI(t *testing.T) {
+
+	u := client.PaymentURI{
+		Address: "1BoatSLRHtKNngkdXEeobR76b53LETtpyT",
+		Amount:  100000,
+		Label:   "Joe's Shop",
+		Message: "Order 12 & 13",
+	}
+	expected := "bitcoin:1BoatSLRHtKNngkdXEeobR76b53LETtpyT?amount=0.001" +
+		"&label=Joe%27s%20Shop&message=Order%2012%20%26%2013"
+	if u.String() != expected {
+		t.Fatal("unexpected URI", u.String())
+	}
+	parsed, err := client.ParsePaymentURI(u.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != u {
+		t.Fatalf("expected %+v got %+v", u, parsed)
+	}
+
+	amounts := map[client.Amount]string{
+		1:             "0.00000001",
+		100000000:     "1",
+		2150000000:    "21.5",
+		2099999999999: "20999.99999999",
+	}
+	for satoshi, amount := range amounts {
+		u := client.PaymentURI{Address: "a", Amount: satoshi}
+		if u.String() != "bitcoin:a?amount="+amount {
+			t.Fatal("unexpected amount", u.String())
+		}
+	}
+}
+
+func TestParsePaymentURI(t *testing.T) {
+
+	const addr = "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4"
+	tests := []struct {
+		uri    string
+		amount client.Amount
+		valid  bool
+	}{
+		{"bitcoin:" + addr, 0, true},
+		{"BITCOIN:" + addr + "?amount=1.000", 100000000, true},
+		{"bitcoin:" + addr + "?amount=.5&other=x", 50000000, true},
+		{"bitcoin:" + addr + "?amount=0.000000001", 0, false},
+		{"bitcoin:" + addr + "?amount=1,5", 0, false},
+		{"bitcoin:" + addr + "?amount=1e3", 0, false},
+		{"bitcoin:" + addr + "?amount=.", 0, false},
+		{"bitcoin:" + addr + "?req-expiry=10", 0, false},
+		{"bitcoin:1BoatSLRHtKNngkdXEeobR76b53LETtpyU", 0, false},
+		{"litecoin:" + addr, 0, false},
+	}
+	for _, test := range tests {
+		u, err := client.ParsePaymentURI(test.uri)
+		if !test.valid {
+			if !errors.Is(err, client.ErrInvalidPaymentURI) {
+				t.Fatal("expected invalid URI", test.uri, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(test.uri, err)
+		}
+		if u.Address != addr || u.Amount != test.amount {
+			t.Fatalf("unexpected %+v from %s", u, test.uri)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting RTWire while the circuit
+// breaker set with WithCircuitBreaker is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// WithCircuitBreaker stops requests being sent to RTWire after threshold
+// consecutive failures, returning ErrCircuitOpen instead. After cooldown a
+// single probe request is let through: if it succeeds the breaker closes,
+// otherwise it stays open for another cooldown. Network errors and 5xx
+// responses count as failures; other error responses, such as insufficient
+// funds, show RTWire is up and do not.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientOption {
+	return func(c *client) {
+		if threshold < 1 {
+			threshold = 1
+		}
+		c.breaker = &breaker{threshold: threshold, cooldown: cooldown}
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// allow reports whether a request may be sent. When the cooldown has passed
+// the first caller is allowed through as the probe and the rest are refused
+// until the probe completes.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of an allowed request.
+func (b *breaker) record(resp *http.Response, err error) {
+	failed := (err != nil && !isContextErr(err)) ||
+		(resp != nil && resp.StatusCode >= 500)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		if err != nil && b.state == breakerHalfOpen {
+			// The probe was cancelled by its caller so let another through.
+			b.state = breakerOpen
+			return
+		}
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,67 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+
+	requests := 0
+	healthy := false
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if !healthy {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "fees", "payload": []}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithCircuitBreaker(3, 50*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		if _, err := cl.Fees(); err == nil || errors.Is(err,
+			client.ErrCircuitOpen) {
+			t.Fatal("expected server error", err)
+		}
+	}
+	if _, err := cl.Fees(); !errors.Is(err, client.ErrCircuitOpen) {
+		t.Fatal("expected circuit open", err)
+	}
+	if requests != 3 {
+		t.Fatal("expected open circuit to skip the server", requests)
+	}
+
+	// A failed probe reopens the circuit.
+	time.Sleep(60 * time.Millisecond)
+	if _, err := cl.Fees(); errors.Is(err, client.ErrCircuitOpen) {
+		t.Fatal("expected probe", err)
+	}
+	if _, err := cl.Fees(); !errors.Is(err, client.ErrCircuitOpen) {
+		t.Fatal("expected circuit open after failed probe", err)
+	}
+
+	// A successful probe closes it.
+	healthy = true
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		if _, err := cl.Fees(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests != 6 {
+		t.Fatal("unexpected number of requests", requests)
+	}
+}
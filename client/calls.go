@@ -0,0 +1,578 @@
+package client
+
+import "context"
+
+// Call describes a call to a Client method made through a client returned by
+// WithCallMiddleware.
+type Call struct {
+	// Method is the name of the Client method, without any Context suffix,
+	// for example "Transfer".
+	Method string
+
+	// Args are the arguments of the call, excluding ctx, in the order of the
+	// method's parameters. The options given to the listings Accounts,
+	// AccountTransactions and AccountAddresses are included as their last
+	// argument; per-call options such as CallTimeout given to other methods
+	// are not.
+	Args []interface{}
+}
+
+// CallHandler handles a Client method call. The handler at the end of the
+// chain makes the call and returns its error.
+type CallHandler func(ctx context.Context, call Call) error
+
+// CallMiddleware wraps Client method calls. A middleware can inspect the call
+// before and after passing it to next, return an error to refuse it, or
+// return nil without calling next to skip it, for example in a dry run, in
+// which case the method returns zero values.
+type CallMiddleware func(next CallHandler) CallHandler
+
+// WithCallMiddleware returns a Client that passes every method call on c
+// through middleware before making it. The first middleware given is the
+// outermost. Calls to methods without a Context suffix reach middleware with
+// a background context.
+func WithCallMiddleware(c Client, middleware ...CallMiddleware) Client {
+	return &callClient{client: c, middleware: middleware}
+}
+
+type callClient struct {
+	client     Client
+	middleware []CallMiddleware
+}
+
+func (c *callClient) call(ctx context.Context, method string,
+	fn func(ctx context.Context) error, args ...interface{}) error {
+
+	h := CallHandler(func(ctx context.Context, _ Call) error {
+		return fn(ctx)
+	})
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h(ctx, Call{Method: method, Args: args})
+}
+
+func (c *callClient) CreateAccount(options ...option) (Account, error) {
+	return c.CreateAccountContext(context.Background(), options...)
+}
+
+func (c *callClient) CreateAccountContext(ctx context.Context,
+	options ...option) (Account, error) {
+	var acc Account
+	err := c.call(ctx, "CreateAccount", func(ctx context.Context) error {
+		var err error
+		acc, err = c.client.CreateAccountContext(ctx, options...)
+		return err
+	})
+	return acc, err
+}
+
+func (c *callClient) UpdateAccount(accountID int64,
+	options ...option) (Account, error) {
+	return c.UpdateAccountContext(context.Background(), accountID,
+		options...)
+}
+
+func (c *callClient) UpdateAccountContext(ctx context.Context,
+	accountID int64, options ...option) (Account, error) {
+	var acc Account
+	err := c.call(ctx, "UpdateAccount", func(ctx context.Context) error {
+		var err error
+		acc, err = c.client.UpdateAccountContext(ctx, accountID, options...)
+		return err
+	}, accountID)
+	return acc, err
+}
+
+func (c *callClient) CloseAccount(accountID int64,
+	options ...option) error {
+	return c.CloseAccountContext(context.Background(), accountID, options...)
+}
+
+func (c *callClient) CloseAccountContext(ctx context.Context, accountID int64,
+	options ...option) error {
+	return c.call(ctx, "CloseAccount", func(ctx context.Context) error {
+		return c.client.CloseAccountContext(ctx, accountID, options...)
+	}, accountID)
+}
+
+func (c *callClient) CreateAccounts(n int) ([]Account, BatchResult) {
+	return c.CreateAccountsContext(context.Background(), n)
+}
+
+// CreateAccountsContext reports the batch's first failure to middleware. If
+// middleware does not make the call every item is left not attempted, with
+// the middleware's error if it returned one.
+func (c *callClient) CreateAccountsContext(ctx context.Context, n int) (
+	[]Account, BatchResult) {
+	var (
+		accs []Account
+		res  BatchResult
+		ran  bool
+	)
+	err := c.call(ctx, "CreateAccounts", func(ctx context.Context) error {
+		ran = true
+		accs, res = c.client.CreateAccountsContext(ctx, n)
+		return res.Err()
+	}, n)
+	if !ran {
+		accs = make([]Account, n)
+		res = newBatchResult(n)
+		for i := range res.Items {
+			res.Items[i].Err = err
+		}
+	}
+	return accs, res
+}
+
+func (c *callClient) Account(accountID int64, options ...option) (Account,
+	error) {
+	return c.AccountContext(context.Background(), accountID, options...)
+}
+
+func (c *callClient) AccountContext(ctx context.Context, accountID int64,
+	options ...option) (Account, error) {
+	var acc Account
+	err := c.call(ctx, "Account", func(ctx context.Context) error {
+		var err error
+		acc, err = c.client.AccountContext(ctx, accountID, options...)
+		return err
+	}, accountID)
+	return acc, err
+}
+
+func (c *callClient) Accounts(options ...option) (string, []Account, error) {
+	return c.AccountsContext(context.Background(), options...)
+}
+
+func (c *callClient) AccountsContext(ctx context.Context,
+	options ...option) (string, []Account, error) {
+	var (
+		next string
+		accs []Account
+	)
+	err := c.call(ctx, "Accounts", func(ctx context.Context) error {
+		var err error
+		next, accs, err = c.client.AccountsContext(ctx, options...)
+		return err
+	}, options)
+	return next, accs, err
+}
+
+func (c *callClient) CreateAddress(accountID int64, options ...option) (
+	string, error) {
+	return c.CreateAddressContext(context.Background(), accountID, options...)
+}
+
+func (c *callClient) CreateAddressContext(ctx context.Context,
+	accountID int64, options ...option) (string, error) {
+	var addr string
+	err := c.call(ctx, "CreateAddress", func(ctx context.Context) error {
+		var err error
+		addr, err = c.client.CreateAddressContext(ctx, accountID,
+			options...)
+		return err
+	}, accountID)
+	return addr, err
+}
+
+func (c *callClient) CreateAddresses(accountIDs []int64) ([]string,
+	BatchResult) {
+	return c.CreateAddressesContext(context.Background(), accountIDs)
+}
+
+// CreateAddressesContext reports the batch's first failure to middleware. If
+// middleware does not make the call every item is left not attempted, with
+// the middleware's error if it returned one.
+func (c *callClient) CreateAddressesContext(ctx context.Context,
+	accountIDs []int64) ([]string, BatchResult) {
+	var (
+		addrs []string
+		res   BatchResult
+		ran   bool
+	)
+	err := c.call(ctx, "CreateAddresses", func(ctx context.Context) error {
+		ran = true
+		addrs, res = c.client.CreateAddressesContext(ctx, accountIDs)
+		return res.Err()
+	}, accountIDs)
+	if !ran {
+		addrs = make([]string, len(accountIDs))
+		res = newBatchResult(len(accountIDs))
+		for i := range res.Items {
+			res.Items[i].Err = err
+		}
+	}
+	return addrs, res
+}
+
+func (c *callClient) CreateTransactionIDs(n int, options ...option) ([]int64,
+	error) {
+	return c.CreateTransactionIDsContext(context.Background(), n, options...)
+}
+
+func (c *callClient) CreateTransactionIDsContext(ctx context.Context,
+	n int, options ...option) ([]int64, error) {
+	var ids []int64
+	err := c.call(ctx, "CreateTransactionIDs",
+		func(ctx context.Context) error {
+			var err error
+			ids, err = c.client.CreateTransactionIDsContext(ctx, n,
+				options...)
+			return err
+		}, n)
+	return ids, err
+}
+
+func (c *callClient) Transaction(txID int64, options ...option) (Transaction,
+	error) {
+	return c.TransactionContext(context.Background(), txID, options...)
+}
+
+func (c *callClient) TransactionContext(ctx context.Context, txID int64,
+	options ...option) (Transaction, error) {
+	var tx Transaction
+	err := c.call(ctx, "Transaction", func(ctx context.Context) error {
+		var err error
+		tx, err = c.client.TransactionContext(ctx, txID, options...)
+		return err
+	}, txID)
+	return tx, err
+}
+
+func (c *callClient) TransactionsByHash(txHash string,
+	options ...option) ([]Transaction, error) {
+	return c.TransactionsByHashContext(context.Background(), txHash,
+		options...)
+}
+
+func (c *callClient) TransactionsByHashContext(ctx context.Context,
+	txHash string, options ...option) ([]Transaction, error) {
+	var txns []Transaction
+	err := c.call(ctx, "TransactionsByHash", func(ctx context.Context) error {
+		var err error
+		txns, err = c.client.TransactionsByHashContext(ctx, txHash,
+			options...)
+		return err
+	}, txHash)
+	return txns, err
+}
+
+func (c *callClient) RawTransaction(txHash string,
+	options ...option) (RawTransaction, error) {
+	return c.RawTransactionContext(context.Background(), txHash, options...)
+}
+
+func (c *callClient) RawTransactionContext(ctx context.Context,
+	txHash string, options ...option) (RawTransaction, error) {
+	var tx RawTransaction
+	err := c.call(ctx, "RawTransaction", func(ctx context.Context) error {
+		var err error
+		tx, err = c.client.RawTransactionContext(ctx, txHash, options...)
+		return err
+	}, txHash)
+	return tx, err
+}
+
+func (c *callClient) WaitForTransaction(ctx context.Context, txID int64) (
+	Transaction, error) {
+	var tx Transaction
+	err := c.call(ctx, "WaitForTransaction", func(ctx context.Context) error {
+		var err error
+		tx, err = c.client.WaitForTransaction(ctx, txID)
+		return err
+	}, txID)
+	return tx, err
+}
+
+func (c *callClient) AccountAddresses(accountID int64,
+	options ...option) (string, []Address, error) {
+	return c.AccountAddressesContext(context.Background(), accountID,
+		options...)
+}
+
+func (c *callClient) AccountAddressesContext(ctx context.Context,
+	accountID int64, options ...option) (string, []Address, error) {
+	var (
+		next  string
+		addrs []Address
+	)
+	err := c.call(ctx, "AccountAddresses",
+		func(ctx context.Context) error {
+			var err error
+			next, addrs, err = c.client.AccountAddressesContext(ctx,
+				accountID, options...)
+			return err
+		}, accountID, options)
+	return next, addrs, err
+}
+
+func (c *callClient) AccountByAddress(address string,
+	options ...option) (int64, error) {
+	return c.AccountByAddressContext(context.Background(), address,
+		options...)
+}
+
+func (c *callClient) AccountByAddressContext(ctx context.Context,
+	address string, options ...option) (int64, error) {
+	var id int64
+	err := c.call(ctx, "AccountByAddress", func(ctx context.Context) error {
+		var err error
+		id, err = c.client.AccountByAddressContext(ctx, address, options...)
+		return err
+	}, address)
+	return id, err
+}
+
+func (c *callClient) FundAddress(address string, value Amount,
+	options ...option) error {
+	return c.FundAddressContext(context.Background(), address, value,
+		options...)
+}
+
+func (c *callClient) FundAddressContext(ctx context.Context, address string,
+	value Amount, options ...option) error {
+	return c.call(ctx, "FundAddress", func(ctx context.Context) error {
+		return c.client.FundAddressContext(ctx, address, value, options...)
+	}, address, value)
+}
+
+func (c *callClient) AccountTransactions(accountID int64,
+	options ...option) (string, []Transaction, error) {
+	return c.AccountTransactionsContext(context.Background(), accountID,
+		options...)
+}
+
+func (c *callClient) AccountTransactionsContext(ctx context.Context,
+	accountID int64, options ...option) (string, []Transaction, error) {
+	var (
+		next string
+		txns []Transaction
+	)
+	err := c.call(ctx, "AccountTransactions",
+		func(ctx context.Context) error {
+			var err error
+			next, txns, err = c.client.AccountTransactionsContext(ctx,
+				accountID, options...)
+			return err
+		}, accountID, options)
+	return next, txns, err
+}
+
+func (c *callClient) AccountWithTransactions(accountID int64, limit int) (
+	Account, []Transaction, error) {
+	return c.AccountWithTransactionsContext(context.Background(), accountID,
+		limit)
+}
+
+func (c *callClient) AccountWithTransactionsContext(ctx context.Context,
+	accountID int64, limit int) (Account, []Transaction, error) {
+	var (
+		acc  Account
+		txns []Transaction
+	)
+	err := c.call(ctx, "AccountWithTransactions",
+		func(ctx context.Context) error {
+			var err error
+			acc, txns, err = c.client.AccountWithTransactionsContext(ctx,
+				accountID, limit)
+			return err
+		}, accountID, limit)
+	return acc, txns, err
+}
+
+func (c *callClient) Transfer(txID, fromAccountID, toAccountID int64,
+	value Amount, options ...option) (Transaction, error) {
+	return c.TransferContext(context.Background(), txID, fromAccountID,
+		toAccountID, value, options...)
+}
+
+func (c *callClient) TransferContext(ctx context.Context, txID, fromAccountID,
+	toAccountID int64, value Amount, options ...option) (Transaction,
+	error) {
+	var tx Transaction
+	err := c.call(ctx, "Transfer", func(ctx context.Context) error {
+		var err error
+		tx, err = c.client.TransferContext(ctx, txID, fromAccountID,
+			toAccountID, value, options...)
+		return err
+	}, txID, fromAccountID, toAccountID, value)
+	return tx, err
+}
+
+func (c *callClient) Debit(txID, fromAccountID int64, toAddress string,
+	value Amount, options ...option) (Transaction, error) {
+	return c.DebitContext(context.Background(), txID, fromAccountID,
+		toAddress, value, options...)
+}
+
+func (c *callClient) DebitContext(ctx context.Context, txID,
+	fromAccountID int64, toAddress string, value Amount,
+	options ...option) (Transaction, error) {
+	var tx Transaction
+	err := c.call(ctx, "Debit", func(ctx context.Context) error {
+		var err error
+		tx, err = c.client.DebitContext(ctx, txID, fromAccountID,
+			toAddress, value, options...)
+		return err
+	}, txID, fromAccountID, toAddress, value)
+	return tx, err
+}
+
+func (c *callClient) DebitMany(txID, fromAccountID int64, outputs []Output,
+	options ...option) (Transaction, error) {
+	return c.DebitManyContext(context.Background(), txID, fromAccountID,
+		outputs, options...)
+}
+
+func (c *callClient) DebitManyContext(ctx context.Context, txID,
+	fromAccountID int64, outputs []Output,
+	options ...option) (Transaction, error) {
+	var tx Transaction
+	err := c.call(ctx, "DebitMany", func(ctx context.Context) error {
+		var err error
+		tx, err = c.client.DebitManyContext(ctx, txID, fromAccountID,
+			outputs, options...)
+		return err
+	}, txID, fromAccountID, outputs)
+	return tx, err
+}
+
+func (c *callClient) Fees(options ...option) ([]Fee, error) {
+	return c.FeesContext(context.Background(), options...)
+}
+
+func (c *callClient) FeesContext(ctx context.Context, options ...option) (
+	[]Fee, error) {
+	var fees []Fee
+	err := c.call(ctx, "Fees", func(ctx context.Context) error {
+		var err error
+		fees, err = c.client.FeesContext(ctx, options...)
+		return err
+	})
+	return fees, err
+}
+
+func (c *callClient) FeeForTarget(blocks int,
+	options ...option) (Fee, error) {
+	return c.FeeForTargetContext(context.Background(), blocks, options...)
+}
+
+func (c *callClient) FeeForTargetContext(ctx context.Context, blocks int,
+	options ...option) (Fee, error) {
+	var fee Fee
+	err := c.call(ctx, "FeeForTarget", func(ctx context.Context) error {
+		var err error
+		fee, err = c.client.FeeForTargetContext(ctx, blocks, options...)
+		return err
+	}, blocks)
+	return fee, err
+}
+
+func (c *callClient) ChainInfo(options ...option) (ChainInfo, error) {
+	return c.ChainInfoContext(context.Background(), options...)
+}
+
+func (c *callClient) ChainInfoContext(ctx context.Context,
+	options ...option) (ChainInfo, error) {
+	var info ChainInfo
+	err := c.call(ctx, "ChainInfo", func(ctx context.Context) error {
+		var err error
+		info, err = c.client.ChainInfoContext(ctx, options...)
+		return err
+	})
+	return info, err
+}
+
+func (c *callClient) CreateHook(url string, options ...option) error {
+	return c.CreateHookContext(context.Background(), url, options...)
+}
+
+func (c *callClient) CreateHookContext(ctx context.Context, url string,
+	options ...option) error {
+	return c.call(ctx, "CreateHook", func(ctx context.Context) error {
+		return c.client.CreateHookContext(ctx, url, options...)
+	}, url)
+}
+
+func (c *callClient) Hooks(options ...option) ([]Hook, error) {
+	return c.HooksContext(context.Background(), options...)
+}
+
+func (c *callClient) HooksContext(ctx context.Context, options ...option) (
+	[]Hook, error) {
+	var hooks []Hook
+	err := c.call(ctx, "Hooks", func(ctx context.Context) error {
+		var err error
+		hooks, err = c.client.HooksContext(ctx, options...)
+		return err
+	})
+	return hooks, err
+}
+
+func (c *callClient) DeleteHook(url string, options ...option) error {
+	return c.DeleteHookContext(context.Background(), url, options...)
+}
+
+func (c *callClient) DeleteHookContext(ctx context.Context, url string,
+	options ...option) error {
+	return c.call(ctx, "DeleteHook", func(ctx context.Context) error {
+		return c.client.DeleteHookContext(ctx, url, options...)
+	}, url)
+}
+
+func (c *callClient) UpdateHook(hook Hook, options ...option) error {
+	return c.UpdateHookContext(context.Background(), hook, options...)
+}
+
+func (c *callClient) UpdateHookContext(ctx context.Context, hook Hook,
+	options ...option) error {
+	return c.call(ctx, "UpdateHook", func(ctx context.Context) error {
+		return c.client.UpdateHookContext(ctx, hook, options...)
+	}, hook)
+}
+
+func (c *callClient) CreateAccountHook(accountID int64, url string,
+	options ...option) error {
+	return c.CreateAccountHookContext(context.Background(), accountID, url,
+		options...)
+}
+
+func (c *callClient) CreateAccountHookContext(ctx context.Context,
+	accountID int64, url string, options ...option) error {
+	return c.call(ctx, "CreateAccountHook", func(ctx context.Context) error {
+		return c.client.CreateAccountHookContext(ctx, accountID, url,
+			options...)
+	}, accountID, url)
+}
+
+func (c *callClient) AccountHooks(accountID int64, options ...option) (
+	[]Hook, error) {
+	return c.AccountHooksContext(context.Background(), accountID, options...)
+}
+
+func (c *callClient) AccountHooksContext(ctx context.Context,
+	accountID int64, options ...option) ([]Hook, error) {
+	var hooks []Hook
+	err := c.call(ctx, "AccountHooks", func(ctx context.Context) error {
+		var err error
+		hooks, err = c.client.AccountHooksContext(ctx, accountID,
+			options...)
+		return err
+	}, accountID)
+	return hooks, err
+}
+
+func (c *callClient) DeleteAccountHook(accountID int64, url string,
+	options ...option) error {
+	return c.DeleteAccountHookContext(context.Background(), accountID, url,
+		options...)
+}
+
+func (c *callClient) DeleteAccountHookContext(ctx context.Context,
+	accountID int64, url string, options ...option) error {
+	return c.call(ctx, "DeleteAccountHook", func(ctx context.Context) error {
+		return c.client.DeleteAccountHookContext(ctx, accountID, url,
+			options...)
+	}, accountID, url)
+}
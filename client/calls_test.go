@@ -0,0 +1,79 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestWithCallMiddleware(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"GET /accounts/1": `{"type": "accounts",
+			"payload": [{"id": 1, "balance": 10}]}`,
+		"PUT /transactions/": ``,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	var audit []string
+	auditor := func(next client.CallHandler) client.CallHandler {
+		return func(ctx context.Context, call client.Call) error {
+			err := next(ctx, call)
+			audit = append(audit, fmt.Sprintf("%s%v %v", call.Method,
+				call.Args, err))
+			return err
+		}
+	}
+
+	errLimit := errors.New("over limit")
+	policy := func(next client.CallHandler) client.CallHandler {
+		return func(ctx context.Context, call client.Call) error {
+			if call.Method == "Transfer" && call.Args[3].(client.Amount) > 100 {
+				return errLimit
+			}
+			return next(ctx, call)
+		}
+	}
+
+	wrapped := client.WithCallMiddleware(cl, auditor, policy)
+
+	acc, err := wrapped.Account(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Balance != 10 {
+		t.Fatal("unexpected account", acc)
+	}
+	if _, err := wrapped.Transfer(1, 2, 3, 50); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrapped.Transfer(2, 2, 3, 500); err != errLimit {
+		t.Fatal("expected policy error", err)
+	}
+
+	expected := []string{
+		"Account[1] <nil>",
+		"Transfer[1 2 3 0.00000050 BTC] <nil>",
+		"Transfer[2 2 3 0.00000500 BTC] over limit",
+	}
+	if fmt.Sprint(audit) != fmt.Sprint(expected) {
+		t.Fatal("unexpected audit", audit)
+	}
+
+	dryRun := func(client.CallHandler) client.CallHandler {
+		return func(context.Context, client.Call) error { return nil }
+	}
+	addrs, res := client.WithCallMiddleware(cl, dryRun).CreateAddresses(
+		[]int64{1, 2})
+	if len(addrs) != 2 || res.Err() == nil ||
+		res.Items[0].Status != client.BatchNotAttempted {
+		t.Fatal("expected skipped batch", addrs, res)
+	}
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"errors"
+)
+
+// ChaosMode selects the failures injected by WithChaos. Modes can be combined
+// with |.
+type ChaosMode int
+
+const (
+	// ChaosLatency delays requests by up to two seconds.
+	ChaosLatency ChaosMode = 1 << iota
+
+	// ChaosNetworkError fails requests with ErrChaos before they are sent.
+	ChaosNetworkError
+
+	// ChaosServerError replaces responses with a 500 RTWire error object.
+	ChaosServerError
+)
+
+// ErrChaos is returned for requests failed by ChaosNetworkError.
+var ErrChaos = errors.New("chaos: injected network error")
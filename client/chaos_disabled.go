@@ -0,0 +1,15 @@
+//go:build !chaos
+
+package client
+
+// ChaosEnabled reports whether the package was built with the chaos build tag
+// and WithChaos injects failures.
+const ChaosEnabled = false
+
+// WithChaos injects failures chosen from modes into a fraction, probability,
+// of requests so that fallback paths can be exercised against the real client.
+// Failures are only injected when built with the chaos tag; otherwise WithChaos
+// does nothing. It must never be enabled in production.
+func WithChaos(probability float64, modes ChaosMode) ClientOption {
+	return func(c *client) {}
+}
@@ -0,0 +1,96 @@
+//go:build chaos
+
+package client
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// ChaosEnabled reports whether the package was built with the chaos build tag
+// and WithChaos injects failures.
+const ChaosEnabled = true
+
+const maxChaosLatency = 2 * time.Second
+
+const chaosServerError = `{"type": "errors",
+	"payload": [{"message": "chaos: injected server error"}]}`
+
+// WithChaos injects failures chosen from modes into a fraction, probability,
+// of requests so that fallback paths can be exercised against the real client.
+// Failures are only injected when built with the chaos tag; otherwise WithChaos
+// does nothing. It must never be enabled in production.
+func WithChaos(probability float64, modes ChaosMode) ClientOption {
+	return func(c *client) {
+		hc := c.ownHTTPClient()
+		next := hc.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		hc.Transport = &chaosTransport{
+			next:        next,
+			probability: probability,
+			modes:       modes,
+		}
+	}
+}
+
+type chaosTransport struct {
+	next        http.RoundTripper
+	probability float64
+	modes       ChaosMode
+}
+
+func (t *chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rand.Float64() >= t.probability {
+		return t.next.RoundTrip(req)
+	}
+
+	var enabled []ChaosMode
+	for _, mode := range []ChaosMode{
+		ChaosLatency, ChaosNetworkError, ChaosServerError,
+	} {
+		if t.modes&mode != 0 {
+			enabled = append(enabled, mode)
+		}
+	}
+	if len(enabled) == 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	switch enabled[rand.Intn(len(enabled))] {
+	case ChaosLatency:
+		delay := time.Duration(rand.Int63n(int64(maxChaosLatency)))
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		return t.next.RoundTrip(req)
+	case ChaosNetworkError:
+		return nil, ErrChaos
+	default:
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return &http.Response{
+			Status:     "500 Internal Server Error",
+			StatusCode: http.StatusInternalServerError,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header: http.Header{
+				"Content-Type": []string{"application/json"},
+			},
+			Body: io.NopCloser(
+				bytes.NewReader([]byte(chaosServerError))),
+			ContentLength: int64(len(chaosServerError)),
+			Request:       req,
+		}, nil
+	}
+}
@@ -0,0 +1,45 @@
+//go:build chaos
+
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestChaos(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"GET /accounts/1": `{"type": "accounts", "payload": [{"id": 1}]}`,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithChaos(1, client.ChaosNetworkError))
+	if _, err := cl.Account(1); err == nil {
+		t.Fatal("expected injected error")
+	}
+
+	cl = client.New(http.DefaultClient, url, "user", "pass",
+		client.WithChaos(1, client.ChaosServerError))
+	if _, err := cl.Account(1); err == nil ||
+		err.Error() != "chaos: injected server error" {
+		t.Fatal("expected injected server error", err)
+	}
+
+	cl = client.New(http.DefaultClient, url, "user", "pass",
+		client.WithChaos(0, client.ChaosNetworkError))
+	if _, err := cl.Account(1); err != nil {
+		t.Fatal(err)
+	}
+
+	// The shared http.Client must not be modified.
+	if http.DefaultClient.Transport != nil {
+		t.Fatal("default client transport modified")
+	}
+}
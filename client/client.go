@@ -2,14 +2,19 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"log/slog"
+	"mime"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -23,6 +28,8 @@ const (
 	TestNet3URL = "https://api.rtwire.com/v1/testnet3"
 )
 
+// Errors reported by RTWire are returned as *APIError values which match the
+// corresponding sentinel errors below using errors.Is.
 var (
 	// ErrTxIDUsed is returned from Transfer or Debit if the transaction ID has
 	// already been used.
@@ -32,35 +39,139 @@ var (
 	// sending the funds has insufficient satoshi.
 	ErrInsufficientFunds = errors.New("insufficient funds")
 
+	// ErrAccountClosed is returned if an address is requested for, or funds
+	// moved to or from, an account closed with CloseAccount.
+	ErrAccountClosed = errors.New("account closed")
+
 	// ErrHookExists is returned if a web hook has already been registered.
 	ErrHookExists = errors.New("hook exists")
+
+	// ErrInconsistentSnapshot is returned from AccountWithTransactions if a
+	// consistent view of an account could not be read, usually because the
+	// account is too busy.
+	ErrInconsistentSnapshot = errors.New("inconsistent account snapshot")
+
+	// ErrNotFound is returned from Transaction if the transaction does not
+	// exist, or is not yet visible, in RTWire.
+	ErrNotFound = errors.New("not found")
+
+	// ErrMainNet is returned from FundAddress on a client of MainNetURL, where
+	// addresses are only funded by bitcoin transactions.
+	ErrMainNet = errors.New("not available on mainnet")
 )
 
-type option func(url *url.URL) error
+// option configures a single call. Most options, such as Limit, set query
+// parameters of the request and only apply to the methods that document them.
+// Others, such as CallTimeout, apply to any method taking options.
+type option func(o *callOptions) error
 
-func setQueryValue(u *url.URL, key, value string) error {
-	v, err := url.ParseQuery(u.RawQuery)
-	if err != nil {
-		return err
+// Option is the type of the options accepted by Client methods, such as Limit
+// and CallTimeout. It allows other packages to implement Client.
+type Option = option
+
+// callOptions holds the options of a call.
+type callOptions struct {
+	query   url.Values
+	header  http.Header
+	timeout time.Duration
+	noRetry bool
+	types   TransactionType
+
+	feePerByte int64
+	confTarget int
+
+	// retried is set once the call's request has been retried.
+	retried bool
+
+	reconcileHook bool
+
+	// hook holds the fields set with HookSecret, HookAuthorization and
+	// HookContentType. Its URL is not used.
+	hook Hook
+
+	// address holds the fields set with AddressFormat, SingleUse and
+	// ExpireAfter.
+	address addressFields
+
+	// account holds the fields set with Label and Metadata.
+	account accountFields
+}
+
+func (o *callOptions) setQuery(key, value string) error {
+	if o.query == nil {
+		o.query = url.Values{}
 	}
-	v.Set(key, value)
-	u.RawQuery = v.Encode()
+	o.query.Set(key, value)
 	return nil
 }
 
+// applyOptions returns the options of a call.
+func applyOptions(options []option) (*callOptions, error) {
+	o := &callOptions{}
+	for _, op := range options {
+		if err := op(o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// callOptionsKey is the context key holding the options of a request.
+type callOptionsKey struct{}
+
+func callOptionsFromContext(ctx context.Context) *callOptions {
+	o, _ := ctx.Value(callOptionsKey{}).(*callOptions)
+	if o == nil {
+		return &callOptions{}
+	}
+	return o
+}
+
+// CallTimeout limits the time a single call may take, including any retries,
+// to d. It is applied on top of any deadline of the call's context.
+func CallTimeout(d time.Duration) option {
+	return func(o *callOptions) error {
+		o.timeout = d
+		return nil
+	}
+}
+
+// NoRetry disables retries for a single call made by a client created with
+// WithRetry.
+func NoRetry() option {
+	return func(o *callOptions) error {
+		o.noRetry = true
+		return nil
+	}
+}
+
+// CallHeader sets the header key to value on the request of a single call,
+// replacing any value given with WithBaseHeader. It cannot override the
+// Authorization, Accept, Content-Type or X-Request-ID headers.
+func CallHeader(key, value string) option {
+	return func(o *callOptions) error {
+		if o.header == nil {
+			o.header = http.Header{}
+		}
+		o.header.Set(key, value)
+		return nil
+	}
+}
+
 // Limit limits the maximum number of results returned from both the
 // AccountTransactions and Account endpoints.
 func Limit(limit int) option {
-	return func(u *url.URL) error {
-		return setQueryValue(u, "limit", strconv.Itoa(limit))
+	return func(o *callOptions) error {
+		return o.setQuery("limit", strconv.Itoa(limit))
 	}
 }
 
-// Next takes the cursor value of a previous call to AccountTransactions, or
-// Accounts in order to page through the next set of results.
+// Next takes the cursor value of a previous call to AccountTransactions,
+// Accounts or AccountAddresses in order to page through the next set of
+// results.
 func Next(next string) option {
-	return func(u *url.URL) error {
-		return setQueryValue(u, "next", next)
+	return func(o *callOptions) error {
+		return o.setQuery("next", next)
 	}
 }
 
@@ -68,8 +179,291 @@ func Next(next string) option {
 // transactions that have been detected by RTWire but have not yet been credited
 // to an account.
 func Pending() option {
-	return func(u *url.URL) error {
-		return setQueryValue(u, "status", "pending")
+	return func(o *callOptions) error {
+		return o.setQuery("status", "pending")
+	}
+}
+
+// ReconcileHook is an option used with CreateHook to make hook setup
+// idempotent. If a hook with the URL is already registered, the registered
+// hook is fetched and compared with the requested one: CreateHook returns nil
+// if they match and otherwise updates the hook in place with UpdateHook,
+// rather than returning ErrHookExists.
+func ReconcileHook() option {
+	return func(o *callOptions) error {
+		o.reconcileHook = true
+		return nil
+	}
+}
+
+// HookSecret is an option used with CreateHook and CreateAccountHook to have
+// RTWire sign each delivery with secret, which must be at least 16 characters
+// long, so that the receiving endpoint can authenticate it with
+// VerifyHookSignature rather than rely on the URL being secret.
+func HookSecret(secret string) option {
+	return func(o *callOptions) error {
+		if len(secret) < 16 {
+			return &ValidationError{"secret",
+				"must be at least 16 characters"}
+		}
+		o.hook.Secret = secret
+		return nil
+	}
+}
+
+// HookAuthorization is an option used with CreateHook and CreateAccountHook
+// to have RTWire send value as the Authorization header of each delivery, for
+// example "Bearer " followed by a token the endpoint expects.
+func HookAuthorization(value string) option {
+	return func(o *callOptions) error {
+		if value == "" || strings.ContainsAny(value, "\r\n") {
+			return &ValidationError{"authorization",
+				"must be a non-empty single line"}
+		}
+		o.hook.Authorization = value
+		return nil
+	}
+}
+
+// HookContentType is an option used with CreateHook and CreateAccountHook to
+// set the Content-Type header of each delivery, for endpoints expecting a
+// parameter such as "application/json; charset=utf-8".
+func HookContentType(contentType string) option {
+	return func(o *callOptions) error {
+		mt, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !strings.Contains(mt, "/") {
+			return &ValidationError{"contentType", "must be a media type"}
+		}
+		o.hook.ContentType = contentType
+		return nil
+	}
+}
+
+// AddressFormat is an option used with CreateAddress to create an address of
+// type t, such as Bech32, instead of a P2PKH address.
+func AddressFormat(t AddressType) option {
+	return func(o *callOptions) error {
+		switch t {
+		case P2PKH, P2SHSegwit, Bech32, P2TR:
+		default:
+			return &ValidationError{"addressType",
+				fmt.Sprintf("unknown address type %q", t)}
+		}
+		o.address.Type = t
+		return nil
+	}
+}
+
+// SingleUse is an option used with CreateAddress to create an address that
+// only accepts its first payment, for example for an invoice. RTWire sends an
+// AddressExpiredEvent for later payments to it.
+func SingleUse() option {
+	return func(o *callOptions) error {
+		o.address.SingleUse = true
+		return nil
+	}
+}
+
+// ExpireAfter is an option used with CreateAddress to create an address that
+// expires d after it is created, rounded down to the second. RTWire sends an
+// AddressExpiredEvent for payments to it once it has expired.
+func ExpireAfter(d time.Duration) option {
+	return func(o *callOptions) error {
+		if d < time.Second {
+			return &ValidationError{"expireAfter",
+				"must be at least a second"}
+		}
+		o.address.ExpiresIn = int64(d / time.Second)
+		return nil
+	}
+}
+
+// addressFields are the fields of an address requested when creating it.
+type addressFields struct {
+	Type      AddressType `json:"type,omitempty"`
+	SingleUse bool        `json:"singleUse,omitempty"`
+	ExpiresIn int64       `json:"expiresIn,omitempty"` // seconds
+}
+
+// accountFields are the fields of an account set by its owner rather than by
+// RTWire. Nil fields are left unchanged.
+type accountFields struct {
+	Label    *string            `json:"label,omitempty"`
+	Metadata *map[string]string `json:"metadata,omitempty"`
+}
+
+func (f accountFields) empty() bool {
+	return f.Label == nil && f.Metadata == nil
+}
+
+// Label is an option used with CreateAccount or UpdateAccount to set the
+// account's label, for example the ID of the customer owning it.
+func Label(label string) option {
+	return func(o *callOptions) error {
+		o.account.Label = &label
+		return nil
+	}
+}
+
+// Metadata is an option used with CreateAccount or UpdateAccount to set the
+// account's metadata, replacing any it had. An empty md clears it.
+func Metadata(md map[string]string) option {
+	return func(o *callOptions) error {
+		copied := make(map[string]string, len(md))
+		for key, value := range md {
+			if key == "" {
+				return &ValidationError{"metadata", "keys must not be empty"}
+			}
+			copied[key] = value
+		}
+		o.account.Metadata = &copied
+		return nil
+	}
+}
+
+// FeePerByte is an option used with Debit to pay the given miner fee, in
+// satoshi per byte, instead of the fee chosen by RTWire. It cannot be combined
+// with ConfirmationTarget.
+func FeePerByte(satoshi int64) option {
+	return func(o *callOptions) error {
+		if satoshi <= 0 {
+			return &ValidationError{"feePerByte", "must be greater than zero"}
+		}
+		o.feePerByte = satoshi
+		return nil
+	}
+}
+
+// ConfirmationTarget is an option used with Debit to pay a fee expected to
+// confirm the debit within blocks blocks, for example 1 for an urgent
+// withdrawal or 144 for an overnight batch. It cannot be combined with
+// FeePerByte. FeeForTarget returns the fee it pays.
+func ConfirmationTarget(blocks int) option {
+	return func(o *callOptions) error {
+		if blocks < 1 || blocks > 1008 {
+			return &ValidationError{"confirmationTarget",
+				"must be between 1 and 1008 blocks"}
+		}
+		o.confTarget = blocks
+		return nil
+	}
+}
+
+// TransactionType is a set of transaction types used with TypeFilter. Types
+// can be combined, for example Credit|Debit.
+type TransactionType uint
+
+const (
+	// Credit is a deposit from outside RTWire.
+	Credit TransactionType = 1 << iota
+
+	// Debit is a payment to an address outside RTWire.
+	Debit
+
+	// Transfer is a transfer between RTWire accounts.
+	Transfer
+)
+
+var transactionTypes = []struct {
+	typ  TransactionType
+	name string
+}{
+	{Credit, "credit"},
+	{Debit, "debit"},
+	{Transfer, "transfer"},
+}
+
+// has reports whether name, the Type of a Transaction, is in t.
+func (t TransactionType) has(name string) bool {
+	for _, tt := range transactionTypes {
+		if tt.name == name {
+			return t&tt.typ != 0
+		}
+	}
+	return false
+}
+
+// TypeFilter is an option used with AccountTransactions to return only
+// transactions of the given types, for example TypeFilter(Debit) to audit
+// payouts. Transactions of other types are also removed from the returned
+// page, so a page may hold fewer transactions than requested with Limit.
+func TypeFilter(types TransactionType) option {
+	return func(o *callOptions) error {
+		if types == 0 || types&^(Credit|Debit|Transfer) != 0 {
+			return &ValidationError{"types",
+				fmt.Sprintf("unknown transaction types %d", types)}
+		}
+		var names []string
+		for _, tt := range transactionTypes {
+			if types&tt.typ != 0 {
+				names = append(names, tt.name)
+			}
+		}
+		o.types = types
+		return o.setQuery("type", strings.Join(names, ","))
+	}
+}
+
+// SortOrder is the direction results are sorted in with Order.
+type SortOrder string
+
+const (
+	// OrderAscending returns the oldest or smallest results first.
+	OrderAscending SortOrder = "asc"
+
+	// OrderDescending returns the newest or largest results first.
+	OrderDescending SortOrder = "desc"
+)
+
+// SortField is a field results can be sorted by with SortBy.
+type SortField string
+
+const (
+	// SortByCreated sorts results by the time they were created.
+	SortByCreated SortField = "created"
+
+	// SortByValue sorts transactions by their value. It is only supported by
+	// AccountTransactions.
+	SortByValue SortField = "value"
+)
+
+// Order is an option used with Accounts and AccountTransactions to set the
+// direction results are sorted in. Results are sorted by creation time unless
+// SortBy is also given. The order must be kept the same when paging with
+// Next.
+func Order(order SortOrder) option {
+	return func(o *callOptions) error {
+		if order != OrderAscending && order != OrderDescending {
+			return &ValidationError{"order",
+				fmt.Sprintf("unknown sort order %q", order)}
+		}
+		return o.setQuery("order", string(order))
+	}
+}
+
+// Ascending is shorthand for Order(OrderAscending), returning the oldest
+// results first, for example to backfill history.
+func Ascending() option {
+	return Order(OrderAscending)
+}
+
+// Descending is shorthand for Order(OrderDescending), returning the newest
+// results first, for example for a dashboard.
+func Descending() option {
+	return Order(OrderDescending)
+}
+
+// SortBy is an option used with Accounts and AccountTransactions to set the
+// field results are sorted by. Results with equal values are returned in a
+// stable order so pages don't overlap or skip results. The field must be kept
+// the same when paging with Next.
+func SortBy(field SortField) option {
+	return func(o *callOptions) error {
+		if field != SortByCreated && field != SortByValue {
+			return &ValidationError{"sortBy",
+				fmt.Sprintf("unknown sort field %q", field)}
+		}
+		return o.setQuery("sortBy", string(field))
 	}
 }
 
@@ -78,10 +472,22 @@ func Pending() option {
 type Client interface {
 
 	// CreateAccount creates a new account.
-	CreateAccount() (Account, error)
+	CreateAccount(options ...option) (Account, error)
+
+	// UpdateAccount sets the label or metadata of an account with the Label
+	// and Metadata options.
+	UpdateAccount(accountID int64, options ...option) (Account, error)
+
+	// CloseAccount closes an account, preventing new addresses and transfers.
+	CloseAccount(accountID int64, options ...option) error
+
+	// CreateAccounts creates n accounts, making several requests at once as
+	// set by WithBatchConcurrency. The returned accounts are in item order
+	// and are zero for items that failed.
+	CreateAccounts(n int) ([]Account, BatchResult)
 
 	// Account returns the account associated with accountID.
-	Account(accountID int64) (Account, error)
+	Account(accountID int64, options ...option) (Account, error)
 
 	// Accounts returns a cursor and a list of previously created accounts.
 	//
@@ -93,17 +499,57 @@ type Client interface {
 	Accounts(options ...option) (string, []Account, error)
 
 	// CreateAddress creates a public key hash bitcoin address for the account
-	// represented by accountID. This address can be used to send bitcoins to
-	// the account.
-	CreateAddress(accountID int64) (string, error)
+	// represented by accountID, or an address of the type given with
+	// AddressFormat. This address can be used to send bitcoins to the account.
+	CreateAddress(accountID int64, options ...option) (string, error)
+
+	// CreateAddresses creates one address for each account in accountIDs. The
+	// returned addresses are in the same order as accountIDs and are empty
+	// for items that failed.
+	CreateAddresses(accountIDs []int64) ([]string, BatchResult)
 
 	// CreateTransactionIDs creates transaction IDs that can be used to transfer
 	// bitcoins between accounts or debit bitcoins to other addresses. A
 	// transaction ID can only be used once.
-	CreateTransactionIDs(int) ([]int64, error)
+	CreateTransactionIDs(n int, options ...option) ([]int64, error)
+
+	// AccountAddresses returns a cursor and the deposit addresses created for
+	// accountID, with the time each was created.
+	//
+	// The Next() option can be used to with the previous cursor to retrieve the
+	// next page of results.
+	//
+	// The Limit() option can be used to limit the maximum number of addresses
+	// returned in one call.
+	AccountAddresses(accountID int64, options ...option) (
+		string, []Address, error)
+
+	// AccountByAddress returns the ID of the account that address was created
+	// for.
+	AccountByAddress(address string, options ...option) (int64, error)
+
+	// FundAddress credits value satoshi to address as if it had been paid
+	// on chain, to seed balances in tests against testnet3 or a mock of
+	// RTWire.
+	FundAddress(address string, value Amount, options ...option) error
 
 	// Transaction returns the transaction associated with txID.
-	Transaction(txID int64) (Transaction, error)
+	Transaction(txID int64, options ...option) (Transaction, error)
+
+	// TransactionsByHash returns the transactions paid out or credited by the
+	// bitcoin transaction with txHash.
+	TransactionsByHash(txHash string, options ...option) ([]Transaction,
+		error)
+
+	// RawTransaction returns the bitcoin transaction with txHash, such as a
+	// hash listed in Transaction.TxHashes, decoded from its serialization.
+	RawTransaction(txHash string, options ...option) (RawTransaction, error)
+
+	// WaitForTransaction returns the transaction associated with txID,
+	// polling while RTWire reports it as not found. A transaction may not be
+	// visible immediately after Transfer or Debit return so this should be
+	// used instead of Transaction when reading a transaction just written.
+	WaitForTransaction(ctx context.Context, txID int64) (Transaction, error)
 
 	// AccountTransactions returns a cursor and the transactions associated with
 	// accountID.
@@ -116,48 +562,191 @@ type Client interface {
 	//
 	// The Pending() option can be used to only view transactions that are yet
 	// to be confirmed by the system.
+	//
+	// The TypeFilter() option can be used to only view transactions of some
+	// types, for example debits.
 	AccountTransactions(accountID int64, options ...option) (
 		string, []Transaction, error)
 
-	// Transfer transfers satoshi from one account to another. An unused txID,
-	// which can be generated by CreateTransactionIDs, must be used for this
-	// call to succeed.
-	Transfer(txID, fromAccountID, toAccountID, value int64) error
-
-	// Debit transfers satoshi from fromAccountID to toAddress which should be
-	// a public key hash bitcoin address. An unused txID, which can be generated
-	// by CreateTransactionIDs, must be used for this call to succeed.
-	Debit(txID, fromAccountID int64, toAddress string, value int64) error
+	// AccountWithTransactions returns the account associated with accountID
+	// together with up to limit of its most recent transactions, retrying
+	// until the balance and the transactions agree with each other.
+	AccountWithTransactions(accountID int64, limit int) (
+		Account, []Transaction, error)
+
+	// Transfer transfers satoshi from one account to another and returns the
+	// transfer transaction, including the account balances after it. An
+	// unused txID, which can be generated by CreateTransactionIDs, must be
+	// used for this call to succeed.
+	Transfer(txID, fromAccountID, toAccountID int64, value Amount,
+		options ...option) (Transaction, error)
+
+	// Debit transfers satoshi from fromAccountID to toAddress which may be a
+	// legacy, segwit or taproot bitcoin address. An unused txID, which can be
+	// generated by CreateTransactionIDs, must be used for this call to
+	// succeed.
+	Debit(txID, fromAccountID int64, toAddress string, value Amount,
+		options ...option) (Transaction, error)
+
+	// DebitMany pays each of outputs from fromAccountID in a single bitcoin
+	// transaction, which costs less in miner fees than a debit per output. An
+	// unused txID, which can be generated by CreateTransactionIDs, must be used
+	// for this call to succeed.
+	DebitMany(txID, fromAccountID int64, outputs []Output,
+		options ...option) (Transaction, error)
 
 	// Fees returns the approximate value per byte in satoshi of bitcoin
 	// transaction currently being used as miner incentives. An average
 	// transaction is approximately 250 bytes in size.
-	Fees() ([]Fee, error)
+	Fees(options ...option) ([]Fee, error)
+
+	// FeeForTarget returns the fee per byte expected to confirm a transaction
+	// within blocks blocks.
+	FeeForTarget(blocks int, options ...option) (Fee, error)
+
+	// ChainInfo returns the height and hash of the last block RTWire has
+	// processed and whether it is in sync with the bitcoin network.
+	ChainInfo(options ...option) (ChainInfo, error)
 
 	// CreateHook a web hook described by url. RTWire will POST to this URL
 	// every time bitcoins are credited to an account.
-	CreateHook(url string) error
+	CreateHook(url string, options ...option) error
 
 	// Hooks returns all the hooks currently stored within RTWire for this
 	// client.
-	Hooks() ([]Hook, error)
+	Hooks(options ...option) ([]Hook, error)
 
 	// DeleteHook deletes the hook specified in url.
-	DeleteHook(url string) error
+	DeleteHook(url string, options ...option) error
+
+	// UpdateHook replaces the configuration of the registered hook with
+	// hook's URL by hook.
+	UpdateHook(hook Hook, options ...option) error
+
+	// CreateAccountHook creates a web hook described by url that is only
+	// called for transactions involving accountID.
+	CreateAccountHook(accountID int64, url string, options ...option) error
+
+	// AccountHooks returns the hooks registered for accountID.
+	AccountHooks(accountID int64, options ...option) ([]Hook, error)
+
+	// DeleteAccountHook deletes the hook specified in url from accountID.
+	DeleteAccountHook(accountID int64, url string, options ...option) error
+
+	// The following methods behave as the method of the same name without
+	// the Context suffix but make their requests with ctx, so the call is
+	// abandoned when ctx is cancelled or its deadline passes.
+
+	CreateAccountContext(ctx context.Context, options ...option) (
+		Account, error)
+	UpdateAccountContext(ctx context.Context, accountID int64,
+		options ...option) (Account, error)
+	CloseAccountContext(ctx context.Context, accountID int64,
+		options ...option) error
+	CreateAccountsContext(ctx context.Context, n int) ([]Account,
+		BatchResult)
+	AccountContext(ctx context.Context, accountID int64,
+		options ...option) (Account, error)
+	AccountsContext(ctx context.Context, options ...option) (
+		string, []Account, error)
+	CreateAddressContext(ctx context.Context, accountID int64,
+		options ...option) (string, error)
+	CreateAddressesContext(ctx context.Context, accountIDs []int64) (
+		[]string, BatchResult)
+	AccountAddressesContext(ctx context.Context, accountID int64,
+		options ...option) (string, []Address, error)
+	AccountByAddressContext(ctx context.Context, address string,
+		options ...option) (int64, error)
+	FundAddressContext(ctx context.Context, address string, value Amount,
+		options ...option) error
+	CreateTransactionIDsContext(ctx context.Context, n int,
+		options ...option) ([]int64, error)
+	TransactionContext(ctx context.Context, txID int64,
+		options ...option) (Transaction, error)
+	TransactionsByHashContext(ctx context.Context, txHash string,
+		options ...option) ([]Transaction, error)
+	RawTransactionContext(ctx context.Context, txHash string,
+		options ...option) (RawTransaction, error)
+	AccountTransactionsContext(ctx context.Context, accountID int64,
+		options ...option) (string, []Transaction, error)
+	AccountWithTransactionsContext(ctx context.Context, accountID int64,
+		limit int) (Account, []Transaction, error)
+	TransferContext(ctx context.Context, txID, fromAccountID,
+		toAccountID int64, value Amount, options ...option) (Transaction,
+		error)
+	DebitContext(ctx context.Context, txID, fromAccountID int64,
+		toAddress string, value Amount, options ...option) (Transaction,
+		error)
+	DebitManyContext(ctx context.Context, txID, fromAccountID int64,
+		outputs []Output, options ...option) (Transaction, error)
+	FeesContext(ctx context.Context, options ...option) ([]Fee, error)
+	FeeForTargetContext(ctx context.Context, blocks int,
+		options ...option) (Fee, error)
+	ChainInfoContext(ctx context.Context, options ...option) (ChainInfo,
+		error)
+	CreateHookContext(ctx context.Context, url string,
+		options ...option) error
+	HooksContext(ctx context.Context, options ...option) ([]Hook, error)
+	DeleteHookContext(ctx context.Context, url string,
+		options ...option) error
+	UpdateHookContext(ctx context.Context, hook Hook,
+		options ...option) error
+	CreateAccountHookContext(ctx context.Context, accountID int64,
+		url string, options ...option) error
+	AccountHooksContext(ctx context.Context, accountID int64,
+		options ...option) ([]Hook, error)
+	DeleteAccountHookContext(ctx context.Context, accountID int64,
+		url string, options ...option) error
 }
 
 type client struct {
 	client *http.Client
 	url    string
-	user   string
-	pass   string
+	codec  Codec
+	json   jsonCodec
+	header http.Header
+
+	// network is the network of url, whose addresses are accepted by Debit,
+	// or nil if url names no known network.
+	network *Network
+
+	// authorization is the Authorization header of the user and password
+	// given to New.
+	authorization string
+
+	// roundTrip sends requests through the middleware to client. It is built
+	// once by New.
+	roundTrip RoundTripperFunc
+
+	ownsHTTPClient bool
+
+	addressVerifier AddressVerifier
+	limiter         Limiter
+	coalescer       *coalescer
+	breaker         *breaker
+	middleware      []Middleware
+	logger          *slog.Logger
+	retries         int
+	hookPolicy      *HookPolicy
+	hookPreflight   bool
+	idGenerator     IDGenerator
+	location        *time.Location
+	concurrency     int
+	credentials     *Credentials
 }
 
 // Account represents an RTWire account. See https://rtwire.com/docs#accounts
 // for more information.
 type Account struct {
-	ID      int64 `json:"id"`
-	Balance int64 `json:"balance"`
+	ID      int64  `json:"id"`
+	Balance Amount `json:"balance"`
+
+	// Label and Metadata are set with the Label and Metadata options.
+	Label    string            `json:"label,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Closed is set once the account is closed with CloseAccount.
+	Closed bool `json:"closed,omitempty"`
 }
 
 // Transaction represents a RTWire transaction. See
@@ -170,267 +759,758 @@ type Transaction struct {
 	FromAccountID int64 `json:"fromAccountID"`
 	ToAccountID   int64 `json:"toAccountID"`
 
-	FromAccountBalance int64 `json:"fromAccountBalance"`
-	ToAccountBalance   int64 `json:"toAccountBalance"`
+	FromAccountBalance Amount `json:"fromAccountBalance"`
+	ToAccountBalance   Amount `json:"toAccountBalance"`
 
 	FromAccountTxID int64 `json:"fromAccountTxID"`
 	ToAccountTxID   int64 `json:"toAccountTxID"`
 
-	Value   int64     `json:"value"`
+	Value   Amount    `json:"value"`
 	Created time.Time `json:"created"`
 
 	TxHashes   []string `json:"txHashes"`
 	TxOutIndex int64    `json:"txOutIndex"`
 }
 
+// Output is a payment of Value satoshi to Address made by DebitMany.
+type Output struct {
+	Address string `json:"address"`
+	Value   Amount `json:"value"`
+}
+
 type Fee struct {
 	FeePerByte  int64 `json:"feePerByte"`
 	BlockHeight int64 `json:"blockHeight"`
+
+	// ConfirmationTarget is the number of blocks the fee is expected to
+	// confirm a transaction within, if the fee was estimated for a target.
+	ConfirmationTarget int `json:"confirmationTarget,omitempty"`
+}
+
+// ChainInfo is RTWire's view of the bitcoin blockchain.
+type ChainInfo struct {
+	// Height and BestBlockHash identify the last block RTWire has processed.
+	Height        int64  `json:"height"`
+	BestBlockHash string `json:"bestBlockHash"`
+
+	// HeaderHeight is the height of the best block header RTWire has seen,
+	// ahead of Height while RTWire catches up.
+	HeaderHeight int64 `json:"headerHeight"`
+
+	// Synced reports whether RTWire has processed every block of the best
+	// chain known to the bitcoin network.
+	Synced bool `json:"synced"`
 }
 
 // Hook represents an RTWire hook. See https://rtwire.com/docs#hooks for more
 // information.
 type Hook struct {
 	URL string `json:"url"`
+
+	// Secret, set with HookSecret, signs each delivery so that it can be
+	// checked with VerifyHookSignature. RTWire does not list secrets, so
+	// Hooks returns it empty.
+	Secret string `json:"secret,omitempty"`
+
+	// Authorization, set with HookAuthorization, is sent as the
+	// Authorization header of each delivery.
+	Authorization string `json:"authorization,omitempty"`
+
+	// ContentType, set with HookContentType, is the Content-Type header of
+	// each delivery, application/json unless set.
+	ContentType string `json:"contentType,omitempty"`
 }
 
 type address struct {
-	Address string
+	Address string `json:"address"`
+}
+
+// Address is a deposit address of an account, as returned by
+// AccountAddresses.
+type Address struct {
+	AccountID int64     `json:"accountID,omitempty"`
+	Address   string    `json:"address"`
+	Created   time.Time `json:"created"`
+
+	// SingleUse and Expires are set for addresses created with the SingleUse
+	// and ExpireAfter options. Expires is zero for addresses that don't
+	// expire.
+	SingleUse bool      `json:"singleUse,omitempty"`
+	Expires   time.Time `json:"expires,omitempty"`
 }
 
 type object struct {
-	Type    string
-	Next    string
-	Payload json.RawMessage
+	Type    string          `json:"type"`
+	Next    string          `json:"next"`
+	Payload json.RawMessage `json:"payload"`
 }
 
-func (c *client) do(req *http.Request) (string, json.RawMessage, error) {
-	resp, err := c.client.Do(req)
+// request applies options to a call and returns its request. The options are
+// kept in the request's context for do.
+func (c *client) request(ctx context.Context, method, urlStr string,
+	body interface{}, options []option) (*http.Request, error) {
+
+	o, err := applyOptions(options)
 	if err != nil {
-		return "", nil, err
+		return nil, err
+	}
+	if len(o.query) > 0 {
+		u, err := url.Parse(urlStr)
+		if err != nil {
+			return nil, err
+		}
+		q := u.Query()
+		for key, values := range o.query {
+			q[key] = values
+		}
+		u.RawQuery = q.Encode()
+		urlStr = u.String()
+	}
+	return c.newRequest(context.WithValue(ctx, callOptionsKey{}, o), method,
+		urlStr, body)
+}
+
+func (c *client) newRequest(ctx context.Context, method, urlStr string,
+	body interface{}) (*http.Request, error) {
+
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		// The body is already encoded.
+		r = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, r)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	for key, values := range callOptionsFromContext(ctx).header {
+		req.Header[key] = values
+	}
+	// Headers are set by their canonical keys, saving Header.Set the work on
+	// every request.
+	req.Header["Authorization"] = []string{c.authorization}
+	req.Header["Accept"] = []string{c.accept()}
+	req.Header[canonicalRequestIDHeader] = []string{requestID(ctx)}
+	if body != nil {
+		req.Header["Content-Type"] = []string{"application/json"}
+	}
+	return req, nil
+}
+
+// sendOnce sends req, coalescing it with identical requests in flight if
+// enabled.
+func (c *client) sendOnce(req *http.Request) (*http.Response, []byte, error) {
+	if c.coalescer != nil && req.Method == "GET" {
+		return c.coalescer.do(req, c.send)
+	}
+	return c.send(req)
+}
+
+// send makes the request and reads the response body, closing it.
+func (c *client) send(req *http.Request) (*http.Response, []byte, error) {
+	if c.breaker == nil {
+		return c.sendLimited(req)
+	}
+	if err := c.breaker.allow(); err != nil {
+		return nil, nil, err
+	}
+	resp, body, err := c.sendLimited(req)
+	c.breaker.record(resp, err)
+	return resp, body, err
+}
+
+func (c *client) sendLimited(req *http.Request) (*http.Response, []byte,
+	error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	start := time.Now()
+	resp, body, err := c.read(req)
+	if c.logger != nil {
+		c.logRequest(req, resp, err, time.Since(start))
+	}
+	return resp, body, err
+}
+
+// maxPreallocated is the largest response body read into a buffer of its
+// Content-Length.
+const maxPreallocated = 1 << 20
+
+func (c *client) read(req *http.Request) (*http.Response, []byte, error) {
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	// We don't care about the status code. Only if we can decode JSON.
-	body, err := ioutil.ReadAll(resp.Body)
+	// Reading into a buffer of the announced length saves growing it. The
+	// length is capped as it comes from the server.
+	var buf bytes.Buffer
+	if n := resp.ContentLength; n > 0 && n <= maxPreallocated {
+		buf.Grow(int(n) + bytes.MinRead)
+	}
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return nil, nil, err
+	}
+	return resp, buf.Bytes(), nil
+}
+
+// do sends req and decodes the response payload into v, which may be nil if
+// no payload is expected. The cursor of the response is returned.
+func (c *client) do(req *http.Request, v interface{}) (string, error) {
+	o := callOptionsFromContext(req.Context())
+	if o.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), o.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	if c.credentials != nil {
+		creds := c.credentials.acquire()
+		defer creds.inFlight.Done()
+		req.SetBasicAuth(creds.user, creds.pass)
+	}
+
+	var (
+		resp *http.Response
+		body []byte
+		err  error
+	)
+	if c.retries > 0 && !o.noRetry {
+		resp, body, err = c.sendRetry(req)
+	} else {
+		resp, body, err = c.sendOnce(req)
+	}
 	if err != nil {
-		return "", nil, err
+		return "", err
 	}
 
+	// We don't care about the status code. Only if we can decode the body.
 	// Check if no response expected.
 	if len(body) == 0 && resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return "", nil, nil
+		return "", nil
 	}
 
-	obj := &object{}
-	if err := json.Unmarshal(body, obj); err != nil {
-		return "", nil, fmt.Errorf("%v: %s", req.URL, body)
+	codec := c.responseCodec(resp)
+	typ, next, payload, err := codec.UnmarshalObject(body)
+	if err != nil {
+		apiErr := newAPIError(req, resp, body)
+		if resp.StatusCode == http.StatusNotFound {
+			apiErr.Message = "not found"
+		} else {
+			apiErr.Message = fmt.Sprintf("%v: %s", req.URL, body)
+		}
+		return "", apiErr
 	}
 
-	if obj.Type == "errors" {
-		return "", nil, doError(obj)
+	if typ == "errors" {
+		return "", doError(codec, payload, newAPIError(req, resp, body))
+	}
+	if v != nil {
+		if err := codec.Unmarshal(payload, v); err != nil {
+			return "", err
+		}
+		if c.location != nil {
+			c.localize(v)
+		}
 	}
-	return obj.Next, obj.Payload, nil
+	return next, nil
 }
 
-func doError(obj *object) error {
+func doError(codec Codec, data []byte, apiErr *APIError) error {
 	payload := make([]struct {
-		Message string
+		Code    string `json:"code"`
+		Message string `json:"message"`
 	}, 0, 1)
-	if err := json.Unmarshal(obj.Payload, &payload); err != nil {
+	if err := codec.Unmarshal(data, &payload); err != nil {
 		return err
 	}
-	return errors.New(payload[0].Message)
-}
-
-func accountsFromPayload(payload json.RawMessage) ([]Account, error) {
-	accs := []Account{}
-	if err := json.Unmarshal(payload, &accs); err != nil {
-		return nil, err
+	if len(payload) == 0 {
+		apiErr.Message = "unknown error"
+		return apiErr
 	}
-	return accs, nil
+	apiErr.Code = payload[0].Code
+	apiErr.Message = payload[0].Message
+	return apiErr
 }
 
-func accountFromPayload(payload json.RawMessage) (Account, error) {
-	accs, err := accountsFromPayload(payload)
-	if err != nil {
-		return Account{}, err
-	}
+func accountFromPayload(accs []Account) (Account, error) {
 	if len(accs) != 1 {
 		return Account{}, errors.New("expected one account")
 	}
 	return accs[0], nil
 }
 
-// CreateAccount creates a new account. See
+// CreateAccount calls CreateAccountContext with a background context.
+func (c *client) CreateAccount(options ...option) (Account, error) {
+	return c.CreateAccountContext(context.Background(), options...)
+}
+
+// CreateAccountContext creates a new account. See
 // https://rtwire.com/docs#post-accounts for more information.
-func (c *client) CreateAccount() (Account, error) {
-	urlStr := fmt.Sprintf("%s/accounts/", c.url)
-	req, err := http.NewRequest("POST", urlStr, nil)
+func (c *client) CreateAccountContext(ctx context.Context,
+	options ...option) (Account, error) {
+	o, err := applyOptions(options)
 	if err != nil {
 		return Account{}, err
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	req.Header.Set("Accept", "application/json")
-	_, payload, err := c.do(req)
-	if err != nil {
-		return Account{}, err
+	var body interface{}
+	if !o.account.empty() {
+		body = o.account
 	}
-	return accountFromPayload(payload)
-}
 
-// Account returns the account specified by id. See
-// https://rtwire.com/docs#get-account for more information.
-func (c *client) Account(id int64) (Account, error) {
-	urlStr := fmt.Sprintf("%s/accounts/%d", c.url, id)
-	req, err := http.NewRequest("GET", urlStr, nil)
+	urlStr := fmt.Sprintf("%s/accounts/", c.url)
+	req, err := c.request(ctx, "POST", urlStr, body, options)
 	if err != nil {
 		return Account{}, err
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	req.Header.Set("Accept", "application/json")
-	_, payload, err := c.do(req)
-	if err != nil {
+	accs := []Account{}
+	if _, err := c.do(req, &accs); err != nil {
 		return Account{}, err
 	}
-
-	return accountFromPayload(payload)
+	return accountFromPayload(accs)
 }
 
-// Accounts returns a cursor for the next set of accouts, a list of accounts and// any errors which may have occured. Next() can be used to cursor through the
-// next set of accounts by passing in the previous cursor value. Limit() can be
-// used to limit the number of accounts that are returned in one call. See
-// https://rtwire.com/docs#get-accounts for more information.
-func (c *client) Accounts(options ...option) (string, []Account, error) {
+// UpdateAccount calls UpdateAccountContext with a background context.
+func (c *client) UpdateAccount(accountID int64,
+	options ...option) (Account, error) {
+	return c.UpdateAccountContext(context.Background(), accountID,
+		options...)
+}
 
-	urlStr := fmt.Sprintf("%s/accounts/", c.url)
-	url, err := url.Parse(urlStr)
+// UpdateAccountContext sets the label or metadata of accountID to those given
+// with the Label and Metadata options, leaving fields without an option
+// unchanged, and returns the updated account. Labels allow an account to be
+// matched to a customer without a separate lookup table.
+func (c *client) UpdateAccountContext(ctx context.Context, accountID int64,
+	options ...option) (Account, error) {
+	if err := validateID("accountID", accountID); err != nil {
+		return Account{}, err
+	}
+	o, err := applyOptions(options)
 	if err != nil {
-		return "", nil, err
+		return Account{}, err
 	}
-
-	for _, op := range options {
-		if err := op(url); err != nil {
-			return "", nil, err
-		}
+	if o.account.empty() {
+		return Account{}, &ValidationError{"options",
+			"Label or Metadata must be given"}
 	}
 
-	req, err := http.NewRequest("GET", url.String(), nil)
+	urlStr := fmt.Sprintf("%s/accounts/%d", c.url, accountID)
+	req, err := c.request(ctx, "PUT", urlStr, o.account, options)
 	if err != nil {
-		return "", nil, err
+		return Account{}, err
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	req.Header.Set("Accept", "application/json")
-	next, payload, err := c.do(req)
-	if err != nil {
-		return "", nil, err
+	accs := []Account{}
+	if _, err := c.do(req, &accs); err != nil {
+		return Account{}, err
 	}
+	return accountFromPayload(accs)
+}
 
-	accs, err := accountsFromPayload(payload)
-	if err != nil {
-		return "", nil, err
-	}
-	return next, accs, err
+// CloseAccount calls CloseAccountContext with a background context.
+func (c *client) CloseAccount(accountID int64, options ...option) error {
+	return c.CloseAccountContext(context.Background(), accountID, options...)
 }
 
-// CreateAddress creates a public key hash address associated with accountID.
-// Any bitcoins transfered to that address will credit the account associated
-// with accountID. See https://rtwire.com/docs#post-addresses for more
-// information.
-func (c *client) CreateAddress(accountID int64) (string, error) {
-	urlStr := fmt.Sprintf("%s/accounts/%d/addresses/", c.url, accountID)
-	req, err := http.NewRequest("POST", urlStr, nil)
-	if err != nil {
-		return "", err
+// CloseAccountContext closes accountID, for example once its customer has
+// left. RTWire then refuses to create addresses for the account and to
+// transfer or debit funds to or from it with ErrAccountClosed; the account and
+// its transactions can still be read, with Closed set. Funds should be moved
+// out of the account before it is closed.
+func (c *client) CloseAccountContext(ctx context.Context, accountID int64,
+	options ...option) error {
+	if err := validateID("accountID", accountID); err != nil {
+		return err
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	req.Header.Set("Accept", "application/json")
-	_, payload, err := c.do(req)
+
+	urlStr := fmt.Sprintf("%s/accounts/%d", c.url, accountID)
+	req, err := c.request(ctx, "DELETE", urlStr, nil, options)
 	if err != nil {
-		return "", err
+		return err
 	}
-	addrs := []address{}
-	if err := json.Unmarshal(payload, &addrs); err != nil {
-		return "", err
+	if _, err := c.do(req, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateAccounts calls CreateAccountsContext with a background context.
+func (c *client) CreateAccounts(n int) ([]Account, BatchResult) {
+	return c.CreateAccountsContext(context.Background(), n)
+}
+
+// CreateAccountsContext creates n accounts. RTWire has no bulk endpoint so
+// accounts are created with up to WithBatchConcurrency requests in flight.
+// Items not started when ctx is done are left not attempted. Failed items can
+// be retried with BatchResult.Retry.
+func (c *client) CreateAccountsContext(ctx context.Context, n int) ([]Account,
+	BatchResult) {
+	if err := validateCount("n", n); err != nil {
+		return nil, BatchResult{err: err}
+	}
+	accs := make([]Account, n)
+	result := newBatchResult(n)
+
+	concurrency := c.concurrency
+	if concurrency < 1 {
+		concurrency = defaultBatchConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			acc, err := c.CreateAccountContext(ctx)
+			accs[i] = acc
+			result.set(i, 0, err)
+		}(i)
+	}
+	wg.Wait()
+	return accs, result
+}
+
+// Account calls AccountContext with a background context.
+func (c *client) Account(id int64, options ...option) (Account, error) {
+	return c.AccountContext(context.Background(), id, options...)
+}
+
+// AccountContext returns the account specified by id. See
+// https://rtwire.com/docs#get-account for more information.
+func (c *client) AccountContext(ctx context.Context, id int64,
+	options ...option) (Account, error) {
+	if err := validateID("accountID", id); err != nil {
+		return Account{}, err
+	}
+
+	urlStr := fmt.Sprintf("%s/accounts/%d", c.url, id)
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
+	if err != nil {
+		return Account{}, err
+	}
+	accs := []Account{}
+	if _, err := c.do(req, &accs); err != nil {
+		return Account{}, err
+	}
+
+	return accountFromPayload(accs)
+}
+
+// Accounts calls AccountsContext with a background context.
+func (c *client) Accounts(options ...option) (string, []Account, error) {
+	return c.AccountsContext(context.Background(), options...)
+}
+
+// AccountsContext returns a cursor for the next set of accouts, a list of
+// accounts and any errors which may have occured. Next() can be used to cursor
+// through the next set of accounts by passing in the previous cursor value.
+// Limit() can be used to limit the number of accounts that are returned in one
+// call. See https://rtwire.com/docs#get-accounts for more information.
+func (c *client) AccountsContext(ctx context.Context, options ...option) (
+	string, []Account, error) {
+
+	urlStr := fmt.Sprintf("%s/accounts/", c.url)
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
+	if err != nil {
+		return "", nil, err
+	}
+	accs := []Account{}
+	next, err := c.do(req, &accs)
+	if err != nil {
+		return "", nil, err
+	}
+	return next, accs, nil
+}
+
+// CreateAddress calls CreateAddressContext with a background context.
+func (c *client) CreateAddress(accountID int64, options ...option) (string,
+	error) {
+	return c.CreateAddressContext(context.Background(), accountID, options...)
+}
+
+// CreateAddressContext creates a public key hash address associated with
+// accountID. Any bitcoins transfered to that address will credit the account
+// associated with accountID. A segwit address is created instead with the
+// AddressFormat option, and an error is returned if RTWire responds with an
+// address of another type. See https://rtwire.com/docs#post-addresses for
+// more information.
+func (c *client) CreateAddressContext(ctx context.Context, accountID int64,
+	options ...option) (string, error) {
+	if err := validateID("accountID", accountID); err != nil {
+		return "", err
+	}
+	o, err := applyOptions(options)
+	if err != nil {
+		return "", err
+	}
+
+	var body interface{}
+	if o.address != (addressFields{}) {
+		body = o.address
+	}
+
+	urlStr := fmt.Sprintf("%s/accounts/%d/addresses/", c.url, accountID)
+	req, err := c.request(ctx, "POST", urlStr, body, options)
+	if err != nil {
+		return "", err
+	}
+	addrs := []address{}
+	if _, err := c.do(req, &addrs); err != nil {
+		return "", err
 	}
 
 	if len(addrs) != 1 {
 		return "", errors.New("expected one address")
 	}
-	return addrs[0].Address, nil
+
+	addr := addrs[0].Address
+	if o.address.Type != "" && typeOf(addr) != o.address.Type {
+		return "", fmt.Errorf("expected %s address for account %d, got %s",
+			o.address.Type, accountID, addr)
+	}
+	if c.addressVerifier != nil {
+		if err := c.addressVerifier.VerifyAddress(accountID, addr); err != nil {
+			return "", &AddressVerificationError{
+				AccountID: accountID,
+				Address:   addr,
+				Err:       err,
+			}
+		}
+	}
+	return addr, nil
 }
 
-// AccountTransactions list all the transactions involving accountID. As there
-// may be many transactions a paging system is used. The first returned value
-// is a cursor for the next set of results. Next() with the previous cursor can
-// be used as an option to retrieve the next set of results. Limit() can be used
-// to determine how many transactions are returned with one call. The Pending()
-// option can be used to list all pending transactions for the specified
-// account. See https://rtwire.com/docs#get-account-transactions for more
-// information.
-func (c *client) AccountTransactions(accountID int64, options ...option) (
-	string, []Transaction, error) {
-	urlStr := fmt.Sprintf("%s/accounts/%d/transactions/", c.url, accountID)
-	url, err := url.Parse(urlStr)
+// CreateAddresses calls CreateAddressesContext with a background context.
+func (c *client) CreateAddresses(accountIDs []int64) ([]string, BatchResult) {
+	return c.CreateAddressesContext(context.Background(), accountIDs)
+}
+
+// CreateAddressesContext creates an address for each account in accountIDs.
+// Failed items can be retried with BatchResult.Retry.
+func (c *client) CreateAddressesContext(ctx context.Context,
+	accountIDs []int64) ([]string, BatchResult) {
+	addrs := make([]string, len(accountIDs))
+	result := newBatchResult(len(accountIDs))
+	for i, accountID := range accountIDs {
+		addr, err := c.CreateAddressContext(ctx, accountID)
+		addrs[i] = addr
+		result.set(i, 0, err)
+	}
+	return addrs, result
+}
+
+// AccountAddresses calls AccountAddressesContext with a background context.
+func (c *client) AccountAddresses(accountID int64, options ...option) (
+	string, []Address, error) {
+	return c.AccountAddressesContext(context.Background(), accountID,
+		options...)
+}
+
+// AccountAddressesContext returns a cursor and a page of the deposit addresses
+// created for accountID, so that an address can be matched to its account by
+// support tooling. Pages are requested with the Limit and Next options, as for
+// AccountTransactions.
+func (c *client) AccountAddressesContext(ctx context.Context,
+	accountID int64, options ...option) (string, []Address, error) {
+	if err := validateID("accountID", accountID); err != nil {
+		return "", nil, err
+	}
+
+	urlStr := fmt.Sprintf("%s/accounts/%d/addresses/", c.url, accountID)
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
+	if err != nil {
+		return "", nil, err
+	}
+	addrs := []Address{}
+	next, err := c.do(req, &addrs)
 	if err != nil {
 		return "", nil, err
 	}
+	return next, addrs, nil
+}
 
-	for _, op := range options {
-		if err := op(url); err != nil {
-			return "", nil, err
-		}
+// AccountByAddress calls AccountByAddressContext with a background context.
+func (c *client) AccountByAddress(address string, options ...option) (int64,
+	error) {
+	return c.AccountByAddressContext(context.Background(), address,
+		options...)
+}
+
+// AccountByAddressContext returns the ID of the account that the deposit
+// address was created for, for example to find who an unexpected on-chain
+// payment was meant for. ErrNotFound is returned if the address was not
+// created by RTWire.
+func (c *client) AccountByAddressContext(ctx context.Context, address string,
+	options ...option) (int64, error) {
+	if err := validateAddress("address", address, c.network); err != nil {
+		return 0, err
 	}
 
-	req, err := http.NewRequest("GET", url.String(), nil)
+	urlStr := fmt.Sprintf("%s/addresses/%s", c.url, url.PathEscape(address))
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
 	if err != nil {
+		return 0, err
+	}
+	addrs := []Address{}
+	if _, err := c.do(req, &addrs); err != nil {
+		return 0, err
+	}
+	if len(addrs) != 1 || addrs[0].AccountID <= 0 {
+		return 0, errors.New("expected one address with an account")
+	}
+	return addrs[0].AccountID, nil
+}
+
+// FundAddress calls FundAddressContext with a background context.
+func (c *client) FundAddress(address string, value Amount,
+	options ...option) error {
+	return c.FundAddressContext(context.Background(), address, value,
+		options...)
+}
+
+// FundAddressContext credits value satoshi to the deposit address, as a
+// bitcoin transaction paying it would, so that integration tests can seed
+// balances. It is only accepted by test networks such as TestNet3URL and by
+// mocks of RTWire; ErrMainNet is returned without making a request on a
+// client of MainNetURL.
+func (c *client) FundAddressContext(ctx context.Context, address string,
+	value Amount, options ...option) error {
+	if strings.TrimSuffix(c.url, "/") == MainNetURL {
+		return ErrMainNet
+	}
+	if err := validate(
+		validateAddress("address", address, c.network),
+		validateValue(value),
+	); err != nil {
+		return err
+	}
+
+	urlStr := fmt.Sprintf("%s/addresses/%s", c.url, url.PathEscape(address))
+	fundReq := struct {
+		Value Amount `json:"value"`
+	}{value}
+	req, err := c.request(ctx, "POST", urlStr, fundReq, options)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(req, nil)
+	return err
+}
+
+// AccountTransactions calls AccountTransactionsContext with a background
+// context.
+func (c *client) AccountTransactions(accountID int64, options ...option) (
+	string, []Transaction, error) {
+	return c.AccountTransactionsContext(context.Background(), accountID,
+		options...)
+}
+
+// AccountTransactionsContext list all the transactions involving accountID. As
+// there may be many transactions a paging system is used. The first returned
+// value is a cursor for the next set of results. Next() with the previous
+// cursor can be used as an option to retrieve the next set of results. Limit()
+// can be used to determine how many transactions are returned with one call.
+// The Pending() option can be used to list all pending transactions for the
+// specified account. See https://rtwire.com/docs#get-account-transactions for
+// more information.
+func (c *client) AccountTransactionsContext(ctx context.Context,
+	accountID int64, options ...option) (
+	string, []Transaction, error) {
+	if err := validateID("accountID", accountID); err != nil {
 		return "", nil, err
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	req.Header.Set("Accept", "application/json")
-	next, payload, err := c.do(req)
+
+	urlStr := fmt.Sprintf("%s/accounts/%d/transactions/", c.url, accountID)
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
 	if err != nil {
 		return "", nil, err
 	}
 	txns := []Transaction{}
-	if err := json.Unmarshal(payload, &txns); err != nil {
+	next, err := c.do(req, &txns)
+	if err != nil {
 		return "", nil, err
 	}
+	if types := callOptionsFromContext(req.Context()).types; types != 0 {
+		filtered := txns[:0]
+		for _, tx := range txns {
+			if types.has(tx.Type) {
+				filtered = append(filtered, tx)
+			}
+		}
+		txns = filtered
+	}
 	return next, txns, nil
 }
 
-// CreateTransactionIDs creates transaction ids that can be used to transfer
-// and debit satoshi from RTWire accounts. A transaction ID can only be used
-// successfully once. Allowing clients to create transaction IDs prior to
+// CreateTransactionIDs calls CreateTransactionIDsContext with a background
+// context.
+func (c *client) CreateTransactionIDs(n int, options ...option) ([]int64,
+	error) {
+	return c.CreateTransactionIDsContext(context.Background(), n, options...)
+}
+
+// CreateTransactionIDsContext creates transaction ids that can be used to
+// transfer and debit satoshi from RTWire accounts. A transaction ID can only be
+// used successfully once. Allowing clients to create transaction IDs prior to
 // creating transactions through debits and transfers ensures that transactions
-// can be made idempotent. See https://rtwire.com/docs#put-transactions for more
-// information.
-func (c *client) CreateTransactionIDs(n int) ([]int64, error) {
+// can be made idempotent. IDs are created by the generator set with
+// WithIDGenerator, if any, without contacting RTWire. See
+// https://rtwire.com/docs#put-transactions for more information.
+func (c *client) CreateTransactionIDsContext(ctx context.Context, n int,
+	options ...option) ([]int64, error) {
+	if err := validateCount("n", n); err != nil {
+		return nil, err
+	}
+	if c.idGenerator != nil {
+		txIDs := make([]int64, n)
+		for i := range txIDs {
+			id, err := c.idGenerator.NewID()
+			if err != nil {
+				return nil, err
+			}
+			txIDs[i] = id
+		}
+		return txIDs, nil
+	}
+
 	urlStr := fmt.Sprintf("%s/transactions/", c.url)
 
-	var postBody bytes.Buffer
-	if err := json.NewEncoder(&postBody).Encode(struct {
+	req, err := c.request(ctx, "POST", urlStr, struct {
 		N int `json:"n"`
 	}{
 		N: n,
-	}); err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", urlStr, &postBody)
+	}, options)
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Content-Type", "application/json")
 
-	_, payload, err := c.do(req)
-	if err != nil {
-		return nil, err
-	}
 	txns := make([]Transaction, 0, n)
-	if err := json.Unmarshal(payload, &txns); err != nil {
+	if _, err := c.do(req, &txns); err != nil {
 		return nil, err
 	}
 
@@ -442,204 +1522,747 @@ func (c *client) CreateTransactionIDs(n int) ([]int64, error) {
 	return txIDs, nil
 }
 
-// Transaction returns transaction information for transaction id. See
+// Transaction calls TransactionContext with a background context.
+func (c *client) Transaction(id int64, options ...option) (Transaction,
+	error) {
+	return c.TransactionContext(context.Background(), id, options...)
+}
+
+// TransactionContext returns transaction information for transaction id. See
 // https://rtwire.com/docs#get-transaction for more information.
-func (c *client) Transaction(id int64) (Transaction, error) {
-	urlStr := fmt.Sprintf("%s/transactions/%d", c.url, id)
-	req, err := http.NewRequest("GET", urlStr, nil)
-	if err != nil {
+func (c *client) TransactionContext(ctx context.Context, id int64,
+	options ...option) (Transaction, error) {
+	if err := validateID("txID", id); err != nil {
 		return Transaction{}, err
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	req.Header.Set("Accept", "application/json")
-	_, payload, err := c.do(req)
+
+	urlStr := fmt.Sprintf("%s/transactions/%d", c.url, id)
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
 	if err != nil {
 		return Transaction{}, err
 	}
 
 	txns := make([]Transaction, 0, 1)
-	if err := json.Unmarshal(payload, &txns); err != nil {
+	if _, err := c.do(req, &txns); err != nil {
 		return Transaction{}, err
 	}
 
+	if len(txns) == 0 {
+		return Transaction{}, ErrNotFound
+	}
 	return txns[0], nil
 }
 
-// Transfer transfers value satoshi from fromAccountID to toAccountID. A
-// transaction ID, txID can be obtained from CreateTransactionIDs. See
-// https://rtwire.com/docs#put-transactions for more information.
-func (c *client) Transfer(txID, fromAccountID, toAccountID, value int64) error {
+// TransactionsByHash calls TransactionsByHashContext with a background
+// context.
+func (c *client) TransactionsByHash(txHash string, options ...option) (
+	[]Transaction, error) {
+	return c.TransactionsByHashContext(context.Background(), txHash,
+		options...)
+}
 
-	urlStr := fmt.Sprintf("%s/transactions/", c.url)
+// TransactionsByHashContext returns the RTWire transactions associated with
+// the bitcoin transaction hash txHash, as listed in their TxHashes, so that a
+// transaction found on a block explorer can be traced back to the ledger. A
+// debit paying several outputs, or credits to several accounts, share a hash.
+// An empty list is returned if no transaction has the hash.
+func (c *client) TransactionsByHashContext(ctx context.Context, txHash string,
+	options ...option) ([]Transaction, error) {
+	if err := validateTxHash("txHash", txHash); err != nil {
+		return nil, err
+	}
 
-	transferReq := struct {
-		ID            int64 `json:"id"`
-		FromAccountID int64 `json:"fromAccountID"`
-		ToAccountID   int64 `json:"toAccountID"`
-		Value         int64 `json:"value"`
-	}{
-		ID:            txID,
-		FromAccountID: fromAccountID,
-		ToAccountID:   toAccountID,
-		Value:         value,
+	query := url.Values{"txHash": {strings.ToLower(txHash)}}
+	urlStr := fmt.Sprintf("%s/transactions/?%s", c.url, query.Encode())
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
+	if err != nil {
+		return nil, err
 	}
+	txns := []Transaction{}
+	if _, err := c.do(req, &txns); err != nil {
+		return nil, err
+	}
+	return txns, nil
+}
 
-	var putBody bytes.Buffer
-	if err := json.NewEncoder(&putBody).Encode(transferReq); err != nil {
-		return err
+// RawTransaction calls RawTransactionContext with a background context.
+func (c *client) RawTransaction(txHash string, options ...option) (
+	RawTransaction, error) {
+	return c.RawTransactionContext(context.Background(), txHash, options...)
+}
+
+// RawTransactionContext returns the bitcoin transaction with hash txHash as
+// serialized on the network, so that payouts can be checked without a block
+// explorer. The client decodes the transaction itself and fails if it does
+// not hash to txHash, so RTWire cannot report different inputs or outputs
+// than those of the transaction on chain. ErrNotFound is returned if RTWire
+// does not know the transaction.
+func (c *client) RawTransactionContext(ctx context.Context, txHash string,
+	options ...option) (RawTransaction, error) {
+	if err := validateTxHash("txHash", txHash); err != nil {
+		return RawTransaction{}, err
 	}
+	txHash = strings.ToLower(txHash)
 
-	req, err := http.NewRequest("PUT", urlStr, &putBody)
+	urlStr := fmt.Sprintf("%s/rawtransactions/%s", c.url, txHash)
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
 	if err != nil {
-		return err
+		return RawTransaction{}, err
+	}
+	raws := []struct {
+		Hex string `json:"hex"`
+	}{}
+	if _, err := c.do(req, &raws); err != nil {
+		return RawTransaction{}, err
+	}
+	if len(raws) == 0 {
+		return RawTransaction{}, ErrNotFound
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	req.Header.Set("Content-Type", "application/json")
 
-	if _, _, err := c.do(req); err != nil {
+	tx, err := decodeRawTransaction(raws[0].Hex)
+	if err != nil {
+		return RawTransaction{}, fmt.Errorf("transaction %s: %w", txHash, err)
+	}
+	if tx.Hash != txHash {
+		return RawTransaction{}, fmt.Errorf("transaction %s: hashes to %s",
+			txHash, tx.Hash)
+	}
+	return tx, nil
+}
 
-		switch err.Error() {
-		case "insufficient funds":
-			return ErrInsufficientFunds
+const (
+	// waitForTransactionTimeout bounds WaitForTransaction when ctx has no
+	// deadline.
+	waitForTransactionTimeout = 30 * time.Second
+
+	minTransactionPoll = 100 * time.Millisecond
+	maxTransactionPoll = 2 * time.Second
+)
+
+// WaitForTransaction polls Transaction with exponential backoff until the
+// transaction is found, a different error occurs or ctx is done. If ctx has no
+// deadline polling stops after 30 seconds.
+func (c *client) WaitForTransaction(ctx context.Context, id int64) (
+	Transaction, error) {
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitForTransactionTimeout)
+		defer cancel()
+	}
+
+	poll := minTransactionPoll
+	for {
+		tx, err := c.TransactionContext(ctx, id)
+		if !errors.Is(err, ErrNotFound) {
+			return tx, err
+		}
+
+		timer := time.NewTimer(poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Transaction{}, ctx.Err()
+		case <-timer.C:
+		}
+
+		if poll *= 2; poll > maxTransactionPoll {
+			poll = maxTransactionPoll
 		}
-		return err
 	}
-	return nil
 }
 
-// Debit debits value satoshi from fromAccountID to a public key hash address
-// toAddress. A transaction ID, txID, can be obtained from CreateTransactionIDs.
-// See https://rtwire.com/docs#put-transactions for more information.
+// Transfer calls TransferContext with a background context.
+func (c *client) Transfer(txID, fromAccountID, toAccountID int64,
+	value Amount, options ...option) (Transaction, error) {
+	return c.TransferContext(context.Background(), txID, fromAccountID,
+		toAccountID, value, options...)
+}
+
+// TransferContext transfers value satoshi from fromAccountID to toAccountID. A
+// transaction ID, txID can be obtained from CreateTransactionIDs.
+//
+// The transfer transaction returned by RTWire is returned, including
+// FromAccountBalance and ToAccountBalance, so the balances after the transfer
+// are known without reading the accounts back, which could race other
+// transfers. If RTWire responds without the transaction, only the fields known
+// from the request are set. See https://rtwire.com/docs#put-transactions for
+// more information.
+func (c *client) TransferContext(ctx context.Context, txID, fromAccountID,
+	toAccountID int64, value Amount, options ...option) (Transaction,
+	error) {
+
+	if err := validate(
+		validateID("txID", txID),
+		validateID("fromAccountID", fromAccountID),
+		validateID("toAccountID", toAccountID),
+		validateValue(value),
+	); err != nil {
+		return Transaction{}, err
+	}
+
+	urlStr := c.url + "/transactions/"
+
+	body := transferBody(txID, fromAccountID, toAccountID, value)
+	req, err := c.request(ctx, "PUT", urlStr, body, options)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	txns := []Transaction{}
+	if _, err := c.do(req, &txns); err != nil {
+		return c.resolveTxIDUsed(ctx, req, err, txID,
+			func(tx Transaction) bool {
+				return tx.Type == "transfer" &&
+					tx.FromAccountID == fromAccountID &&
+					tx.ToAccountID == toAccountID && tx.Value == value
+			})
+	}
+	if len(txns) == 0 {
+		return Transaction{
+			ID:            txID,
+			Type:          "transfer",
+			FromAccountID: fromAccountID,
+			ToAccountID:   toAccountID,
+			Value:         value,
+		}, nil
+	}
+	return txns[0], nil
+}
+
+// transferBody returns the JSON body of a transfer. It is encoded by hand as
+// transfers are made far more often than other calls.
+func transferBody(txID, fromAccountID, toAccountID int64,
+	value Amount) []byte {
+	b := make([]byte, 0, 96)
+	b = append(b, `{"id":`...)
+	b = strconv.AppendInt(b, txID, 10)
+	b = append(b, `,"fromAccountID":`...)
+	b = strconv.AppendInt(b, fromAccountID, 10)
+	b = append(b, `,"toAccountID":`...)
+	b = strconv.AppendInt(b, toAccountID, 10)
+	b = append(b, `,"value":`...)
+	b = strconv.AppendInt(b, int64(value), 10)
+	return append(b, '}')
+}
+
+// Debit calls DebitContext with a background context.
 func (c *client) Debit(txID, fromAccountID int64, toAddress string,
-	value int64) error {
+	value Amount, options ...option) (Transaction, error) {
+	return c.DebitContext(context.Background(), txID, fromAccountID, toAddress,
+		value, options...)
+}
 
-	urlStr := fmt.Sprintf("%s/transactions/", c.url)
+// DebitContext debits value satoshi from fromAccountID to toAddress, a
+// base58, bech32 or, for taproot, bech32m address. A transaction ID, txID,
+// can be obtained from CreateTransactionIDs. The miner fee is chosen by
+// RTWire unless set with the FeePerByte or ConfirmationTarget option.
+//
+// The debit transaction returned by RTWire is returned, including TxHashes and
+// TxOutIndex, so an explorer link can be shown without reading it back. If
+// RTWire responds without the transaction, only the fields known from the
+// request are set. See https://rtwire.com/docs#put-transactions for more
+// information.
+func (c *client) DebitContext(ctx context.Context, txID, fromAccountID int64,
+	toAddress string, value Amount, options ...option) (Transaction,
+	error) {
+
+	if err := validate(
+		validateID("txID", txID),
+		validateID("fromAccountID", fromAccountID),
+		validateAddress("toAddress", toAddress, c.network),
+		validateValue(value),
+	); err != nil {
+		return Transaction{}, err
+	}
+	o, err := applyOptions(options)
+	if err != nil {
+		return Transaction{}, err
+	}
+	if o.feePerByte != 0 && o.confTarget != 0 {
+		return Transaction{}, &ValidationError{"fee",
+			"FeePerByte and ConfirmationTarget are exclusive"}
+	}
 
-	var body bytes.Buffer
-	if err := json.NewEncoder(&body).Encode(struct {
-		TxID          int64  `json:"id"`
-		FromAccountID int64  `json:"fromAccountID"`
-		ToAddress     string `json:"toAddress"`
-		Value         int64  `json:"value"`
+	urlStr := c.url + "/transactions/"
+
+	req, err := c.request(ctx, "PUT", urlStr, struct {
+		TxID               int64  `json:"id"`
+		FromAccountID      int64  `json:"fromAccountID"`
+		ToAddress          string `json:"toAddress"`
+		Value              Amount `json:"value"`
+		FeePerByte         int64  `json:"feePerByte,omitempty"`
+		ConfirmationTarget int    `json:"confirmationTarget,omitempty"`
 	}{
-		TxID:          txID,
-		FromAccountID: fromAccountID,
-		ToAddress:     toAddress,
-		Value:         value,
-	}); err != nil {
-		return err
+		TxID:               txID,
+		FromAccountID:      fromAccountID,
+		ToAddress:          toAddress,
+		Value:              value,
+		FeePerByte:         o.feePerByte,
+		ConfirmationTarget: o.confTarget,
+	}, options)
+	if err != nil {
+		return Transaction{}, err
 	}
 
-	req, err := http.NewRequest("PUT", urlStr, &body)
+	txns := []Transaction{}
+	if _, err := c.do(req, &txns); err != nil {
+		return c.resolveTxIDUsed(ctx, req, err, txID,
+			func(tx Transaction) bool {
+				return tx.Type == "debit" &&
+					tx.FromAccountID == fromAccountID && tx.Value == value
+			})
+	}
+	if len(txns) == 0 {
+		return Transaction{
+			ID:            txID,
+			Type:          "debit",
+			FromAccountID: fromAccountID,
+			Value:         value,
+		}, nil
+	}
+	return txns[0], nil
+}
+
+// DebitMany calls DebitManyContext with a background context.
+func (c *client) DebitMany(txID, fromAccountID int64, outputs []Output,
+	options ...option) (Transaction, error) {
+	return c.DebitManyContext(context.Background(), txID, fromAccountID,
+		outputs, options...)
+}
+
+// DebitManyContext debits the total value of outputs from fromAccountID and
+// pays each output's address its value in a single bitcoin transaction. The
+// debit is all or nothing: either every output is paid or none is. A
+// transaction ID, txID, can be obtained from CreateTransactionIDs and fees are
+// set as for Debit.
+//
+// The debit transaction returned by RTWire is returned, with Value set to the
+// total paid. If RTWire responds without the transaction, only the fields
+// known from the request are set. See https://rtwire.com/docs#put-transactions
+// for more information.
+func (c *client) DebitManyContext(ctx context.Context, txID,
+	fromAccountID int64, outputs []Output,
+	options ...option) (Transaction, error) {
+
+	errs := []error{
+		validateID("txID", txID),
+		validateID("fromAccountID", fromAccountID),
+	}
+	if len(outputs) == 0 {
+		errs = append(errs, &ValidationError{"outputs", "must not be empty"})
+	}
+	var (
+		total    Amount
+		overflow error
+	)
+	for i, out := range outputs {
+		errs = append(errs,
+			validateAddress(fmt.Sprintf("outputs[%d].address", i),
+				out.Address, c.network),
+			validateValue(out.Value))
+		if overflow == nil {
+			total, overflow = total.Add(out.Value)
+		}
+	}
+	if overflow != nil {
+		errs = append(errs, &ValidationError{"value", "total overflows"})
+	} else {
+		errs = append(errs, validateValue(total))
+	}
+	if err := validate(errs...); err != nil {
+		return Transaction{}, err
+	}
+	o, err := applyOptions(options)
 	if err != nil {
-		return err
+		return Transaction{}, err
+	}
+	if o.feePerByte != 0 && o.confTarget != 0 {
+		return Transaction{}, &ValidationError{"fee",
+			"FeePerByte and ConfirmationTarget are exclusive"}
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	req.Header.Set("Content-Type", "application/json")
 
-	if _, _, err := c.do(req); err != nil {
-		return err
+	urlStr := fmt.Sprintf("%s/transactions/", c.url)
+
+	req, err := c.request(ctx, "PUT", urlStr, struct {
+		TxID               int64    `json:"id"`
+		FromAccountID      int64    `json:"fromAccountID"`
+		Outputs            []Output `json:"outputs"`
+		FeePerByte         int64    `json:"feePerByte,omitempty"`
+		ConfirmationTarget int      `json:"confirmationTarget,omitempty"`
+	}{
+		TxID:               txID,
+		FromAccountID:      fromAccountID,
+		Outputs:            outputs,
+		FeePerByte:         o.feePerByte,
+		ConfirmationTarget: o.confTarget,
+	}, options)
+	if err != nil {
+		return Transaction{}, err
 	}
-	return nil
+
+	txns := []Transaction{}
+	if _, err := c.do(req, &txns); err != nil {
+		return c.resolveTxIDUsed(ctx, req, err, txID,
+			func(tx Transaction) bool {
+				return tx.Type == "debit" &&
+					tx.FromAccountID == fromAccountID && tx.Value == total
+			})
+	}
+	if len(txns) == 0 {
+		return Transaction{
+			ID:            txID,
+			Type:          "debit",
+			FromAccountID: fromAccountID,
+			Value:         total,
+		}, nil
+	}
+	return txns[0], nil
 }
 
-// Fees returns the current estimated miner fees. This gives an idea of how much
-// a debit will cost in miner fees.See https://rtwire.com/docs#get-fees for more
-// information.
-func (c *client) Fees() ([]Fee, error) {
-	req, err := http.NewRequest("GET", c.url+"/fees/", nil)
+// Fees calls FeesContext with a background context.
+func (c *client) Fees(options ...option) ([]Fee, error) {
+	return c.FeesContext(context.Background(), options...)
+}
+
+// FeesContext returns the current estimated miner fees. This gives an idea of
+// how much a debit will cost in miner fees.See https://rtwire.com/docs#get-fees
+// for more information.
+func (c *client) FeesContext(ctx context.Context, options ...option) ([]Fee,
+	error) {
+	req, err := c.request(ctx, "GET", c.url+"/fees/", nil, options)
 	if err != nil {
 		return nil, err
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	req.Header.Set("Accept", "application/json")
-	_, payload, err := c.do(req)
-	if err != nil {
+
+	fees := []Fee{}
+	if _, err := c.do(req, &fees); err != nil {
 		return nil, err
 	}
+	return fees, nil
+}
+
+// FeeForTarget calls FeeForTargetContext with a background context.
+func (c *client) FeeForTarget(blocks int, options ...option) (Fee, error) {
+	return c.FeeForTargetContext(context.Background(), blocks, options...)
+}
+
+// FeeForTargetContext returns the estimated fee per byte for a transaction to
+// confirm within blocks blocks, the fee a Debit with ConfirmationTarget(blocks)
+// pays, so that payout schedulers can trade cost against speed. blocks must be
+// between 1 and 1008. If RTWire returns several estimates the one of the
+// highest block height is returned.
+func (c *client) FeeForTargetContext(ctx context.Context, blocks int,
+	options ...option) (Fee, error) {
+	if _, err := applyOptions([]option{ConfirmationTarget(blocks)}); err != nil {
+		return Fee{}, err
+	}
+	query := url.Values{"confirmationTarget": {strconv.Itoa(blocks)}}
+	urlStr := fmt.Sprintf("%s/fees/?%s", c.url, query.Encode())
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
+	if err != nil {
+		return Fee{}, err
+	}
 
 	fees := []Fee{}
-	return fees, json.Unmarshal(payload, &fees)
+	if _, err := c.do(req, &fees); err != nil {
+		return Fee{}, err
+	}
+	if len(fees) == 0 {
+		return Fee{}, errors.New("expected a fee")
+	}
+	latest := fees[0]
+	for _, fee := range fees[1:] {
+		if fee.BlockHeight > latest.BlockHeight {
+			latest = fee
+		}
+	}
+	latest.ConfirmationTarget = blocks
+	return latest, nil
+}
+
+// ChainInfo calls ChainInfoContext with a background context.
+func (c *client) ChainInfo(options ...option) (ChainInfo, error) {
+	return c.ChainInfoContext(context.Background(), options...)
 }
 
-// CreateHook creates a web hook. Every time a transaction is potentially
+// ChainInfoContext returns the block RTWire has processed up to. Balances and
+// transactions read after it reflect at least that block, so a reconciliation
+// job can record the height its view of the ledger corresponds to by reading
+// the chain info before the ledger.
+func (c *client) ChainInfoContext(ctx context.Context, options ...option) (
+	ChainInfo, error) {
+	req, err := c.request(ctx, "GET", c.url+"/chain/", nil, options)
+	if err != nil {
+		return ChainInfo{}, err
+	}
+
+	infos := []ChainInfo{}
+	if _, err := c.do(req, &infos); err != nil {
+		return ChainInfo{}, err
+	}
+	if len(infos) == 0 {
+		return ChainInfo{}, errors.New("expected chain info")
+	}
+	return infos[0], nil
+}
+
+// CreateHook calls CreateHookContext with a background context.
+func (c *client) CreateHook(url string, options ...option) error {
+	return c.CreateHookContext(context.Background(), url, options...)
+}
+
+// CreateHookContext creates a web hook. Every time a transaction is potentially
 // credited to an account url will be called. Note that url may be called
-// several times for the same transaction. See
+// several times for the same transaction. Deliveries can be authenticated by
+// creating the hook with HookSecret or HookAuthorization. A *HookURLError is
+// returned if url is not an absolute http or https URL. See
 // https://rtwire.com/docs#post-hooks for more information.
-func (c *client) CreateHook(url string) error {
+func (c *client) CreateHookContext(ctx context.Context, url string,
+	options ...option) error {
 
+	if err := c.checkHookURL(ctx, url); err != nil {
+		return err
+	}
+	o, err := applyOptions(options)
+	if err != nil {
+		return err
+	}
 	urlStr := fmt.Sprintf("%s/hooks/", c.url)
 
-	hookReq := struct {
-		URL string `json:"url"`
-	}{url}
+	hook := o.hook
+	hook.URL = url
+	req, err := c.request(ctx, "POST", urlStr, hook, options)
+	if err != nil {
+		return err
+	}
 
-	body := &bytes.Buffer{}
-	if err := json.NewEncoder(body).Encode(hookReq); err != nil {
+	if _, err := c.do(req, nil); err != nil {
+		if errors.Is(err, ErrHookExists) && o.reconcileHook {
+			return c.reconcileHook(ctx, hook, err, options)
+		}
 		return err
 	}
+	return nil
+}
+
+// reconcileHook makes the registered hook with want's URL match want, after
+// CreateHook failed with err as it already exists.
+func (c *client) reconcileHook(ctx context.Context, want Hook, err error,
+	options []option) error {
+	hooks, listErr := c.HooksContext(ctx, options...)
+	if listErr != nil {
+		return listErr
+	}
+	for _, hook := range hooks {
+		if hook.URL != want.URL {
+			continue
+		}
+		// A listed hook has no secret to compare.
+		if hook.Secret == "" {
+			hook.Secret = want.Secret
+		}
+		if hook == want {
+			return nil
+		}
+		return c.UpdateHookContext(ctx, want, options...)
+	}
+	// The hook is registered but was not listed, so it can't be compared.
+	return err
+}
+
+// Hooks calls HooksContext with a background context.
+func (c *client) Hooks(options ...option) ([]Hook, error) {
+	return c.HooksContext(context.Background(), options...)
+}
+
+// HooksContext lists the registered web hooks. See
+// https://rtwire.com/docs#get-hooks for more information.
+func (c *client) HooksContext(ctx context.Context, options ...option) ([]Hook,
+	error) {
+	urlStr := fmt.Sprintf("%s/hooks/", c.url)
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
+	if err != nil {
+		return nil, err
+	}
+	hooks := []Hook{}
+	if _, err := c.do(req, &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
 
-	req, err := http.NewRequest("POST", urlStr, body)
+// DeleteHook calls DeleteHookContext with a background context.
+func (c *client) DeleteHook(url string, options ...option) error {
+	return c.DeleteHookContext(context.Background(), url, options...)
+}
+
+// DeleteHookContext deletes a web hook with the specified url. See
+// https://rtwire.com/docs#delete-hook for more information.
+func (c *client) DeleteHookContext(ctx context.Context, url string,
+	options ...option) error {
+	encodedURL := base64.URLEncoding.EncodeToString([]byte(url))
+	urlStr := fmt.Sprintf("%s/hooks/%s", c.url, encodedURL)
+	req, err := c.request(ctx, "DELETE", urlStr, nil, options)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.SetBasicAuth(c.user, c.pass)
+	if _, err := c.do(req, nil); err != nil {
+		return err
+	}
+	return nil
+}
 
-	if _, _, err := c.do(req); err != nil {
-		switch err.Error() {
-		case "hook exists":
-			return ErrHookExists
-		}
+// UpdateHook calls UpdateHookContext with a background context.
+func (c *client) UpdateHook(hook Hook, options ...option) error {
+	return c.UpdateHookContext(context.Background(), hook, options...)
+}
+
+// UpdateHookContext replaces the configuration of the registered web hook with
+// hook's URL by hook, in place, so that deliveries continue without the gap
+// left by deleting and recreating it. A *HookURLError is returned if the URL
+// is invalid as for CreateHook.
+func (c *client) UpdateHookContext(ctx context.Context, hook Hook,
+	options ...option) error {
+	if err := c.checkHookURL(ctx, hook.URL); err != nil {
+		return err
+	}
+	encodedURL := base64.URLEncoding.EncodeToString([]byte(hook.URL))
+	urlStr := fmt.Sprintf("%s/hooks/%s", c.url, encodedURL)
+	req, err := c.request(ctx, "PUT", urlStr, hook, options)
+	if err != nil {
+		return err
+	}
+	if _, err := c.do(req, nil); err != nil {
 		return err
 	}
 	return nil
 }
 
-// Hooks lists the registered web hooks. See https://rtwire.com/docs#get-hooks
-// for more information.
-func (c *client) Hooks() ([]Hook, error) {
-	urlStr := fmt.Sprintf("%s/hooks/", c.url)
-	req, err := http.NewRequest("GET", urlStr, nil)
+// CreateAccountHook calls CreateAccountHookContext with a background context.
+func (c *client) CreateAccountHook(accountID int64, url string,
+	options ...option) error {
+	return c.CreateAccountHookContext(context.Background(), accountID, url,
+		options...)
+}
+
+// CreateAccountHookContext creates a web hook for a single account. It behaves
+// as CreateHook but url is only called for transactions crediting or debiting
+// accountID, allowing high value accounts to be monitored separately. See
+// https://rtwire.com/docs#post-account-hooks for more information.
+func (c *client) CreateAccountHookContext(ctx context.Context, accountID int64,
+	url string, options ...option) error {
+
+	if err := validateID("accountID", accountID); err != nil {
+		return err
+	}
+	if err := c.checkHookURL(ctx, url); err != nil {
+		return err
+	}
+	o, err := applyOptions(options)
 	if err != nil {
+		return err
+	}
+	urlStr := fmt.Sprintf("%s/accounts/%d/hooks/", c.url, accountID)
+
+	hook := o.hook
+	hook.URL = url
+	req, err := c.request(ctx, "POST", urlStr, hook, options)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.do(req, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AccountHooks calls AccountHooksContext with a background context.
+func (c *client) AccountHooks(accountID int64, options ...option) ([]Hook,
+	error) {
+	return c.AccountHooksContext(context.Background(), accountID, options...)
+}
+
+// AccountHooksContext lists the web hooks registered for accountID. See
+// https://rtwire.com/docs#get-account-hooks for more information.
+func (c *client) AccountHooksContext(ctx context.Context, accountID int64,
+	options ...option) ([]Hook, error) {
+	if err := validateID("accountID", accountID); err != nil {
 		return nil, err
 	}
-	req.Header.Set("Accept", "application/json")
-	req.SetBasicAuth(c.user, c.pass)
-	_, payload, err := c.do(req)
+	urlStr := fmt.Sprintf("%s/accounts/%d/hooks/", c.url, accountID)
+	req, err := c.request(ctx, "GET", urlStr, nil, options)
 	if err != nil {
 		return nil, err
 	}
 	hooks := []Hook{}
-	return hooks, json.Unmarshal(payload, &hooks)
+	if _, err := c.do(req, &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
 }
 
-// DeleteHook deletes a web hook with the specified url. See
-// https://rtwire.com/docs#delete-hook for more information.
-func (c *client) DeleteHook(url string) error {
+// DeleteAccountHook calls DeleteAccountHookContext with a background context.
+func (c *client) DeleteAccountHook(accountID int64, url string,
+	options ...option) error {
+	return c.DeleteAccountHookContext(context.Background(), accountID, url,
+		options...)
+}
+
+// DeleteAccountHookContext deletes the web hook with the specified url from
+// accountID. See https://rtwire.com/docs#delete-account-hook for more
+// information.
+func (c *client) DeleteAccountHookContext(ctx context.Context, accountID int64,
+	url string, options ...option) error {
+	if err := validateID("accountID", accountID); err != nil {
+		return err
+	}
 	encodedURL := base64.URLEncoding.EncodeToString([]byte(url))
-	urlStr := fmt.Sprintf("%s/hooks/%s", c.url, encodedURL)
-	req, err := http.NewRequest("DELETE", urlStr, nil)
+	urlStr := fmt.Sprintf("%s/accounts/%d/hooks/%s", c.url, accountID,
+		encodedURL)
+	req, err := c.request(ctx, "DELETE", urlStr, nil, options)
 	if err != nil {
 		return err
 	}
-	req.SetBasicAuth(c.user, c.pass)
-	if _, _, err := c.do(req); err != nil {
+	if _, err := c.do(req, nil); err != nil {
 		return err
 	}
 	return nil
 }
 
+// ClientOption configures a client created by New.
+type ClientOption func(c *client)
+
 // New creates a new client. URL can either be MainNetURL or TestNet3URL to
-// connect to their respective RTWire endpoints. User and pass represent
-// credentials that can be found at https://console.rtwire.com/.
-func New(c *http.Client, url, user, pass string) Client {
-	return &client{
-		client: c,
-		url:    url,
-		user:   user,
-		pass:   pass,
+// connect to their respective RTWire endpoints, or the URL of another network
+// made with Network.URL. Addresses given to Debit must be for the network the
+// URL ends with, such as mainnet or testnet3, or the one set by WithNetwork.
+// User and pass represent credentials that can be found at
+// https://console.rtwire.com/. If c is nil a new http.Client is used. Options,
+// such as WithTimeout and WithUserAgent, are applied in order and never modify
+// c itself.
+func New(c *http.Client, url, user, pass string,
+	options ...ClientOption) Client {
+
+	if c == nil {
+		c = &http.Client{}
+	}
+	cl := &client{
+		client:  c,
+		url:     url,
+		network: networkOf(url),
+		authorization: "Basic " + base64.StdEncoding.EncodeToString(
+			[]byte(user+":"+pass)),
+	}
+	for _, op := range options {
+		op(cl)
 	}
+	cl.roundTrip = RoundTripperFunc(cl.client.Do)
+	for i := len(cl.middleware) - 1; i >= 0; i-- {
+		cl.roundTrip = cl.middleware[i](cl.roundTrip)
+	}
+	return cl
 }
 
 // TransactionEvent represents a RTWire transaction event generated by a
@@ -653,16 +2276,12 @@ type TransactionEvent struct {
 
 // Unmarshal takes an http.Request that has been generated by an RTWire hook
 // event and returns a TransactionEvent. See https://rtwire.com/docs#hook-event
-// for more information.
+// for more information. Other kinds of event are returned as an error; use
+// UnmarshalEvents to receive them.
 func Unmarshal(r *http.Request) ([]TransactionEvent, error) {
 
-	if r.Header.Get("Content-Type") != "application/json" {
-		return nil, errors.New("incorrect content type")
-	}
-	defer r.Body.Close()
-
-	obj := &object{}
-	if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
+	obj, err := unmarshalHookObject(r)
+	if err != nil {
 		return nil, err
 	}
 
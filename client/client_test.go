@@ -2,11 +2,17 @@ package client_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rtwire/go/client"
 	"github.com/rtwire/mock/service"
@@ -96,32 +102,10 @@ func TestTransfer(t *testing.T) {
 	}
 
 	// Populate the account with funds.
-	addrURL := fmt.Sprintf("%s/addresses/%s", url, accOneAddr)
-	var addrPayload bytes.Buffer
-	if err := json.NewEncoder(&addrPayload).Encode(struct {
-		Value int64 `json:"value"`
-	}{
-		Value: 10,
-	}); err != nil {
-		t.Fatal(err)
-	}
-
-	req, err := http.NewRequest("POST", addrURL, &addrPayload)
-	if err != nil {
-		t.Fatal(err)
-	}
-	req.SetBasicAuth("user", "pass")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
+	if err := cl.FundAddress(accOneAddr, 10); err != nil {
 		t.Fatal(err)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		t.Fatal("expected status ok", resp.StatusCode)
-	}
-
 	// Create a recipient account.
 	accTwo, err := cl.CreateAccount()
 	if err != nil {
@@ -135,9 +119,13 @@ func TestTransfer(t *testing.T) {
 	}
 
 	// Transfer funds from sender to recipient account.
-	if err := cl.Transfer(txIDs[0], accOne.ID, accTwo.ID, 5); err != nil {
+	transfer, err := cl.Transfer(txIDs[0], accOne.ID, accTwo.ID, 5)
+	if err != nil {
 		t.Fatal(err)
 	}
+	if transfer.ID != txIDs[0] || transfer.ToAccountBalance != 5 {
+		t.Fatalf("unexpected transfer %+v", transfer)
+	}
 
 	// Check transfer occured.
 	accOne, err = cl.Account(accOne.ID)
@@ -219,7 +207,7 @@ func TestHooks(t *testing.T) {
 
 	if err := cl.CreateHook(hookURL); err == nil {
 		t.Fatal("expected duplicate error")
-	} else if err != client.ErrHookExists {
+	} else if !errors.Is(err, client.ErrHookExists) {
 		t.Fatal(err)
 	}
 
@@ -243,31 +231,9 @@ func TestHooks(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	addrURL := fmt.Sprintf("%s/addresses/%s", url, addr)
-	var addrPayload bytes.Buffer
-	if err := json.NewEncoder(&addrPayload).Encode(struct {
-		Value int64 `json:"value"`
-	}{
-		Value: 10,
-	}); err != nil {
-		t.Fatal(err)
-	}
-
-	req, err := http.NewRequest("POST", addrURL, &addrPayload)
-	if err != nil {
+	if err := cl.FundAddress(addr, 10); err != nil {
 		t.Fatal(err)
 	}
-	req.SetBasicAuth("user", "pass")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		t.Fatal("expected status ok", resp.StatusCode)
-	}
 
 	// Fetch the hook event result and make sure it is populated.
 	msg := <-eventsChan
@@ -322,31 +288,9 @@ func TestDebit(t *testing.T) {
 	}
 
 	// Populate the account with funds.
-	addrURL := fmt.Sprintf("%s/addresses/%s", url, accAddr)
-	var addrPayload bytes.Buffer
-	if err := json.NewEncoder(&addrPayload).Encode(struct {
-		Value int64 `json:"value"`
-	}{
-		Value: 10,
-	}); err != nil {
-		t.Fatal(err)
-	}
-
-	req, err := http.NewRequest("POST", addrURL, &addrPayload)
-	if err != nil {
+	if err := client.FundAddress(accAddr, 10); err != nil {
 		t.Fatal(err)
 	}
-	req.SetBasicAuth("user", "pass")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		t.Fatal("expected status ok", resp.StatusCode)
-	}
 
 	// Create a txID.
 	txIDs, err := client.CreateTransactionIDs(1)
@@ -356,7 +300,7 @@ func TestDebit(t *testing.T) {
 
 	// Debit funds.
 	const debitAddr = "12aXxEWgTYZgAiGC81Tqu1cSiDUSy3embt"
-	if err := client.Debit(txIDs[0], acc.ID, debitAddr, 5); err != nil {
+	if _, err := client.Debit(txIDs[0], acc.ID, debitAddr, 5); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -413,3 +357,684 @@ func TestBadRequestError(t *testing.T) {
 		t.Fatalf("unexpected error %v", err.Error())
 	}
 }
+
+// newRoutesServer returns a server that replies to requests matching the
+// "METHOD /path" patterns in routes, relative to /v1/mainnet, with the
+// pattern's body and 404s otherwise. Bodies of error objects are sent with a
+// 400 status code.
+func newRoutesServer(routes map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			path := strings.TrimPrefix(r.URL.Path, "/v1/mainnet")
+			body, ok := routes[r.Method+" "+path]
+			if !ok || path == r.URL.Path {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if strings.Contains(body, `"errors"`) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+			fmt.Fprint(w, body)
+		}))
+}
+
+func TestWaitForTransaction(t *testing.T) {
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests < 3 {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "transactions", "payload": [{"id": 1}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	if _, err := cl.Transaction(1); !errors.Is(err, client.ErrNotFound) {
+		t.Fatal("expected not found", err)
+	}
+
+	tx, err := cl.WaitForTransaction(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.ID != 1 {
+		t.Fatal("incorrect tx id")
+	}
+
+	// Give up once the context is done.
+	requests = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := cl.WaitForTransaction(ctx, 1); err != context.DeadlineExceeded {
+		t.Fatal("expected deadline exceeded", err)
+	}
+}
+
+func TestAccountHooks(t *testing.T) {
+
+	const hookURL = "https://example.com/hook"
+	server := newRoutesServer(map[string]string{
+		"POST /accounts/1/hooks/": ``,
+		"POST /accounts/2/hooks/": `{"type": "errors",
+			"payload": [{"message": "hook exists"}]}`,
+		"GET /accounts/1/hooks/": `{"type": "hooks",
+			"payload": [{"url": "https://example.com/hook"}]}`,
+		"DELETE /accounts/1/hooks/aHR0cHM6Ly9leGFtcGxlLmNvbS9ob29r": ``,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	if err := cl.CreateAccountHook(1, hookURL); err != nil {
+		t.Fatal(err)
+	}
+
+	err := cl.CreateAccountHook(2, hookURL)
+	if !errors.Is(err, client.ErrHookExists) {
+		t.Fatal("expected hook exists", err)
+	}
+
+	hooks, err := cl.AccountHooks(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hooks) != 1 || hooks[0].URL != hookURL {
+		t.Fatalf("incorrect hooks %+v", hooks)
+	}
+
+	if err := cl.DeleteAccountHook(1, hookURL); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReconcileHook(t *testing.T) {
+
+	const hookURL = "https://example.com/hook"
+	exists := `{"type": "errors", "payload": [{"message": "hook exists"}]}`
+	tests := []struct {
+		name   string
+		listed string
+		err    error
+	}{
+		{"listed", `[{"url": "https://example.com/hook"}]`, nil},
+		{"not listed", `[]`, client.ErrHookExists},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newRoutesServer(map[string]string{
+				"POST /hooks/": exists,
+				"GET /hooks/": `{"type": "hooks", "payload": ` +
+					test.listed + `}`,
+			})
+			defer server.Close()
+
+			url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+			cl := client.New(http.DefaultClient, url, "user", "pass")
+
+			if err := cl.CreateHook(hookURL); !errors.Is(err,
+				client.ErrHookExists) {
+				t.Fatal("expected hook exists", err)
+			}
+			// A matching hook is left as it is, as the server has no PUT
+			// route.
+			err := cl.CreateHook(hookURL, client.ReconcileHook())
+			if !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+				t.Fatalf("expected %v got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestUpdateHook(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"PUT /hooks/aHR0cHM6Ly9leGFtcGxlLmNvbS9ob29r": ``,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	hook := client.Hook{URL: "https://example.com/hook"}
+	if err := cl.UpdateHook(hook); err != nil {
+		t.Fatal(err)
+	}
+	hook.URL = "https://example.com/other"
+	if err := cl.UpdateHook(hook); !errors.Is(err, client.ErrNotFound) {
+		t.Fatal("expected not found", err)
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}))
+	defer server.Close()
+	defer close(release)
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := cl.AccountContext(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected deadline exceeded", err)
+	}
+}
+
+func TestOrder(t *testing.T) {
+
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "transactions", "payload": []}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	_, _, err := cl.AccountTransactions(1, client.SortBy(client.SortByValue),
+		client.Order(client.OrderDescending))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "order=desc&sortBy=value" {
+		t.Fatal("unexpected query", query)
+	}
+
+	if _, _, err := cl.Accounts(client.Ascending()); err != nil {
+		t.Fatal(err)
+	}
+	if query != "order=asc" {
+		t.Fatal("unexpected query", query)
+	}
+	if _, _, err := cl.Accounts(client.Descending()); err != nil {
+		t.Fatal(err)
+	}
+	if query != "order=desc" {
+		t.Fatal("unexpected query", query)
+	}
+
+	if _, _, err := cl.Accounts(client.Order("up")); err == nil {
+		t.Fatal("expected error for unknown order")
+	}
+	if _, _, err := cl.Accounts(client.SortBy("name")); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
+
+func TestTypeFilter(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if v := r.URL.Query().Get("type"); v != "debit,transfer" {
+				t.Errorf("unexpected type query %q", v)
+			}
+			// The server doesn't filter, so the client must.
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "transactions", "payload": [
+				{"id": 1, "type": "credit"}, {"id": 2, "type": "debit"},
+				{"id": 3, "type": "transfer"}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	_, txns, err := cl.AccountTransactions(1,
+		client.TypeFilter(client.Debit|client.Transfer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 2 || txns[0].ID != 2 || txns[1].ID != 3 {
+		t.Fatalf("expected debit and transfer %+v", txns)
+	}
+
+	if _, _, err := cl.AccountTransactions(1, client.TypeFilter(0)); err == nil {
+		t.Fatal("expected empty filter to be refused")
+	}
+}
+
+func TestDebitFee(t *testing.T) {
+
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body = nil
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+	const addr = "12aXxEWgTYZgAiGC81Tqu1cSiDUSy3embt"
+
+	if _, err := cl.Debit(1, 2, addr, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := body["feePerByte"]; ok {
+		t.Fatal("expected no fee by default", body)
+	}
+
+	if _, err := cl.Debit(1, 2, addr, 10, client.FeePerByte(12)); err != nil {
+		t.Fatal(err)
+	}
+	if body["feePerByte"] != 12.0 {
+		t.Fatal("expected fee per byte", body)
+	}
+
+	if _, err := cl.Debit(1, 2, addr, 10,
+		client.ConfirmationTarget(144)); err != nil {
+		t.Fatal(err)
+	}
+	if body["confirmationTarget"] != 144.0 {
+		t.Fatal("expected confirmation target", body)
+	}
+
+	var vErr *client.ValidationError
+	_, err := cl.Debit(1, 2, addr, 10, client.FeePerByte(12),
+		client.ConfirmationTarget(6))
+	if !errors.As(err, &vErr) {
+		t.Fatal("expected exclusive fee options to be refused", err)
+	}
+	if _, err := cl.Debit(1, 2, addr, 10,
+		client.ConfirmationTarget(0)); !errors.As(err, &vErr) {
+		t.Fatal("expected invalid target to be refused", err)
+	}
+}
+
+func TestDebitTransaction(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "transactions", "payload": [{"id": 1,
+				"type": "debit", "fromAccountID": 2, "value": 10,
+				"txHashes": ["ab"], "txOutIndex": 3}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	tx, err := cl.Debit(1, 2, "12aXxEWgTYZgAiGC81Tqu1cSiDUSy3embt", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxHashes) != 1 || tx.TxHashes[0] != "ab" || tx.TxOutIndex != 3 {
+		t.Fatalf("expected transaction from response %+v", tx)
+	}
+}
+
+func TestTransferTransaction(t *testing.T) {
+
+	payload := `[{"id": 1, "type": "transfer", "fromAccountID": 2,
+		"toAccountID": 3, "value": 10, "fromAccountBalance": 90,
+		"toAccountBalance": 10}]`
+	var body map[string]int64
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"type": "transactions", "payload": %s}`,
+				payload)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	tx, err := cl.Transfer(1, 2, 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := map[string]int64{"id": 1, "fromAccountID": 2, "toAccountID": 3,
+		"value": 10}
+	if !reflect.DeepEqual(body, sent) {
+		t.Fatal("unexpected request body", body)
+	}
+	if tx.FromAccountBalance != 90 || tx.ToAccountBalance != 10 {
+		t.Fatalf("expected balances from response %+v", tx)
+	}
+
+	// Without the transaction in the response only the request is known.
+	payload = `[]`
+	tx, err = cl.Transfer(1, 2, 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := client.Transaction{ID: 1, Type: "transfer",
+		FromAccountID: 2, ToAccountID: 3, Value: 10}
+	if !reflect.DeepEqual(tx, expected) {
+		t.Fatalf("expected %+v got %+v", expected, tx)
+	}
+}
+
+func TestDebitMany(t *testing.T) {
+
+	var body struct {
+		ID            int64           `json:"id"`
+		FromAccountID int64           `json:"fromAccountID"`
+		Outputs       []client.Output `json:"outputs"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	outputs := []client.Output{
+		{Address: "12aXxEWgTYZgAiGC81Tqu1cSiDUSy3embt", Value: 10},
+		{Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Value: 20},
+	}
+	tx, err := cl.DebitMany(1, 2, outputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body.ID != 1 || body.FromAccountID != 2 ||
+		!reflect.DeepEqual(body.Outputs, outputs) {
+		t.Fatalf("unexpected request %+v", body)
+	}
+	if tx.Type != "debit" || tx.Value != 30 {
+		t.Fatalf("expected the total to be debited %+v", tx)
+	}
+}
+
+func TestAccountMetadata(t *testing.T) {
+
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			bodies = append(bodies, r.Method+" "+strings.TrimSpace(
+				string(body)))
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "accounts", "payload": [{"id": 1,
+				"label": "customer-7", "metadata": {"tier": "gold"}}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	if _, err := cl.CreateAccount(); err != nil {
+		t.Fatal(err)
+	}
+	acc, err := cl.CreateAccount(client.Label("customer-7"),
+		client.Metadata(map[string]string{"tier": "gold"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Label != "customer-7" || acc.Metadata["tier"] != "gold" {
+		t.Fatalf("expected label and metadata %+v", acc)
+	}
+	if _, err := cl.UpdateAccount(1, client.Metadata(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"POST ",
+		`POST {"label":"customer-7","metadata":{"tier":"gold"}}`,
+		`PUT {"metadata":{}}`,
+	}
+	if !reflect.DeepEqual(bodies, expected) {
+		t.Fatalf("expected %q got %q", expected, bodies)
+	}
+}
+
+func TestAccountAddresses(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"GET /accounts/1/addresses/": `{"type": "addresses", "next": "n",
+			"payload": [{"address": "addr1",
+			"created": "2020-01-02 03:04:05"}]}`,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithStrictDecoding())
+
+	next, addrs, err := cl.AccountAddresses(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if next != "n" || len(addrs) != 1 || addrs[0].Address != "addr1" ||
+		!addrs[0].Created.Equal(created) {
+		t.Fatalf("unexpected addresses %q %+v", next, addrs)
+	}
+	if _, _, err := cl.AccountAddresses(0); err == nil {
+		t.Fatal("expected invalid account ID")
+	}
+}
+
+func TestAccountByAddress(t *testing.T) {
+
+	const addr = "12aXxEWgTYZgAiGC81Tqu1cSiDUSy3embt"
+	server := newRoutesServer(map[string]string{
+		"GET /addresses/" + addr: `{"type": "addresses",
+			"payload": [{"accountID": 4, "address": "` + addr + `"}]}`,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	id, err := cl.AccountByAddress(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 4 {
+		t.Fatal("unexpected account", id)
+	}
+	_, err = cl.AccountByAddress("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa")
+	if !errors.Is(err, client.ErrNotFound) {
+		t.Fatal("expected unknown address to be not found", err)
+	}
+}
+
+func TestTransactionsByHash(t *testing.T) {
+
+	const hash = "4a5e1e4baab89f3a32518a88c31bc87f" +
+		"618f76673e2cc77ab2127b7afdeda33b"
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path != "/v1/mainnet/transactions/" ||
+				r.URL.Query().Get("txHash") != hash {
+				fmt.Fprint(w, `{"type": "transactions", "payload": []}`)
+				return
+			}
+			fmt.Fprintf(w, `{"type": "transactions", "payload": [
+				{"id": 1, "type": "debit", "txHashes": ["%s"]},
+				{"id": 2, "type": "debit", "txHashes": ["%s"]}]}`,
+				hash, hash)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	txns, err := cl.TransactionsByHash(strings.ToUpper(hash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 2 || txns[0].ID != 1 || txns[1].ID != 2 {
+		t.Fatalf("unexpected transactions %+v", txns)
+	}
+}
+
+func TestFundAddress(t *testing.T) {
+
+	const addr = "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != "POST" ||
+				r.URL.Path != "/v1/testnet3/addresses/"+addr {
+				http.NotFound(w, r)
+				return
+			}
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/testnet3", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+	if err := cl.FundAddress(addr, 10); err != nil {
+		t.Fatal(err)
+	}
+	if body != `{"value":10}` {
+		t.Fatal("unexpected body", body)
+	}
+
+	// Funding is refused on mainnet before any request is made.
+	mainNet := client.New(&http.Client{Transport: client.RoundTripperFunc(
+		func(*http.Request) (*http.Response, error) {
+			t.Fatal("unexpected request")
+			return nil, nil
+		})}, client.MainNetURL, "user", "pass")
+	err := mainNet.FundAddress("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", 10)
+	if err != client.ErrMainNet {
+		t.Fatal("expected ErrMainNet", err)
+	}
+}
+
+func TestChainInfo(t *testing.T) {
+
+	const hash = "00000000000000000002a7c4c1e48d76" +
+		"c5a37902165a270156b7a8d72728a054"
+	server := newRoutesServer(map[string]string{
+		"GET /chain/": fmt.Sprintf(`{"type": "chain", "payload": [{
+			"height": 800000, "bestBlockHash": "%s",
+			"headerHeight": 800002, "synced": false}]}`, hash),
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	info, err := cl.ChainInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := client.ChainInfo{
+		Height:        800000,
+		BestBlockHash: hash,
+		HeaderHeight:  800002,
+	}
+	if info != want {
+		t.Fatalf("expected %+v got %+v", want, info)
+	}
+}
+
+func TestCloseAccount(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"DELETE /accounts/1": ``,
+		"GET /accounts/1": `{"type": "accounts",
+			"payload": [{"id": 1, "closed": true}]}`,
+		"POST /accounts/1/addresses/": `{"type": "errors",
+			"payload": [{"code": "account closed",
+			"message": "account is closed"}]}`,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	if err := cl.CloseAccount(1); err != nil {
+		t.Fatal(err)
+	}
+	acc, err := cl.Account(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !acc.Closed {
+		t.Fatal("expected account to be closed", acc)
+	}
+	if _, err := cl.CreateAddress(1); !errors.Is(err,
+		client.ErrAccountClosed) {
+		t.Fatal("expected account closed", err)
+	}
+}
+
+// benchClient returns a client whose requests are answered with body without
+// a network round trip, so that only the client's own work is measured.
+func benchClient(body string) client.Client {
+	hc := &http.Client{Transport: client.RoundTripperFunc(
+		func(req *http.Request) (*http.Response, error) {
+			if req.Body != nil {
+				io.Copy(io.Discard, req.Body)
+			}
+			return &http.Response{
+				StatusCode:    http.StatusOK,
+				ContentLength: int64(len(body)),
+				Header: http.Header{
+					"Content-Type": {"application/json"},
+				},
+				Body: io.NopCloser(bytes.NewReader([]byte(body))),
+			}, nil
+		})}
+	return client.New(hc, client.MainNetURL, "user", "pass")
+}
+
+func BenchmarkTransfer(b *testing.B) {
+	cl := benchClient(`{"type": "transactions", "payload": [{"id": 1,
+		"type": "transfer", "fromAccountID": 1, "toAccountID": 2,
+		"value": 10}]}`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := cl.Transfer(int64(i+1), 1, 2, 10); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDebit(b *testing.B) {
+	cl := benchClient(`{"type": "transactions", "payload": [{"id": 1,
+		"type": "debit", "fromAccountID": 1, "value": 10}]}`)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := cl.Debit(int64(i+1), 1,
+			"1BoatSLRHtKNngkdXEeobR76b53LETtpyT", 10); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package clientmock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/clientmock"
+)
+
+func TestClient(t *testing.T) {
+
+	m := &clientmock.Client{
+		TransferFunc: func(ctx context.Context, txID, fromAccountID,
+			toAccountID int64, value client.Amount,
+			options ...client.Option) (client.Transaction, error) {
+			if value > 100 {
+				return client.Transaction{}, client.ErrInsufficientFunds
+			}
+			return client.Transaction{ID: txID, Value: value}, nil
+		},
+	}
+
+	var cl client.Client = m
+	if _, err := cl.Transfer(1, 2, 3, 50); err != nil {
+		t.Fatal(err)
+	}
+	_, err := cl.TransferContext(context.Background(), 2, 2, 3, 500,
+		client.NoRetry())
+	if !errors.Is(err, client.ErrInsufficientFunds) {
+		t.Fatal("expected insufficient funds", err)
+	}
+	if _, err := cl.Account(1); !errors.Is(err, clientmock.ErrNotConfigured) {
+		t.Fatal("expected not configured", err)
+	}
+	_, res := cl.CreateAccounts(2)
+	if len(res.Items) != 2 || !errors.Is(res.Items[1].Err,
+		clientmock.ErrNotConfigured) {
+		t.Fatalf("expected failed items %+v", res)
+	}
+
+	transfers := m.CallsTo("Transfer")
+	if len(transfers) != 2 || transfers[1].Args[3] != client.Amount(500) {
+		t.Fatalf("unexpected transfers %+v", transfers)
+	}
+	if calls := m.Calls(); len(calls) != 4 || calls[2].Method != "Account" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+}
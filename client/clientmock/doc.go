@@ -0,0 +1,13 @@
+// Package clientmock provides Client, a configurable implementation of
+// client.Client for tests. Each method calls the function set in the field of
+// the same name with a Func suffix, after recording the call. Methods without
+// a function return ErrNotConfigured, so a test only configures the calls it
+// expects.
+//
+// Both the plain and Context variant of a method call the same function, with
+// a background context for the plain variant. Client is generated from the
+// client.Client interface by gen.go; run go generate after changing the
+// interface. The build fails if they drift apart.
+package clientmock
+
+//go:generate go run gen.go
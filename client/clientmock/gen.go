@@ -0,0 +1,369 @@
+//go:build ignore
+
+// Command gen writes clientmock.go from the client.Client interface declared
+// in ../client.go. Run it with go generate after changing the interface.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+)
+
+// recordOptions lists the methods whose options are recorded as their last
+// argument, as by client.WithCallMiddleware.
+var recordOptions = map[string]bool{
+	"AccountAddresses":    true,
+	"Accounts":            true,
+	"AccountTransactions": true,
+}
+
+// method is a Client method taking a context, and its plain variant, if any.
+type method struct {
+	name    string // name without the Context suffix
+	ctxName string // name of the variant taking a context
+	plain   bool   // whether there is a variant without a context
+	params  []param
+	results []string
+}
+
+type param struct {
+	names []string
+	typ   string
+}
+
+func main() {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "../client.go", nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	iface := findInterface(f, "Client")
+	if iface == nil {
+		log.Fatal("Client interface not found")
+	}
+
+	funcs := map[string]*ast.FuncType{}
+	var order []string
+	for _, field := range iface.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			log.Fatalf("unexpected embedded interface in Client")
+		}
+		for _, name := range field.Names {
+			funcs[name.Name] = ft
+			order = append(order, name.Name)
+		}
+	}
+
+	var methods []method
+	for _, name := range order {
+		if strings.HasSuffix(name, "Context") &&
+			funcs[strings.TrimSuffix(name, "Context")] != nil {
+			continue
+		}
+		m := method{name: name, ctxName: name}
+		ft := funcs[name]
+		if ctxFT := funcs[name+"Context"]; ctxFT != nil {
+			m.ctxName, m.plain, ft = name+"Context", true, ctxFT
+		}
+		params := ft.Params.List
+		if len(params) == 0 || typeString(params[0].Type) !=
+			"context.Context" || len(params[0].Names) != 1 {
+			log.Fatalf("%s does not take a context", m.ctxName)
+		}
+		for _, p := range params[1:] {
+			var names []string
+			for _, n := range p.Names {
+				names = append(names, n.Name)
+			}
+			m.params = append(m.params, param{names, typeString(p.Type)})
+		}
+		if ft.Results != nil {
+			for _, r := range ft.Results.List {
+				m.results = append(m.results, typeString(r.Type))
+			}
+		}
+		methods = append(methods, m)
+	}
+
+	src, err := format.Source(generate(methods))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("clientmock.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func findInterface(f *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if it, ok := ts.Type.(*ast.InterfaceType); ok &&
+				ts.Name.Name == name {
+				return it
+			}
+		}
+	}
+	return nil
+}
+
+// typeString formats expr, qualifying the types of package client.
+func typeString(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch {
+		case e.Name == "option":
+			return "client.Option"
+		case ast.IsExported(e.Name):
+			return "client." + e.Name
+		}
+		return e.Name
+	case *ast.SelectorExpr:
+		return typeString(e.X) + "." + e.Sel.Name
+	case *ast.ArrayType:
+		return "[]" + typeString(e.Elt)
+	case *ast.StarExpr:
+		return "*" + typeString(e.X)
+	case *ast.Ellipsis:
+		return "..." + typeString(e.Elt)
+	case *ast.MapType:
+		return "map[" + typeString(e.Key) + "]" + typeString(e.Value)
+	}
+	log.Fatalf("unsupported type %T", expr)
+	return ""
+}
+
+// argNames returns the names of the parameters of m.
+func (m method) argNames() []string {
+	var names []string
+	for _, p := range m.params {
+		names = append(names, p.names...)
+	}
+	return names
+}
+
+// hasOptions reports whether the last parameter of m is options.
+func (m method) hasOptions() bool {
+	return len(m.params) > 0 &&
+		strings.HasPrefix(m.params[len(m.params)-1].typ, "...")
+}
+
+// batch reports whether m returns a client.BatchResult rather than an error.
+func (m method) batch() bool {
+	return len(m.results) > 0 &&
+		m.results[len(m.results)-1] == "client.BatchResult"
+}
+
+// paramList returns the parameters of m as items to wrap, giving the type
+// after the last name of each group of parameters sharing it.
+func (m method) paramList() []string {
+	var list []string
+	for _, p := range m.params {
+		for i, name := range p.names {
+			if i == len(p.names)-1 {
+				name += " " + p.typ
+			}
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+func (m method) resultList() string {
+	if len(m.results) == 1 {
+		return m.results[0]
+	}
+	return "(" + strings.Join(m.results, ", ") + ")"
+}
+
+// callArgs returns the arguments passing on the parameters of m, after
+// first.
+func (m method) callArgs(first string) []string {
+	args := append([]string{first}, m.argNames()...)
+	if m.hasOptions() {
+		args[len(args)-1] += "..."
+	}
+	return args
+}
+
+func zero(typ string) string {
+	switch {
+	case typ == "string":
+		return `""`
+	case typ == "error":
+		return "ErrNotConfigured"
+	case strings.HasPrefix(typ, "[]"), strings.HasPrefix(typ, "*"),
+		strings.HasPrefix(typ, "map["):
+		return "nil"
+	case strings.HasPrefix(typ, "client."):
+		return typ + "{}"
+	}
+	return "0"
+}
+
+// width returns the length of line with tabs counted as four columns.
+func width(line string) int {
+	return len(strings.ReplaceAll(line, "\t", "    "))
+}
+
+// wrap joins items after prefix and before suffix, breaking the line after a
+// comma, or after the opening parenthesis of the results, where it would go
+// past 80 columns.
+func wrap(prefix string, items []string, suffix string) string {
+	var lines []string
+	cur := prefix
+	for i, item := range items {
+		if i < len(items)-1 {
+			item += ","
+		}
+		if width(cur+item) > 80 && cur != prefix {
+			lines = append(lines, strings.TrimRight(cur, " "))
+			cur = "\t\t"
+		}
+		cur += item + " "
+	}
+	cur = strings.TrimRight(cur, " ")
+	switch {
+	case width(cur+suffix) <= 80:
+	case strings.HasPrefix(suffix, ") (") && width(cur+") (") <= 80:
+		lines = append(lines, cur+") (")
+		cur, suffix = "\t\t", suffix[len(") ("):]
+	default:
+		if i := strings.LastIndex(cur, ", "); i >= 0 {
+			lines = append(lines, cur[:i+1])
+			cur = "\t\t" + cur[i+2:]
+		}
+	}
+	return strings.Join(append(lines, cur+suffix), "\n")
+}
+
+func generate(methods []method) []byte {
+	var b bytes.Buffer
+	b.WriteString(header)
+	for _, m := range methods {
+		params := append([]string{"ctx context.Context"}, m.paramList()...)
+		fmt.Fprintln(&b, wrap("\t"+m.name+"Func func(", params,
+			") "+m.resultList()))
+	}
+	b.WriteString(helpers)
+
+	for _, m := range methods {
+		if m.plain {
+			fmt.Fprintf(&b, "\n%s\n", wrap("func (m *Client) "+m.name+"(",
+				m.paramList(), ") "+m.resultList()+" {"))
+			fmt.Fprintf(&b, "%s\n}\n", wrap("\treturn m."+m.ctxName+"(",
+				m.callArgs("context.Background()"), ")"))
+		}
+
+		params := append([]string{"ctx context.Context"}, m.paramList()...)
+		fmt.Fprintf(&b, "\n%s\n", wrap("func (m *Client) "+m.ctxName+"(",
+			params, ") "+m.resultList()+" {"))
+
+		record := append([]string{`"` + m.name + `"`}, m.argNames()...)
+		if m.hasOptions() && !recordOptions[m.name] {
+			record = record[:len(record)-1]
+		}
+		fmt.Fprintf(&b, "\tm.record(%s)\n", strings.Join(record, ", "))
+
+		fmt.Fprintf(&b, "\tif m.%sFunc == nil {\n", m.name)
+		var zeros []string
+		if m.batch() {
+			// Batch methods return a result for each item requested.
+			n := m.argNames()[0]
+			if strings.HasPrefix(m.params[0].typ, "[]") {
+				n = "len(" + n + ")"
+			}
+			zeros = []string{
+				fmt.Sprintf("make(%s, %s)", m.results[0], n),
+				fmt.Sprintf("notConfigured(%s)", n),
+			}
+		} else {
+			for _, r := range m.results {
+				zeros = append(zeros, zero(r))
+			}
+		}
+		fmt.Fprintf(&b, "\t\treturn %s\n\t}\n", strings.Join(zeros, ", "))
+		fmt.Fprintf(&b, "%s\n}\n", wrap("\treturn m."+m.name+"Func(",
+			m.callArgs("ctx"), ")"))
+	}
+	return b.Bytes()
+}
+
+const header = `// Code generated by gen.go from the client.Client interface; DO NOT EDIT.
+
+package clientmock
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rtwire/go/client"
+)
+
+// ErrNotConfigured is returned from methods whose function is not set.
+var ErrNotConfigured = errors.New("clientmock: method not configured")
+
+var _ client.Client = (*Client)(nil)
+
+// Client is a client.Client whose behaviour is set by its function fields. It
+// is safe for concurrent use once configured.
+type Client struct {
+`
+
+const helpers = `
+	mu    sync.Mutex
+	calls []client.Call
+}
+
+// Calls returns the calls made so far, in order. Args are recorded as by
+// client.WithCallMiddleware.
+func (m *Client) Calls() []client.Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]client.Call(nil), m.calls...)
+}
+
+// CallsTo returns the calls made so far to method, for example "Transfer".
+func (m *Client) CallsTo(method string) []client.Call {
+	var calls []client.Call
+	for _, call := range m.Calls() {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (m *Client) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	m.calls = append(m.calls, client.Call{Method: method, Args: args})
+	m.mu.Unlock()
+}
+
+// notConfigured returns a result for n items, each failed with
+// ErrNotConfigured.
+func notConfigured(n int) client.BatchResult {
+	res := client.BatchResult{Items: make([]client.BatchItem, n)}
+	for i := range res.Items {
+		res.Items[i] = client.BatchItem{
+			Index:  i,
+			Status: client.BatchFailed,
+			Err:    ErrNotConfigured,
+		}
+	}
+	return res
+}
+`
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// WithRequestCoalescing makes concurrent identical GET requests, such as many
+// goroutines reading the same account or the fees at once, share a single
+// call to RTWire. Each caller decodes its own copy of the shared response.
+// Requests are only coalesced while one is in flight; responses are not
+// cached.
+func WithRequestCoalescing() ClientOption {
+	return func(c *client) {
+		c.coalescer = &coalescer{calls: map[string]*coalescedCall{}}
+	}
+}
+
+// coalescer deduplicates in flight requests in the style of singleflight.
+type coalescer struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+type coalescedCall struct {
+	done chan struct{}
+	resp *http.Response
+	body []byte
+	err  error
+}
+
+// do calls send for req unless an identical request is already in flight, in
+// which case it waits for and returns that request's result. If the request in
+// flight fails because its own context was done, waiters whose contexts are
+// still live make the request again rather than returning the other caller's
+// cancellation.
+func (g *coalescer) do(req *http.Request, send func(*http.Request) (
+	*http.Response, []byte, error)) (*http.Response, []byte, error) {
+
+	key := req.URL.String()
+	for {
+		g.mu.Lock()
+		if call, ok := g.calls[key]; ok {
+			g.mu.Unlock()
+
+			select {
+			case <-call.done:
+			case <-req.Context().Done():
+				return nil, nil, req.Context().Err()
+			}
+			if isContextErr(call.err) && req.Context().Err() == nil {
+				continue
+			}
+			return call.resp, call.body, call.err
+		}
+
+		call := &coalescedCall{done: make(chan struct{})}
+		g.calls[key] = call
+		g.mu.Unlock()
+
+		call.resp, call.body, call.err = send(req)
+
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+
+		return call.resp, call.body, call.err
+	}
+}
+
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,75 @@
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestWithRequestCoalescing(t *testing.T) {
+
+	var requests int32
+	arrived := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			select {
+			case arrived <- struct{}{}:
+			default:
+			}
+			<-release
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "accounts",
+				"payload": [{"id": 1, "balance": 100}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithRequestCoalescing())
+
+	const callers = 10
+	accounts := make([]client.Account, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			acc, err := cl.Account(1)
+			if err != nil {
+				t.Error(err)
+			}
+			accounts[i] = acc
+		}(i)
+	}
+
+	// Give the other callers time to join the request in flight.
+	<-arrived
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Fatal("expected one upstream request", requests)
+	}
+	for _, acc := range accounts {
+		if acc.ID != 1 || acc.Balance != 100 {
+			t.Fatal("unexpected account", acc)
+		}
+	}
+
+	// Requests made after the first completes are sent again.
+	if _, err := cl.Account(1); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Fatal("expected a second upstream request", requests)
+	}
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+)
+
+// Codec decodes RTWire responses in a particular wire format. The client
+// advertises the codec's content type in the Accept header of each request and
+// uses the codec for responses served with that content type. JSON responses
+// are always understood, so a codec can be configured before the server
+// supports its format. Request bodies are always sent as JSON.
+//
+// Every RTWire response is an object with a type, an optional next cursor and
+// a payload. UnmarshalObject splits a response body into those parts, leaving
+// the payload undecoded, and Unmarshal later decodes the payload into the
+// value the caller expects. Struct fields are tagged for encoding/json; other
+// codecs should match field names case insensitively.
+type Codec interface {
+	ContentType() string
+	UnmarshalObject(data []byte) (typ, next string, payload []byte, err error)
+	Unmarshal(payload []byte, v interface{}) error
+}
+
+// WithCodec configures the client to request responses encoded with codec, for
+// example application/msgpack, falling back to JSON when the server responds
+// with JSON.
+func WithCodec(codec Codec) ClientOption {
+	return func(c *client) {
+		c.codec = codec
+	}
+}
+
+// jsonCodec is the default codec. Unknown fields in payloads are reported to
+// onUnknown, if set, and are an error if strict is set.
+type jsonCodec struct {
+	strict    bool
+	onUnknown func(UnknownField)
+}
+
+func (*jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (*jsonCodec) UnmarshalObject(data []byte) (string, string, []byte, error) {
+	obj := &object{}
+	if err := json.Unmarshal(data, obj); err != nil {
+		return "", "", nil, err
+	}
+	return obj.Type, obj.Next, obj.Payload, nil
+}
+
+func (j *jsonCodec) Unmarshal(payload []byte, v interface{}) error {
+	if j.strict || j.onUnknown != nil {
+		if err := j.checkFields(payload, v); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(payload, v)
+}
+
+func (c *client) accept() string {
+	const json = "application/json"
+	if c.codec != nil {
+		return c.codec.ContentType() + ", " + json + ";q=0.9"
+	}
+	return json
+}
+
+// responseCodec returns the codec matching the content type of resp. JSON is
+// assumed if the content type is missing or unrecognised.
+func (c *client) responseCodec(resp *http.Response) Codec {
+	if c.codec == nil {
+		return &c.json
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err == nil && mediaType == c.codec.ContentType() {
+		return c.codec
+	}
+	return &c.json
+}
@@ -0,0 +1,79 @@
+package client_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+// testCodec is JSON served under a different content type.
+type testCodec struct {
+	objects *int
+}
+
+func (testCodec) ContentType() string {
+	return "application/x-test"
+}
+
+func (c testCodec) UnmarshalObject(data []byte) (string, string, []byte, error) {
+	*c.objects++
+	var obj struct {
+		Type    string
+		Next    string
+		Payload json.RawMessage
+	}
+	err := json.Unmarshal(data, &obj)
+	return obj.Type, obj.Next, obj.Payload, err
+}
+
+func (testCodec) Unmarshal(payload []byte, v interface{}) error {
+	return json.Unmarshal(payload, v)
+}
+
+func TestCodec(t *testing.T) {
+
+	supported := true
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			accept := r.Header.Get("Accept")
+			if !strings.HasPrefix(accept, "application/x-test") {
+				t.Errorf("unexpected accept header %q", accept)
+			}
+			if supported {
+				w.Header().Set("Content-Type", "application/x-test")
+			} else {
+				w.Header().Set("Content-Type", "application/json")
+			}
+			fmt.Fprint(w, `{"type": "accounts",
+				"payload": [{"id": 1, "balance": 2}]}`)
+		}))
+	defer server.Close()
+
+	objects := 0
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithCodec(testCodec{objects: &objects}))
+
+	acc, err := cl.Account(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Balance != 2 || objects != 1 {
+		t.Fatal("expected codec to decode response", acc, objects)
+	}
+
+	// JSON responses are decoded without the codec.
+	supported = false
+	acc, err = cl.Account(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Balance != 2 || objects != 1 {
+		t.Fatal("expected JSON fallback", acc, objects)
+	}
+}
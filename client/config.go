@@ -0,0 +1,120 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+)
+
+// Config describes how to connect to RTWire. It is usually loaded from a file
+// with LoadConfig so that binaries can embed their configuration with
+// go:embed.
+type Config struct {
+	URL  string `json:"url"`
+	User string `json:"user"`
+	Pass string `json:"pass"`
+
+	// Timeout bounds each HTTP request, for example "10s". No timeout is
+	// applied if it is empty.
+	Timeout string `json:"timeout"`
+
+	// CertFile and KeyFile name a PEM encoded client certificate and key.
+	// CAFile names PEM encoded certificates used to verify the server instead
+	// of the system roots. Files are read from the same fs.FS as the config.
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	CAFile   string `json:"caFile"`
+
+	fsys fs.FS
+}
+
+// LoadConfig reads a JSON encoded Config from the file name in fsys.
+func LoadConfig(fsys fs.FS, name string) (*Config, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("%s: url not set", name)
+	}
+	cfg.fsys = fsys
+	return cfg, nil
+}
+
+// New creates a client from the config. Options are applied after the
+// config.
+func (cfg *Config) New(options ...ClientOption) (Client, error) {
+	hc := &http.Client{}
+
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("timeout: %w", err)
+		}
+		hc.Timeout = timeout
+	}
+
+	if cfg.CertFile != "" || cfg.CAFile != "" {
+		if cfg.fsys == nil {
+			return nil, errors.New("TLS files set on config not loaded " +
+				"with LoadConfig")
+		}
+		tlsConfig, err := LoadTLSConfig(cfg.fsys, cfg.CertFile, cfg.KeyFile,
+			cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		hc.Transport = transport
+	}
+
+	return New(hc, cfg.URL, cfg.User, cfg.Pass, options...), nil
+}
+
+// LoadTLSConfig creates a tls.Config from PEM encoded files in fsys. certFile
+// and keyFile, if set, are loaded as the client certificate. caFile, if set,
+// replaces the system roots used to verify the server.
+func LoadTLSConfig(fsys fs.FS, certFile, keyFile, caFile string) (
+	*tls.Config, error) {
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if certFile != "" || keyFile != "" {
+		certPEM, err := fs.ReadFile(fsys, certFile)
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := fs.ReadFile(fsys, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		caPEM, err := fs.ReadFile(fsys, caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("%s: no certificates found", caFile)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
@@ -0,0 +1,57 @@
+package client_test
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestLoadConfig(t *testing.T) {
+
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "accounts", "payload": [{"id": 1}]}`)
+		}))
+	defer server.Close()
+
+	caPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	})
+
+	fsys := fstest.MapFS{
+		"config.json": {Data: []byte(fmt.Sprintf(`{
+			"url": "%s/v1/mainnet",
+			"user": "user",
+			"pass": "pass",
+			"timeout": "5s",
+			"caFile": "ca.pem"
+		}`, server.URL))},
+		"ca.pem": {Data: caPEM},
+	}
+
+	cfg, err := client.LoadConfig(fsys, "config.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cl, err := cfg.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The server certificate is only trusted through ca.pem.
+	if _, err := cl.Account(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.LoadTLSConfig(fsys, "", "", "config.json"); err == nil {
+		t.Fatal("expected error for file without certificates")
+	}
+}
@@ -0,0 +1,164 @@
+// Package correlation carries metadata, such as a tenant or trace ID, from the
+// context of a transfer or debit to the hook events RTWire later sends for its
+// transaction, so that a payment can be followed across the asynchronous
+// boundary.
+//
+// RTWire transactions have no field for caller metadata, so it is saved in a
+// Store under the transaction ID when the call is made and loaded again when
+// an event for that ID arrives:
+//
+//	store := correlation.NewMemoryStore(24 * time.Hour)
+//	cl = client.WithCallMiddleware(cl, correlation.Calls(store))
+//
+//	ctx = correlation.With(ctx, map[string]string{"tenant": "acme"})
+//	cl.TransferContext(ctx, txID, from, to, value)
+//
+//	// In the hook handler.
+//	ctx, err := correlation.Event(r.Context(), store, event)
+//	tenant := correlation.From(ctx)["tenant"]
+//
+// Events for transactions made by other processes, or credits from outside
+// RTWire, have no metadata.
+package correlation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// ErrNotFound is returned from Store.Load when no metadata is saved for a
+// transaction.
+var ErrNotFound = errors.New("correlation not found")
+
+type contextKey struct{}
+
+// With returns a copy of ctx carrying md, merged over any metadata ctx already
+// carries.
+func With(ctx context.Context, md map[string]string) context.Context {
+	merged := From(ctx)
+	for k, v := range md {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, contextKey{}, merged)
+}
+
+// From returns a copy of the metadata carried by ctx.
+func From(ctx context.Context) map[string]string {
+	md, _ := ctx.Value(contextKey{}).(map[string]string)
+	return copyMetadata(md)
+}
+
+func copyMetadata(md map[string]string) map[string]string {
+	c := make(map[string]string, len(md))
+	for k, v := range md {
+		c[k] = v
+	}
+	return c
+}
+
+// Store saves the metadata of transactions.
+type Store interface {
+	Save(ctx context.Context, txID int64, md map[string]string) error
+	Load(ctx context.Context, txID int64) (map[string]string, error)
+}
+
+// txMethods are the calls whose first argument is the ID of the transaction
+// they create.
+var txMethods = map[string]bool{
+	"Transfer":  true,
+	"Debit":     true,
+	"DebitMany": true,
+}
+
+// Calls returns a client.CallMiddleware that saves the metadata carried by the
+// context of each Transfer, Debit and DebitMany call to store under the call's
+// transaction ID. It is saved before the call is made, as its events may
+// arrive before the call returns, and the call is refused if it can't be
+// saved. Calls without metadata are not saved.
+func Calls(store Store) client.CallMiddleware {
+	return func(next client.CallHandler) client.CallHandler {
+		return func(ctx context.Context, call client.Call) error {
+			md := From(ctx)
+			if !txMethods[call.Method] || len(md) == 0 ||
+				len(call.Args) == 0 {
+				return next(ctx, call)
+			}
+			txID, ok := call.Args[0].(int64)
+			if !ok {
+				return next(ctx, call)
+			}
+			if err := store.Save(ctx, txID, md); err != nil {
+				return fmt.Errorf("correlation: save transaction %d: %w",
+					txID, err)
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// Event returns a copy of ctx carrying the metadata saved for the transaction
+// of e, if any. The error of store is returned unless it is ErrNotFound.
+func Event(ctx context.Context, store Store,
+	e client.TransactionEvent) (context.Context, error) {
+	md, err := store.Load(ctx, e.ID)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return ctx, nil
+	case err != nil:
+		return ctx, err
+	}
+	return With(ctx, md), nil
+}
+
+// MemoryStore is a Store that keeps metadata in memory for a fixed duration.
+// It only correlates events received by the process that made the call.
+type MemoryStore struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[int64]entry
+}
+
+type entry struct {
+	md      map[string]string
+	expires time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore keeping metadata for ttl, which
+// should cover the time until the last event of a transaction, such as a
+// debit's confirmation.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, entries: map[int64]entry{}}
+}
+
+// Save saves a copy of md for txID and drops expired metadata.
+func (m *MemoryStore) Save(ctx context.Context, txID int64,
+	md map[string]string) error {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, e := range m.entries {
+		if now.After(e.expires) {
+			delete(m.entries, id)
+		}
+	}
+	m.entries[txID] = entry{copyMetadata(md), now.Add(m.ttl)}
+	return nil
+}
+
+// Load returns a copy of the metadata saved for txID.
+func (m *MemoryStore) Load(ctx context.Context, txID int64) (
+	map[string]string, error) {
+	m.mu.Lock()
+	e, ok := m.entries[txID]
+	m.mu.Unlock()
+	if !ok || time.Now().After(e.expires) {
+		return nil, ErrNotFound
+	}
+	return copyMetadata(e.md), nil
+}
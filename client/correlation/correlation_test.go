@@ -0,0 +1,84 @@
+package correlation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/clientmock"
+	"github.com/rtwire/go/client/correlation"
+)
+
+func TestCorrelation(t *testing.T) {
+
+	store := correlation.NewMemoryStore(time.Hour)
+	m := &clientmock.Client{
+		TransferFunc: func(ctx context.Context, txID, from, to int64,
+			value client.Amount, options ...client.Option) (
+			client.Transaction, error) {
+			return client.Transaction{ID: txID}, nil
+		},
+	}
+	cl := client.WithCallMiddleware(m, correlation.Calls(store))
+
+	ctx := correlation.With(context.Background(),
+		map[string]string{"tenant": "acme"})
+	ctx = correlation.With(ctx, map[string]string{"trace": "t1"})
+	if _, err := cl.TransferContext(ctx, 5, 1, 2, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cl.Transfer(6, 1, 2, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	event := client.TransactionEvent{
+		Transaction: client.Transaction{ID: 5}, Status: "credited"}
+	ctx, err := correlation.Event(context.Background(), store, event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := correlation.From(ctx)
+	if md["tenant"] != "acme" || md["trace"] != "t1" {
+		t.Fatalf("expected metadata of the transfer %v", md)
+	}
+
+	// Transfers made without metadata have none.
+	event.ID = 6
+	if ctx, err := correlation.Event(context.Background(), store,
+		event); err != nil || len(correlation.From(ctx)) != 0 {
+		t.Fatal("expected no metadata", correlation.From(ctx), err)
+	}
+}
+
+func TestCallsStoreError(t *testing.T) {
+
+	errStore := errors.New("store unavailable")
+	m := &clientmock.Client{}
+	cl := client.WithCallMiddleware(m, correlation.Calls(failingStore{errStore}))
+
+	ctx := correlation.With(context.Background(),
+		map[string]string{"tenant": "acme"})
+	if _, err := cl.TransferContext(ctx, 5, 1, 2, 10); !errors.Is(err,
+		errStore) {
+		t.Fatal("expected store error", err)
+	}
+	if calls := m.Calls(); len(calls) != 0 {
+		t.Fatal("expected transfer to be refused", calls)
+	}
+}
+
+type failingStore struct {
+	err error
+}
+
+func (s failingStore) Save(ctx context.Context, txID int64,
+	md map[string]string) error {
+	return s.err
+}
+
+func (s failingStore) Load(ctx context.Context, txID int64) (
+	map[string]string, error) {
+	return nil, s.err
+}
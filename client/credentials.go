@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// Credentials holds the user and pass used to authenticate with RTWire so that
+// they can be rotated while the client is in use. Create them with
+// NewCredentials and pass them to New with WithCredentials.
+type Credentials struct {
+	mu      sync.Mutex
+	current *credentialSet
+}
+
+// credentialSet is one generation of credentials and the calls using them.
+type credentialSet struct {
+	user     string
+	pass     string
+	inFlight sync.WaitGroup
+}
+
+// NewCredentials returns credentials authenticating as user and pass.
+func NewCredentials(user, pass string) *Credentials {
+	return &Credentials{current: &credentialSet{user: user, pass: pass}}
+}
+
+// acquire returns the current credentials. The caller must call
+// inFlight.Done once the call using them, including any retries, has
+// finished.
+func (c *Credentials) acquire() *credentialSet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current.inFlight.Add(1)
+	return c.current
+}
+
+// Rotate switches calls made from now on to user and pass, then waits for the
+// calls already in flight under the previous credentials to finish, including
+// their retries, so that the previous credentials can be revoked once Rotate
+// returns. If ctx is done first its error is returned; the new credentials
+// remain in use either way.
+func (c *Credentials) Rotate(ctx context.Context, user, pass string) error {
+	c.mu.Lock()
+	old := c.current
+	c.current = &credentialSet{user: user, pass: pass}
+	c.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		old.inFlight.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WithCredentials authenticates with creds, replacing the user and pass given
+// to New. Each call uses the credentials current when it starts for all of its
+// attempts.
+func WithCredentials(creds *Credentials) ClientOption {
+	return func(c *client) {
+		c.credentials = creds
+	}
+}
@@ -0,0 +1,68 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestCredentialsRotate(t *testing.T) {
+
+	arrived := make(chan string, 2)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			user, _, _ := r.BasicAuth()
+			arrived <- user
+			if user == "old" {
+				<-release
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "accounts", "payload": [{"id": 1}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	creds := client.NewCredentials("old", "pass")
+	cl := client.New(http.DefaultClient, url, "unused", "unused",
+		client.WithCredentials(creds))
+
+	oldErr := make(chan error, 1)
+	go func() {
+		_, err := cl.Account(1)
+		oldErr <- err
+	}()
+	if user := <-arrived; user != "old" {
+		t.Fatal("expected old credentials", user)
+	}
+
+	// Rotation waits for the old call, but new calls use the new
+	// credentials as soon as it starts.
+	ctx, cancel := context.WithTimeout(context.Background(),
+		50*time.Millisecond)
+	defer cancel()
+	if err := creds.Rotate(ctx, "new", "pass"); err != context.DeadlineExceeded {
+		t.Fatal("expected rotation to wait for the old call", err)
+	}
+	if _, err := cl.Account(1); err != nil {
+		t.Fatal(err)
+	}
+	if user := <-arrived; user != "new" {
+		t.Fatal("expected new credentials", user)
+	}
+
+	close(release)
+	if err := <-oldErr; err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is in flight under the new credentials.
+	if err := creds.Rotate(context.Background(), "newer", "pass"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,296 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// EnrichedEvent is a TransactionEvent together with the full transaction and
+// the RTWire accounts involved in it. FromAccount and ToAccount are nil when
+// that side of the transaction is not an RTWire account, for example the
+// sender of a credit.
+type EnrichedEvent struct {
+	Event       TransactionEvent
+	Transaction Transaction
+	FromAccount *Account
+	ToAccount   *Account
+}
+
+// EventEnricher fetches the transaction and account details for hook events
+// so handlers don't need to make their own calls to RTWire. Lookups are made
+// concurrently, limited to a fixed number in flight, and cached. Concurrent
+// lookups of the same transaction or account share a single call. Transactions
+// are cached once they are no longer pending and accounts are cached for a
+// fixed duration as their balances change. The rate of calls is limited by
+// the client's own limiter, set with WithRateLimit.
+//
+// At most maxEnrichCache transactions and as many accounts are cached. Expired
+// accounts are dropped as others are cached, and once a cache is full an
+// arbitrary entry is dropped for each one added, so memory use is bounded in
+// a long-running hook receiver.
+type EventEnricher struct {
+	client     Client
+	sem        chan struct{}
+	accountTTL time.Duration
+
+	mu       sync.Mutex
+	txns     map[int64]Transaction
+	accounts map[int64]cachedAccount
+	inFlight map[lookupKey]*lookup
+}
+
+// maxEnrichCache is the number of transactions, and of accounts, an
+// EventEnricher caches.
+const maxEnrichCache = 10000
+
+type cachedAccount struct {
+	account Account
+	expires time.Time
+}
+
+type lookupKey struct {
+	account bool
+	id      int64
+}
+
+// lookup is a call to RTWire in flight, whose result is shared by every
+// caller wanting it.
+type lookup struct {
+	done chan struct{}
+	tx   Transaction
+	acc  Account
+	err  error
+}
+
+// NewEventEnricher creates an EventEnricher that makes at most concurrency
+// simultaneous calls to c and caches accounts for accountTTL.
+func NewEventEnricher(c Client, concurrency int,
+	accountTTL time.Duration) *EventEnricher {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &EventEnricher{
+		client:     c,
+		sem:        make(chan struct{}, concurrency),
+		accountTTL: accountTTL,
+		txns:       map[int64]Transaction{},
+		accounts:   map[int64]cachedAccount{},
+		inFlight:   map[lookupKey]*lookup{},
+	}
+}
+
+// Enrich returns an EnrichedEvent for each event in events. If any lookup
+// fails the first error is returned. Lookups are made with ctx.
+func (e *EventEnricher) Enrich(ctx context.Context,
+	events []TransactionEvent) ([]EnrichedEvent, error) {
+	enriched := make([]EnrichedEvent, len(events))
+	errs := make([]error, len(events))
+
+	var wg sync.WaitGroup
+	for i, event := range events {
+		wg.Add(1)
+		go func(i int, event TransactionEvent) {
+			defer wg.Done()
+			enriched[i], errs[i] = e.enrich(ctx, event)
+		}(i, event)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return enriched, nil
+}
+
+func (e *EventEnricher) enrich(ctx context.Context, event TransactionEvent) (
+	EnrichedEvent, error) {
+	tx, err := e.transaction(ctx, event.ID, event.Status != "pending")
+	if err != nil {
+		return EnrichedEvent{}, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		from, to *Account
+		fromErr  error
+		toErr    error
+	)
+	if tx.FromAccountID != 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			from, fromErr = e.account(ctx, tx.FromAccountID)
+		}()
+	}
+	if tx.ToAccountID != 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			to, toErr = e.account(ctx, tx.ToAccountID)
+		}()
+	}
+	wg.Wait()
+
+	if fromErr != nil {
+		return EnrichedEvent{}, fromErr
+	}
+	if toErr != nil {
+		return EnrichedEvent{}, toErr
+	}
+	return EnrichedEvent{
+		Event:       event,
+		Transaction: tx,
+		FromAccount: from,
+		ToAccount:   to,
+	}, nil
+}
+
+// share makes the lookup for key with fn, or waits for the one already in
+// flight. Callers sharing a lookup stop waiting when their ctx is done.
+func (e *EventEnricher) share(ctx context.Context, key lookupKey,
+	fn func(l *lookup)) (*lookup, error) {
+	for {
+		e.mu.Lock()
+		l, ok := e.inFlight[key]
+		if !ok {
+			l = &lookup{done: make(chan struct{})}
+			e.inFlight[key] = l
+		}
+		e.mu.Unlock()
+
+		if !ok {
+			select {
+			case e.sem <- struct{}{}:
+				fn(l)
+				<-e.sem
+			case <-ctx.Done():
+				l.err = ctx.Err()
+			}
+			e.mu.Lock()
+			delete(e.inFlight, key)
+			e.mu.Unlock()
+			close(l.done)
+			return l, l.err
+		}
+
+		select {
+		case <-l.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		// Make the lookup again if the caller making it gave up.
+		if isContextErr(l.err) && ctx.Err() == nil {
+			continue
+		}
+		return l, l.err
+	}
+}
+
+func (e *EventEnricher) transaction(ctx context.Context, id int64,
+	cache bool) (Transaction, error) {
+	e.mu.Lock()
+	tx, ok := e.txns[id]
+	e.mu.Unlock()
+	if ok {
+		return tx, nil
+	}
+
+	l, err := e.share(ctx, lookupKey{id: id}, func(l *lookup) {
+		l.tx, l.err = e.client.TransactionContext(ctx, id)
+		if l.err == nil && cache {
+			e.mu.Lock()
+			if len(e.txns) >= maxEnrichCache {
+				for old := range e.txns {
+					delete(e.txns, old)
+					break
+				}
+			}
+			e.txns[id] = l.tx
+			e.mu.Unlock()
+		}
+	})
+	if err != nil {
+		return Transaction{}, err
+	}
+	return l.tx, nil
+}
+
+func (e *EventEnricher) account(ctx context.Context, id int64) (
+	*Account, error) {
+	now := time.Now()
+	e.mu.Lock()
+	cached, ok := e.accounts[id]
+	e.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		acc := cached.account
+		return &acc, nil
+	}
+
+	l, err := e.share(ctx, lookupKey{account: true, id: id},
+		func(l *lookup) {
+			l.acc, l.err = e.client.AccountContext(ctx, id)
+			if l.err == nil {
+				e.mu.Lock()
+				e.cacheAccount(id, l.acc, now)
+				e.mu.Unlock()
+			}
+		})
+	if err != nil {
+		return nil, err
+	}
+	acc := l.acc
+	return &acc, nil
+}
+
+// cacheAccount caches acc, the account id fetched at now, after dropping the
+// expired accounts. e.mu must be held.
+func (e *EventEnricher) cacheAccount(id int64, acc Account,
+	now time.Time) {
+	for cachedID, cached := range e.accounts {
+		if !now.Before(cached.expires) {
+			delete(e.accounts, cachedID)
+		}
+	}
+	if _, ok := e.accounts[id]; !ok && len(e.accounts) >= maxEnrichCache {
+		for cachedID := range e.accounts {
+			delete(e.accounts, cachedID)
+			break
+		}
+	}
+	e.accounts[id] = cachedAccount{
+		account: acc,
+		expires: now.Add(e.accountTTL),
+	}
+}
+
+// Handler returns an http.Handler for RTWire hook requests which enriches the
+// received transaction events, using the request's context, and passes them to
+// fn. Other kinds of event are acknowledged without calling fn. A failed
+// lookup or an error from fn results in a 500 response so that RTWire retries
+// the delivery.
+func (e *EventEnricher) Handler(fn func([]EnrichedEvent) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events, err := UnmarshalEvents(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		txEvents := transactionEvents(events)
+		if len(txEvents) == 0 {
+			return
+		}
+		enriched, err := e.Enrich(r.Context(), txEvents)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := fn(enriched); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	})
+}
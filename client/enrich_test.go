@@ -0,0 +1,168 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/clientmock"
+)
+
+func TestEventEnricher(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"GET /transactions/1": `{"type": "transactions", "payload": [{
+			"id": 1, "type": "transfer",
+			"fromAccountID": 2, "toAccountID": 3, "value": 5}]}`,
+		"GET /accounts/2": `{"type": "accounts",
+			"payload": [{"id": 2, "balance": 10}]}`,
+		"GET /accounts/3": `{"type": "accounts",
+			"payload": [{"id": 3, "balance": 5}]}`,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	enricher := client.NewEventEnricher(cl, 2, time.Minute)
+
+	var got []client.EnrichedEvent
+	handler := enricher.Handler(func(events []client.EnrichedEvent) error {
+		got = events
+		return nil
+	})
+
+	body := `{"type": "transactions", "payload": [{"id": 1, "type": "transfer"}]}`
+	req := httptest.NewRequest("POST", "/", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatal("expected status ok", rec.Code, rec.Body.String())
+	}
+
+	if len(got) != 1 {
+		t.Fatal("expected one event")
+	}
+	if got[0].Transaction.Value != 5 {
+		t.Fatal("transaction not populated")
+	}
+	if got[0].FromAccount == nil || got[0].FromAccount.Balance != 10 {
+		t.Fatal("from account not populated")
+	}
+	if got[0].ToAccount == nil || got[0].ToAccount.Balance != 5 {
+		t.Fatal("to account not populated")
+	}
+
+	// Other kinds of event are acknowledged without calling the handler.
+	got = nil
+	body = `{"type": "accounts", "payload": [{"id": 4}]}`
+	req = httptest.NewRequest("POST", "/", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || got != nil {
+		t.Fatal("expected account event to be acknowledged", rec.Code)
+	}
+
+	// Missing transactions fail the delivery so RTWire retries.
+	body = `{"type": "transactions", "payload": [{"id": 9}]}`
+	req = httptest.NewRequest("POST", "/", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatal("expected internal server error", rec.Code)
+	}
+}
+
+func TestEventEnricherSharesLookups(t *testing.T) {
+
+	var requests int32
+	arrived := make(chan struct{}, 10)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			arrived <- struct{}{}
+			if r.URL.Path == "/v1/mainnet/transactions/1" {
+				atomic.AddInt32(&requests, 1)
+				<-release
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "transactions", "payload": [{"id": 1}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+	enricher := client.NewEventEnricher(cl, 1, time.Minute)
+
+	// Pending transactions are not cached so every event needs a lookup.
+	events := make([]client.TransactionEvent, 5)
+	for i := range events {
+		events[i].ID = 1
+		events[i].Status = "pending"
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := enricher.Enrich(context.Background(), events)
+		done <- err
+	}()
+	<-arrived
+
+	// A lookup waiting for the only slot gives up with its context.
+	ctx, cancel := context.WithTimeout(context.Background(),
+		50*time.Millisecond)
+	defer cancel()
+	_, err := enricher.Enrich(ctx, []client.TransactionEvent{
+		{Transaction: client.Transaction{ID: 2}},
+	})
+	if err != context.DeadlineExceeded {
+		t.Fatal("expected deadline exceeded", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatal("expected one shared lookup got", n)
+	}
+}
+
+func TestEventEnricherCacheBound(t *testing.T) {
+
+	var calls int64
+	m := &clientmock.Client{
+		TransactionFunc: func(ctx context.Context, txID int64,
+			options ...client.Option) (client.Transaction, error) {
+			atomic.AddInt64(&calls, 1)
+			return client.Transaction{ID: txID}, nil
+		},
+	}
+	e := client.NewEventEnricher(m, 8, time.Hour)
+
+	const n = 10001
+	events := make([]client.TransactionEvent, n)
+	for i := range events {
+		events[i].ID = int64(i + 1)
+	}
+	for round := 0; round < 2; round++ {
+		if _, err := e.Enrich(context.Background(), events); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Released transactions are cached, but not more than the cache holds.
+	if calls <= n || calls == 2*n {
+		t.Fatal("expected a bounded cache", calls)
+	}
+}
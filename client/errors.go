@@ -0,0 +1,126 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// APIError is returned when RTWire responds with an error. Sentinel errors
+// such as ErrInsufficientFunds or ErrNotFound can be detected with errors.Is,
+// including when an APIError has been wrapped, and the full details with
+// errors.As.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Code is the RTWire error code, if the response included one.
+	Code string
+
+	// Message is the error message returned by RTWire. If the response could
+	// not be decoded it is the request URL followed by the response body.
+	Message string
+
+	// URL is the URL of the request that failed.
+	URL string
+
+	// RequestID is the X-Request-ID sent with the request, to be quoted when
+	// reporting the failure to RTWire.
+	RequestID string
+
+	// Header holds the response headers.
+	Header http.Header
+
+	// Body is the raw response body.
+	Body []byte
+
+	// RetryAfter is the delay requested by the Retry-After header, or zero
+	// if there was none.
+	RetryAfter time.Duration
+
+	// RateLimit holds the rate limit headers of the response.
+	RateLimit RateLimit
+}
+
+// RateLimit describes the request quota reported by RTWire's X-RateLimit
+// headers. Fields are zero when the corresponding header is absent.
+type RateLimit struct {
+	// Limit is the number of requests allowed in the current window.
+	Limit int
+
+	// Remaining is the number of requests left in the current window.
+	Remaining int
+
+	// Reset is when the current window ends.
+	Reset time.Time
+}
+
+// newAPIError returns an APIError holding the metadata of resp. The caller
+// sets Code and Message.
+func newAPIError(req *http.Request, resp *http.Response,
+	body []byte) *APIError {
+
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		URL:        req.URL.String(),
+		RequestID:  req.Header.Get(RequestIDHeader),
+		Header:     resp.Header,
+		Body:       body,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	e.RateLimit.Limit, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	e.RateLimit.Remaining, _ = strconv.Atoi(
+		resp.Header.Get("X-RateLimit-Remaining"))
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"),
+		10, 64); err == nil {
+		e.RateLimit.Reset = time.Unix(reset, 0)
+	}
+	return e
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// Error returns the message returned by RTWire.
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// sentinels maps RTWire error codes and messages, in lower case, to the
+// sentinel errors they correspond to.
+var sentinels = map[string]error{
+	"txid used":          ErrTxIDUsed,
+	"insufficient funds": ErrInsufficientFunds,
+	"account closed":     ErrAccountClosed,
+	"hook exists":        ErrHookExists,
+	"not found":          ErrNotFound,
+}
+
+// Is reports whether target is the sentinel error corresponding to e's code or
+// message. A 404 response matches ErrNotFound.
+func (e *APIError) Is(target error) bool {
+	if e.StatusCode == http.StatusNotFound && target == ErrNotFound {
+		return true
+	}
+	for _, s := range []string{e.Code, e.Message} {
+		if err, ok := sentinels[strings.ToLower(s)]; ok && err == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestAPIError(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"PUT /transactions/": `{"type": "errors",
+			"payload": [{"message": "insufficient funds"}]}`,
+		"POST /hooks/": `{"type": "errors",
+			"payload": [{"code": "hook exists", "message": "duplicate"}]}`,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	_, err := cl.Transfer(1, 2, 3, 4)
+	if !errors.Is(err, client.ErrInsufficientFunds) {
+		t.Fatal("expected insufficient funds", err)
+	}
+	if wrapped := fmt.Errorf("payout: %w", err); !errors.Is(wrapped,
+		client.ErrInsufficientFunds) {
+		t.Fatal("expected wrapped insufficient funds")
+	}
+
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("expected APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatal("unexpected status code", apiErr.StatusCode)
+	}
+	if apiErr.URL != url+"/transactions/" {
+		t.Fatal("unexpected URL", apiErr.URL)
+	}
+	if errors.Is(err, client.ErrHookExists) {
+		t.Fatal("unexpected match")
+	}
+
+	err = cl.CreateHook("https://example.com/hook")
+	if !errors.Is(err, client.ErrHookExists) {
+		t.Fatal("expected hook exists", err)
+	}
+	if errors.As(err, &apiErr); apiErr.Code != "hook exists" ||
+		apiErr.Message != "duplicate" {
+		t.Fatalf("unexpected error %+v", apiErr)
+	}
+
+	_, err = cl.Transaction(1)
+	if !errors.Is(err, client.ErrNotFound) || !errors.As(err, &apiErr) {
+		t.Fatal("expected not found APIError", err)
+	}
+}
+
+func TestAPIErrorMetadata(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.Header().Set("X-RateLimit-Limit", "100")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "1700000000")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	_, err := cl.Fees()
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("expected APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatal("unexpected status code", apiErr.StatusCode)
+	}
+	if apiErr.RetryAfter != 30*time.Second {
+		t.Fatal("unexpected retry after", apiErr.RetryAfter)
+	}
+	expected := client.RateLimit{
+		Limit:     100,
+		Remaining: 0,
+		Reset:     time.Unix(1700000000, 0),
+	}
+	if apiErr.RateLimit != expected {
+		t.Fatalf("unexpected rate limit %+v", apiErr.RateLimit)
+	}
+	if string(apiErr.Body) != "slow down\n" {
+		t.Fatalf("unexpected body %q", apiErr.Body)
+	}
+	if apiErr.Header.Get("X-RateLimit-Limit") != "100" {
+		t.Fatal("expected response headers")
+	}
+}
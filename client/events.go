@@ -0,0 +1,132 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+// Event is an event delivered to a registered hook. Use a type switch on the
+// concrete types, TransactionEvent, AccountCreatedEvent, AddressCreatedEvent,
+// AddressExpiredEvent and HookDisabledEvent, to handle each kind of event.
+type Event interface {
+	// EventType returns the name of the kind of event, for example
+	// "account-created".
+	EventType() string
+}
+
+// EventType returns "transaction".
+func (TransactionEvent) EventType() string { return "transaction" }
+
+// AccountCreatedEvent is sent to hooks when an account is created.
+type AccountCreatedEvent struct {
+	Account
+}
+
+// EventType returns "account-created".
+func (AccountCreatedEvent) EventType() string { return "account-created" }
+
+// AddressCreatedEvent is sent to hooks when an address is created for an
+// account.
+type AddressCreatedEvent struct {
+	AccountID int64  `json:"accountID"`
+	Address   string `json:"address"`
+}
+
+// EventType returns "address-created".
+func (AddressCreatedEvent) EventType() string { return "address-created" }
+
+// AddressExpiredEvent is sent to hooks when a payment reaches an address
+// after it expired, or after its first payment if it is single-use. The
+// payment is still credited to the account, as Transaction, so that it can be
+// refunded or matched by hand rather than collected silently.
+type AddressExpiredEvent struct {
+	Address     string      `json:"address"`
+	Transaction Transaction `json:"transaction"`
+}
+
+// EventType returns "address-expired".
+func (AddressExpiredEvent) EventType() string { return "address-expired" }
+
+// HookDisabledEvent is sent to the remaining hooks when RTWire disables a hook,
+// for example after repeated failed deliveries. AccountID is zero for hooks
+// not registered to an account.
+type HookDisabledEvent struct {
+	AccountID int64  `json:"accountID"`
+	URL       string `json:"url"`
+	Reason    string `json:"reason"`
+}
+
+// EventType returns "hook-disabled".
+func (HookDisabledEvent) EventType() string { return "hook-disabled" }
+
+// UnmarshalEvents takes an http.Request that has been generated by an RTWire
+// hook and returns the events it contains. Unlike Unmarshal it accepts every
+// kind of event RTWire sends.
+func UnmarshalEvents(r *http.Request) ([]Event, error) {
+	obj, err := unmarshalHookObject(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch obj.Type {
+	case "transactions":
+		return decodeEvents[TransactionEvent](obj.Payload)
+	case "accounts":
+		return decodeEvents[AccountCreatedEvent](obj.Payload)
+	case "addresses":
+		return decodeEvents[AddressCreatedEvent](obj.Payload)
+	case "expiredAddresses":
+		return decodeEvents[AddressExpiredEvent](obj.Payload)
+	case "hooks":
+		return decodeEvents[HookDisabledEvent](obj.Payload)
+	default:
+		return nil, fmt.Errorf("unknown object type %v", obj.Type)
+	}
+}
+
+// IsJSON reports whether the Content-Type of r, a hook delivery, is the
+// application/json media type, whatever its parameters, such as the charset
+// of a hook created with HookContentType.
+func IsJSON(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+func unmarshalHookObject(r *http.Request) (*object, error) {
+	if !IsJSON(r) {
+		return nil, errors.New("incorrect content type")
+	}
+	defer r.Body.Close()
+
+	obj := &object{}
+	if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func decodeEvents[T Event](payload json.RawMessage) ([]Event, error) {
+	var typed []T
+	if err := json.Unmarshal(payload, &typed); err != nil {
+		return nil, err
+	}
+	events := make([]Event, len(typed))
+	for i, e := range typed {
+		events[i] = e
+	}
+	return events, nil
+}
+
+// transactionEvents returns the TransactionEvents of events, in order.
+func transactionEvents(events []Event) []TransactionEvent {
+	var txEvents []TransactionEvent
+	for _, e := range events {
+		if tx, ok := e.(TransactionEvent); ok {
+			txEvents = append(txEvents, tx)
+		}
+	}
+	return txEvents
+}
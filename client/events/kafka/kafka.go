@@ -0,0 +1,104 @@
+// Package kafka publishes RTWire transaction events to a Kafka topic, so that
+// consumers of a ledger can read them from Kafka rather than receive hooks.
+//
+// A Sink encodes each event as the JSON of client.TransactionEvent and writes
+// it with a Writer, which wraps the producer of a Kafka client library, for
+// example kafka-go's Writer or a sarama SyncProducer. Sink.Send is a handler
+// for both hookserver.Handler and client.Watcher:
+//
+//	sink := &kafka.Sink{Writer: w, Key: kafka.AccountKey}
+//	http.Handle("/hook", &hookserver.Handler{
+//		OnCredit: sink.Send,
+//		OnDebit:  sink.Send,
+//	})
+//
+// Events are published at least once: a hook delivery is only acknowledged,
+// and a Watcher only moves past an event, once the Writer has returned. An
+// event may therefore be published more than once, and consumers can remove
+// duplicates using the message's KeyHeader.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/hookserver"
+)
+
+// KeyHeader is the header holding hookserver.Key of the event published in a
+// message, which is the same each time the event is published.
+const KeyHeader = "rtwire-event"
+
+// Message is a Kafka message to write to the topic of a Writer.
+type Message struct {
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// Writer writes messages to a Kafka topic. Implementations must only return
+// nil once every message is acknowledged by the brokers, for example by
+// requiring acks from all in-sync replicas, for events to be published at
+// least once.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...Message) error
+}
+
+// KeyFunc returns the key of the message an event is published in. Events
+// with the same key are kept in order on one partition.
+type KeyFunc func(event client.TransactionEvent) []byte
+
+// AccountKey keys an event by the decimal ID of the account it is for: the
+// account credited by a credit and the account debited otherwise.
+func AccountKey(event client.TransactionEvent) []byte {
+	id := event.FromAccountID
+	if event.Type == "credit" {
+		id = event.ToAccountID
+	}
+	return strconv.AppendInt(nil, id, 10)
+}
+
+// Sink publishes transaction events with a Writer.
+type Sink struct {
+	Writer Writer
+
+	// Key returns the key of each message. Messages have no key if it is
+	// nil, and are spread over the topic's partitions.
+	Key KeyFunc
+}
+
+// Send publishes event, returning the error of the Writer so that the event
+// is delivered again.
+func (s *Sink) Send(ctx context.Context, event client.TransactionEvent) error {
+	return s.SendAll(ctx, []client.TransactionEvent{event})
+}
+
+// SendAll publishes events in one write, for example for the batches of a
+// client.Batcher.
+func (s *Sink) SendAll(ctx context.Context,
+	events []client.TransactionEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	msgs := make([]Message, len(events))
+	for i, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		msgs[i] = Message{
+			Value:   value,
+			Headers: map[string]string{KeyHeader: hookserver.Key(event)},
+		}
+		if s.Key != nil {
+			msgs[i].Key = s.Key(event)
+		}
+	}
+	if err := s.Writer.WriteMessages(ctx, msgs...); err != nil {
+		return fmt.Errorf("kafka: publish %d events: %w", len(msgs), err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package kafka_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/events/kafka"
+)
+
+type writer struct {
+	msgs []kafka.Message
+	err  error
+}
+
+func (w *writer) WriteMessages(ctx context.Context,
+	msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.msgs = append(w.msgs, msgs...)
+	return nil
+}
+
+func TestSink(t *testing.T) {
+
+	w := &writer{}
+	sink := &kafka.Sink{Writer: w, Key: kafka.AccountKey}
+
+	events := []client.TransactionEvent{{
+		Transaction: client.Transaction{ID: 1, Type: "credit",
+			ToAccountID: 4, Value: 10},
+		Status: "pending",
+	}, {
+		Transaction: client.Transaction{ID: 2, Type: "debit",
+			FromAccountID: 5, Value: 20},
+	}}
+	if err := sink.SendAll(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.msgs) != 2 {
+		t.Fatal("expected a message per event", w.msgs)
+	}
+	for i, want := range []struct{ key, header string }{
+		{"4", "1/pending"}, {"5", "2/"},
+	} {
+		msg := w.msgs[i]
+		if string(msg.Key) != want.key ||
+			msg.Headers[kafka.KeyHeader] != want.header {
+			t.Fatalf("%d: unexpected message %q %v", i, msg.Key, msg.Headers)
+		}
+		var got client.TransactionEvent
+		if err := json.Unmarshal(msg.Value, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != events[i].ID || got.Status != events[i].Status ||
+			got.Value != events[i].Value {
+			t.Fatalf("%d: unexpected event %+v", i, got)
+		}
+	}
+
+	failed := errors.New("broker unavailable")
+	w.err = failed
+	if err := sink.Send(context.Background(),
+		events[0]); !errors.Is(err, failed) {
+		t.Fatal("expected writer error", err)
+	}
+
+	// Without a KeyFunc messages have no key.
+	w.err = nil
+	w.msgs = nil
+	sink.Key = nil
+	if err := sink.Send(context.Background(), events[0]); err != nil {
+		t.Fatal(err)
+	}
+	if w.msgs[0].Key != nil {
+		t.Fatal("unexpected key", w.msgs[0].Key)
+	}
+}
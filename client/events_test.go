@@ -0,0 +1,97 @@
+package client_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestUnmarshalEvents(t *testing.T) {
+
+	tests := []struct {
+		body     string
+		expected client.Event
+	}{
+		{`{"type": "transactions", "payload": [{"id": 1, "status": "pending"}]}`,
+			client.TransactionEvent{
+				Transaction: client.Transaction{ID: 1},
+				Status:      "pending",
+			}},
+		{`{"type": "accounts", "payload": [{"id": 2, "balance": 0}]}`,
+			client.AccountCreatedEvent{Account: client.Account{ID: 2}}},
+		{`{"type": "addresses", "payload": [{"accountID": 2, "address": "a"}]}`,
+			client.AddressCreatedEvent{AccountID: 2, Address: "a"}},
+		{`{"type": "expiredAddresses", "payload": [{"address": "a",
+			"transaction": {"id": 3, "type": "credit", "toAccountID": 2}}]}`,
+			client.AddressExpiredEvent{Address: "a",
+				Transaction: client.Transaction{ID: 3, Type: "credit",
+					ToAccountID: 2}}},
+		{`{"type": "hooks", "payload": [{"url": "https://example.com",
+			"reason": "failing"}]}`,
+			client.HookDisabledEvent{URL: "https://example.com",
+				Reason: "failing"}},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(test.body))
+		r.Header.Set("Content-Type", "application/json")
+
+		events, err := client.UnmarshalEvents(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 {
+			t.Fatal("expected one event", events)
+		}
+
+		switch e := events[0].(type) {
+		case client.TransactionEvent:
+			expected := test.expected.(client.TransactionEvent)
+			if e.ID != expected.ID || e.Status != expected.Status {
+				t.Fatalf("unexpected event %+v", e)
+			}
+		default:
+			if !reflect.DeepEqual(e, test.expected) {
+				t.Fatalf("unexpected %s event %+v", e.EventType(), e)
+			}
+		}
+	}
+
+	r := httptest.NewRequest("POST", "/", strings.NewReader(
+		`{"type": "unknown", "payload": []}`))
+	r.Header.Set("Content-Type", "application/json")
+	if _, err := client.UnmarshalEvents(r); err == nil {
+		t.Fatal("expected error for unknown type")
+	}
+}
+
+func TestUnmarshalContentType(t *testing.T) {
+
+	const body = `{"type": "transactions", "payload": [{"id": 1}]}`
+	tests := []struct {
+		contentType string
+		ok          bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"Application/JSON;charset=UTF-8", true},
+		{"text/plain", false},
+		{"application/jsonx", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		r.Header.Set("Content-Type", test.contentType)
+		if client.IsJSON(r) != test.ok {
+			t.Errorf("%q: expected JSON %v", test.contentType, test.ok)
+		}
+		events, err := client.Unmarshal(r)
+		if (err == nil) != test.ok || (test.ok && len(events) != 1) {
+			t.Errorf("%q: unexpected result %v %v", test.contentType,
+				events, err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Package export streams the full transaction history of an account to object
+// storage, such as S3 or GCS, as gzip compressed NDJSON parts, one
+// transaction per line.
+//
+// Parts are streamed to an Uploader while transactions are fetched, so memory
+// use does not depend on the size of the history, and fetching pauses while
+// the upload is slower than RTWire. Progress is saved to a CheckpointStore
+// after every part so an interrupted export continues from the last complete
+// part. The part being written when an export stopped is written again, under
+// the same name, when it is resumed.
+package export
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/rtwire/go/client"
+)
+
+// Uploader writes an object. Implementations wrap a storage client, for
+// example an S3 multipart upload manager, and must read r to EOF or return an
+// error.
+type Uploader interface {
+	Upload(ctx context.Context, name string, r io.Reader) error
+}
+
+// Checkpoint is the progress of an export.
+type Checkpoint struct {
+	// Part is the number of the next part to write.
+	Part int `json:"part"`
+
+	// Cursor is the AccountTransactions cursor the next part starts at.
+	Cursor string `json:"cursor"`
+
+	// Count is the number of transactions in the parts written so far.
+	Count int64 `json:"count"`
+
+	// Done is true once every transaction has been exported.
+	Done bool `json:"done"`
+}
+
+// CheckpointStore saves the progress of an export. Load returns a zero
+// Checkpoint if none has been saved.
+type CheckpointStore interface {
+	Load(ctx context.Context) (Checkpoint, error)
+	Save(ctx context.Context, cp Checkpoint) error
+}
+
+// Exporter exports the transactions of one account.
+type Exporter struct {
+	Client    client.Client
+	AccountID int64
+	Uploader  Uploader
+	Store     CheckpointStore
+
+	// Prefix is prepended to the name of each part, for example
+	// "archive/account-1/". Parts are named Prefix followed by the part
+	// number and ".ndjson.gz".
+	Prefix string
+
+	// PartSize is the minimum number of transactions per part; parts end at
+	// the first page boundary after it. It defaults to 100000.
+	PartSize int
+
+	// Options are passed to each AccountTransactions call, for example
+	// Limit to set the page size. Next must not be given.
+	Options []client.Option
+}
+
+const defaultPartSize = 100000
+
+// Run exports the transactions not yet exported and returns the final
+// checkpoint.
+func (e *Exporter) Run(ctx context.Context) (Checkpoint, error) {
+	cp, err := e.Store.Load(ctx)
+	if err != nil {
+		return cp, err
+	}
+	for !cp.Done {
+		next, n, err := e.part(ctx, cp)
+		if err != nil {
+			return cp, fmt.Errorf("export part %d: %w", cp.Part, err)
+		}
+		cp.Part++
+		cp.Cursor = next
+		cp.Count += int64(n)
+		cp.Done = next == ""
+		if err := e.Store.Save(ctx, cp); err != nil {
+			return cp, err
+		}
+	}
+	return cp, nil
+}
+
+// part writes the part starting at cp, returning the cursor after it and the
+// number of transactions written.
+func (e *Exporter) part(ctx context.Context, cp Checkpoint) (string, int,
+	error) {
+
+	size := e.PartSize
+	if size <= 0 {
+		size = defaultPartSize
+	}
+	name := fmt.Sprintf("%s%06d.ndjson.gz", e.Prefix, cp.Part)
+
+	pr, pw := io.Pipe()
+	uploaded := make(chan error, 1)
+	go func() {
+		err := e.Uploader.Upload(ctx, name, pr)
+		pr.CloseWithError(err)
+		uploaded <- err
+	}()
+
+	next, n, err := e.write(ctx, pw, cp.Cursor, size)
+	if err != nil {
+		pw.CloseWithError(err)
+		<-uploaded
+		return "", 0, err
+	}
+	pw.Close()
+	if err := <-uploaded; err != nil {
+		return "", 0, err
+	}
+	return next, n, nil
+}
+
+// write fetches pages starting at cursor and writes them to w until at least
+// size transactions are written or there are no more pages.
+func (e *Exporter) write(ctx context.Context, w io.Writer, cursor string,
+	size int) (string, int, error) {
+
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+	n := 0
+	for {
+		options := e.Options
+		if cursor != "" {
+			options = append(options[:len(options):len(options)],
+				client.Next(cursor))
+		}
+		next, txns, err := e.Client.AccountTransactionsContext(ctx,
+			e.AccountID, options...)
+		if err != nil {
+			return "", 0, err
+		}
+		for _, tx := range txns {
+			if err := enc.Encode(tx); err != nil {
+				return "", 0, err
+			}
+		}
+		n += len(txns)
+		cursor = next
+		if next == "" || n >= size {
+			break
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return "", 0, err
+	}
+	return cursor, n, nil
+}
+
+// FileStore is a CheckpointStore saving the checkpoint as JSON in a file. The
+// file is replaced atomically.
+type FileStore struct {
+	Path string
+}
+
+// Load reads the checkpoint from the file.
+func (f FileStore) Load(ctx context.Context) (Checkpoint, error) {
+	var cp Checkpoint
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return cp, err
+	}
+	err = json.Unmarshal(data, &cp)
+	return cp, err
+}
+
+// Save writes cp to the file.
+func (f FileStore) Save(ctx context.Context, cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
@@ -0,0 +1,123 @@
+package export_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/export"
+)
+
+type memUploader struct {
+	parts map[string][]byte
+	fail  string
+}
+
+func (u *memUploader) Upload(ctx context.Context, name string,
+	r io.Reader) error {
+	if name == u.fail {
+		u.fail = ""
+		return errors.New("upload failed")
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	u.parts[name] = data
+	return nil
+}
+
+func TestExporter(t *testing.T) {
+
+	// Five pages of two transactions, the cursor being the page number.
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			page, _ := strconv.Atoi(r.URL.Query().Get("next"))
+			next := ""
+			if page < 4 {
+				next = strconv.Itoa(page + 1)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"type": "transactions", "next": %q,
+				"payload": [{"id": %d}, {"id": %d}]}`, next, page*2+1,
+				page*2+2)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	uploader := &memUploader{
+		parts: map[string][]byte{},
+		fail:  "acc/000001.ndjson.gz",
+	}
+	store := export.FileStore{Path: filepath.Join(t.TempDir(), "cp.json")}
+	e := &export.Exporter{
+		Client:    cl,
+		AccountID: 1,
+		Uploader:  uploader,
+		Store:     store,
+		Prefix:    "acc/",
+		PartSize:  3,
+	}
+
+	// The second part fails and is written again when resumed.
+	if _, err := e.Run(context.Background()); err == nil {
+		t.Fatal("expected upload error")
+	}
+	cp, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Part != 1 || cp.Cursor != "2" || cp.Count != 4 || cp.Done {
+		t.Fatalf("unexpected checkpoint %+v", cp)
+	}
+
+	cp, err = e.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cp.Done || cp.Count != 10 || cp.Part != 3 {
+		t.Fatalf("unexpected final checkpoint %+v", cp)
+	}
+
+	var ids []int64
+	for part := 0; part < 3; part++ {
+		data := uploader.parts[fmt.Sprintf("acc/%06d.ndjson.gz", part)]
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(part, err)
+		}
+		s := bufio.NewScanner(gz)
+		for s.Scan() {
+			var tx client.Transaction
+			if err := json.Unmarshal(s.Bytes(), &tx); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, tx.ID)
+		}
+	}
+	if len(ids) != 10 || ids[0] != 1 || ids[9] != 10 {
+		t.Fatalf("expected every transaction once %v", ids)
+	}
+
+	// A finished export does nothing.
+	uploader.parts = map[string][]byte{}
+	if _, err := e.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(uploader.parts) != 0 {
+		t.Fatal("expected no parts to be written")
+	}
+}
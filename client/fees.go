@@ -0,0 +1,24 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// FeesByTarget returns the fee estimate for each of blocks, or for targets of
+// 1, 3 and 6 blocks if none are given, keyed by confirmation target.
+func FeesByTarget(ctx context.Context, c Client, blocks ...int) (map[int]Fee,
+	error) {
+	if len(blocks) == 0 {
+		blocks = []int{1, 3, 6}
+	}
+	fees := make(map[int]Fee, len(blocks))
+	for _, target := range blocks {
+		fee, err := c.FeeForTargetContext(ctx, target)
+		if err != nil {
+			return nil, fmt.Errorf("fee for %d blocks: %w", target, err)
+		}
+		fees[target] = fee
+	}
+	return fees, nil
+}
@@ -0,0 +1,43 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestFeesByTarget(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			target := r.URL.Query().Get("confirmationTarget")
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"type": "fees", "payload": [
+				{"feePerByte": 1, "blockHeight": 99},
+				{"feePerByte": %s0, "blockHeight": 100}]}`, target)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	fees, err := client.FeesByTarget(context.Background(), cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, target := range []int{1, 3, 6} {
+		fee := fees[target]
+		if fee.FeePerByte != int64(target*10) || fee.BlockHeight != 100 ||
+			fee.ConfirmationTarget != target {
+			t.Fatalf("unexpected fee for %d blocks %+v", target, fee)
+		}
+	}
+
+	if _, err := cl.FeeForTarget(0); err == nil {
+		t.Fatal("expected invalid target to be refused")
+	}
+}
@@ -0,0 +1,17 @@
+package client
+
+// FetchOptions sets the options of the fetch requests made by the browser when
+// the client is built for js/wasm, for example by an admin page reaching
+// RTWire through a proxy on another origin. Empty fields keep the browser's
+// defaults.
+type FetchOptions struct {
+	// Mode is the request mode, such as "cors", "no-cors" or "same-origin".
+	Mode string
+
+	// Credentials is when cookies are sent: "omit", "same-origin" or
+	// "include".
+	Credentials string
+
+	// Redirect is how redirects are handled: "follow", "error" or "manual".
+	Redirect string
+}
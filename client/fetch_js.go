@@ -0,0 +1,22 @@
+//go:build js && wasm
+
+package client
+
+// WithFetch sets the options of the fetch requests used to reach RTWire. It
+// only has an effect when built for js/wasm, where net/http sends requests
+// with the browser's fetch API.
+func WithFetch(opts FetchOptions) ClientOption {
+	return func(c *client) {
+		// net/http passes headers prefixed with js.fetch: to fetch as options
+		// rather than sending them.
+		for key, value := range map[string]string{
+			"js.fetch:mode":        opts.Mode,
+			"js.fetch:credentials": opts.Credentials,
+			"js.fetch:redirect":    opts.Redirect,
+		} {
+			if value != "" {
+				WithBaseHeader(key, value)(c)
+			}
+		}
+	}
+}
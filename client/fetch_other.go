@@ -0,0 +1,10 @@
+//go:build !(js && wasm)
+
+package client
+
+// WithFetch sets the options of the fetch requests used to reach RTWire. It
+// only has an effect when built for js/wasm, where net/http sends requests
+// with the browser's fetch API.
+func WithFetch(opts FetchOptions) ClientOption {
+	return func(c *client) {}
+}
@@ -0,0 +1,52 @@
+package client_test
+
+import (
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestWithFetch(t *testing.T) {
+
+	var header http.Header
+	rt := client.RoundTripperFunc(func(req *http.Request) (*http.Response,
+		error) {
+		header = req.Header
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body: io.NopCloser(strings.NewReader(
+				`{"type": "accounts", "payload": [{"id": 1}]}`)),
+			Request: req,
+		}, nil
+	})
+
+	cl := client.New(http.DefaultClient, "https://proxy.example/v1/mainnet",
+		"user", "pass", client.WithTransport(rt),
+		client.WithFetch(client.FetchOptions{
+			Mode:        "cors",
+			Credentials: "include",
+		}))
+	if _, err := cl.Account(1); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"js.fetch:mode":        "cors",
+		"js.fetch:credentials": "include",
+		"js.fetch:redirect":    "",
+	}
+	for key, value := range want {
+		if runtime.GOOS != "js" {
+			// Fetch options must never be sent as headers.
+			value = ""
+		}
+		if got := header.Get(key); got != value {
+			t.Fatalf("expected %s %q got %q", key, value, got)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package fx converts bitcoin amounts to fiat currencies, such as USD or EUR,
+// at current or historical rates from a RateProvider.
+//
+// Coinbase, CoinGecko and Kraken implement RateProvider over the public APIs
+// of those exchanges:
+//
+//	rates := fx.NewCoinbase(http.DefaultClient, fx.CoinbaseURL)
+//	values, err := fx.Transactions(ctx, rates, "USD", txns)
+//
+// Historical rates are daily, so transactions are valued at the rate of the
+// day, in UTC, they were created.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// ErrNoRate is returned from a RateProvider that has no rate for the
+// currency or time requested.
+var ErrNoRate = errors.New("fx: no rate")
+
+// RateProvider returns the price of one bitcoin in currency, an ISO 4217 code
+// such as "USD", at time t. The current price is returned if t is zero.
+type RateProvider interface {
+	Rate(ctx context.Context, currency string, t time.Time) (float64, error)
+}
+
+// RateProviderFunc adapts a function to the RateProvider interface.
+type RateProviderFunc func(ctx context.Context, currency string,
+	t time.Time) (float64, error)
+
+// Rate calls f(ctx, currency, t).
+func (f RateProviderFunc) Rate(ctx context.Context, currency string,
+	t time.Time) (float64, error) {
+	return f(ctx, currency, t)
+}
+
+// Fiat is an amount of a fiat currency in its minor unit, for example cents
+// of USD. Currencies without a minor unit, such as JPY, are in whole units.
+type Fiat struct {
+	Currency string
+	Minor    int64
+}
+
+// zeroDecimal lists the common currencies that have no minor unit.
+var zeroDecimal = map[string]bool{"JPY": true, "KRW": true, "CLP": true,
+	"ISK": true, "VND": true}
+
+// decimals returns the number of decimals of the minor unit of currency.
+func decimals(currency string) int {
+	if zeroDecimal[currency] {
+		return 0
+	}
+	return 2
+}
+
+// String formats f in major units, for example "12.34 USD".
+func (f Fiat) String() string {
+	d := decimals(f.Currency)
+	if d == 0 {
+		return fmt.Sprintf("%d %s", f.Minor, f.Currency)
+	}
+	sign, minor := "", f.Minor
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	return fmt.Sprintf("%s%d.%02d %s", sign, minor/100, minor%100,
+		f.Currency)
+}
+
+// FromRate converts a to currency at price per bitcoin, rounded to the
+// nearest minor unit.
+func FromRate(a client.Amount, currency string, price float64) Fiat {
+	currency = strings.ToUpper(currency)
+	scale := math.Pow10(decimals(currency))
+	return Fiat{
+		Currency: currency,
+		Minor:    int64(math.Round(a.ToBTC() * price * scale)),
+	}
+}
+
+// Convert converts a to currency at the rate of time t, or the current rate
+// if t is zero.
+func Convert(ctx context.Context, p RateProvider, currency string,
+	a client.Amount, t time.Time) (Fiat, error) {
+	currency = strings.ToUpper(currency)
+	price, err := p.Rate(ctx, currency, t)
+	if err != nil {
+		return Fiat{}, fmt.Errorf("%s rate at %v: %w", currency, t, err)
+	}
+	return FromRate(a, currency, price), nil
+}
+
+// Transactions converts the value of each transaction in txns to currency at
+// the rate of the day it was created, fetching each day's rate once. The
+// values are returned in the order of txns.
+func Transactions(ctx context.Context, p RateProvider, currency string,
+	txns []client.Transaction) ([]Fiat, error) {
+	rate := Daily(ctx, p, currency)
+	values := make([]Fiat, len(txns))
+	for i, tx := range txns {
+		price, err := rate(tx.Created)
+		if err != nil {
+			return nil, fmt.Errorf("tx %d: %w", tx.ID, err)
+		}
+		values[i] = FromRate(tx.Value, currency, price)
+	}
+	return values, nil
+}
+
+// Daily returns a function giving the rate of currency on the day, in UTC,
+// of each time it is called with, such as an accounting.Rate. Each day's rate
+// is fetched from p once. The function is not safe for concurrent use.
+func Daily(ctx context.Context, p RateProvider,
+	currency string) func(t time.Time) (float64, error) {
+	currency = strings.ToUpper(currency)
+	rates := map[time.Time]float64{}
+	return func(t time.Time) (float64, error) {
+		day := t.UTC().Truncate(24 * time.Hour)
+		if price, ok := rates[day]; ok {
+			return price, nil
+		}
+		price, err := p.Rate(ctx, currency, day)
+		if err != nil {
+			return 0, fmt.Errorf("%s rate on %s: %w", currency,
+				day.Format("2006-01-02"), err)
+		}
+		rates[day] = price
+		return price, nil
+	}
+}
@@ -0,0 +1,75 @@
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/fx"
+)
+
+func TestTransactions(t *testing.T) {
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	var requested []time.Time
+	rates := fx.RateProviderFunc(func(ctx context.Context, currency string,
+		t time.Time) (float64, error) {
+		if currency != "EUR" {
+			return 0, fx.ErrNoRate
+		}
+		requested = append(requested, t)
+		if t.Equal(day) {
+			return 50000, nil
+		}
+		return 60000, nil
+	})
+
+	txns := []client.Transaction{
+		{ID: 1, Value: 100000, Created: day.Add(2 * time.Hour)},
+		{ID: 2, Value: 1, Created: day.Add(20 * time.Hour)},
+		{ID: 3, Value: 100000, Created: day.Add(26 * time.Hour)},
+	}
+	values, err := fx.Transactions(context.Background(), rates, "eur", txns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []fx.Fiat{{"EUR", 5000}, {"EUR", 0}, {"EUR", 6000}}
+	for i := range want {
+		if values[i] != want[i] {
+			t.Errorf("tx %d: expected %v got %v", i+1, want[i], values[i])
+		}
+	}
+	if len(requested) != 2 {
+		t.Fatal("expected each day's rate fetched once", requested)
+	}
+	if s := values[0].String(); s != "50.00 EUR" {
+		t.Fatal("unexpected format", s)
+	}
+
+	if _, err := fx.Transactions(context.Background(), rates, "USD",
+		txns); !errors.Is(err, fx.ErrNoRate) {
+		t.Fatal("expected ErrNoRate", err)
+	}
+}
+
+func TestFromRate(t *testing.T) {
+
+	tests := []struct {
+		amount   client.Amount
+		currency string
+		price    float64
+		want     string
+	}{
+		{150000000, "usd", 64000.5, "96000.75 USD"},
+		{-2500, "USD", 40000, "-1.00 USD"},
+		{100000, "JPY", 9000000, "9000 JPY"},
+	}
+	for _, test := range tests {
+		got := fx.FromRate(test.amount, test.currency, test.price).String()
+		if got != test.want {
+			t.Errorf("%v: expected %s got %s", test.amount, test.want, got)
+		}
+	}
+}
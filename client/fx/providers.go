@@ -0,0 +1,232 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CoinbaseURL is the URL of Coinbase's public API.
+	CoinbaseURL = "https://api.coinbase.com"
+
+	// CoinGeckoURL is the URL of CoinGecko's public API.
+	CoinGeckoURL = "https://api.coingecko.com/api/v3"
+
+	// KrakenURL is the URL of Kraken's public API.
+	KrakenURL = "https://api.kraken.com"
+)
+
+// getJSON decodes the JSON response to a GET of urlStr into v, naming the
+// provider in errors.
+func getJSON(ctx context.Context, c *http.Client, provider, urlStr string,
+	v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%s: %w", provider, ErrNoRate)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%s: %s", provider, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", provider, err)
+	}
+	return nil
+}
+
+// Coinbase is a RateProvider backed by the Coinbase spot price API.
+// Historical rates are the spot price of the day.
+type Coinbase struct {
+	client *http.Client
+	url    string
+}
+
+// NewCoinbase creates a RateProvider that queries the Coinbase API at url.
+func NewCoinbase(c *http.Client, url string) *Coinbase {
+	return &Coinbase{client: c, url: url}
+}
+
+// Rate returns the spot price of bitcoin in currency on the day of t.
+func (cb *Coinbase) Rate(ctx context.Context, currency string,
+	t time.Time) (float64, error) {
+	urlStr := fmt.Sprintf("%s/v2/prices/BTC-%s/spot", cb.url,
+		url.PathEscape(strings.ToUpper(currency)))
+	if !t.IsZero() {
+		urlStr += "?date=" + t.UTC().Format("2006-01-02")
+	}
+	var resp struct {
+		Data struct {
+			Amount string `json:"amount"`
+		} `json:"data"`
+	}
+	if err := getJSON(ctx, cb.client, "coinbase", urlStr,
+		&resp); err != nil {
+		return 0, err
+	}
+	price, err := strconv.ParseFloat(resp.Data.Amount, 64)
+	if err != nil || price <= 0 {
+		return 0, fmt.Errorf("coinbase: %w for %s", ErrNoRate, currency)
+	}
+	return price, nil
+}
+
+// CoinGecko is a RateProvider backed by the CoinGecko API. Historical rates
+// are the price at 00:00 UTC of the day.
+type CoinGecko struct {
+	client *http.Client
+	url    string
+}
+
+// NewCoinGecko creates a RateProvider that queries the CoinGecko API at url.
+func NewCoinGecko(c *http.Client, url string) *CoinGecko {
+	return &CoinGecko{client: c, url: url}
+}
+
+// Rate returns the price of bitcoin in currency on the day of t.
+func (cg *CoinGecko) Rate(ctx context.Context, currency string,
+	t time.Time) (float64, error) {
+	currency = strings.ToLower(currency)
+	var prices map[string]float64
+	if t.IsZero() {
+		query := url.Values{"ids": {"bitcoin"}, "vs_currencies": {currency}}
+		var resp map[string]map[string]float64
+		if err := getJSON(ctx, cg.client, "coingecko",
+			cg.url+"/simple/price?"+query.Encode(), &resp); err != nil {
+			return 0, err
+		}
+		prices = resp["bitcoin"]
+	} else {
+		query := url.Values{"date": {t.UTC().Format("02-01-2006")},
+			"localization": {"false"}}
+		var resp struct {
+			MarketData struct {
+				CurrentPrice map[string]float64 `json:"current_price"`
+			} `json:"market_data"`
+		}
+		if err := getJSON(ctx, cg.client, "coingecko",
+			cg.url+"/coins/bitcoin/history?"+query.Encode(),
+			&resp); err != nil {
+			return 0, err
+		}
+		prices = resp.MarketData.CurrentPrice
+	}
+	price, ok := prices[currency]
+	if !ok || price <= 0 {
+		return 0, fmt.Errorf("coingecko: %w for %s", ErrNoRate, currency)
+	}
+	return price, nil
+}
+
+// Kraken is a RateProvider backed by the Kraken public market data API.
+// Historical rates are the closing price of the daily candle containing t;
+// Kraken only serves the last 720 days of them.
+type Kraken struct {
+	client *http.Client
+	url    string
+}
+
+// NewKraken creates a RateProvider that queries the Kraken API at url.
+func NewKraken(c *http.Client, url string) *Kraken {
+	return &Kraken{client: c, url: url}
+}
+
+// krakenResponse is the envelope of Kraken responses. Result holds a single
+// entry for the requested pair, under Kraken's own name for it.
+type krakenResponse struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// pair returns the result of the request for the pair, ignoring the cursor
+// OHLC responses include.
+func (r krakenResponse) pair(currency string) (json.RawMessage, error) {
+	if len(r.Error) > 0 {
+		return nil, fmt.Errorf("kraken: %s", strings.Join(r.Error, ", "))
+	}
+	for name, result := range r.Result {
+		if name != "last" {
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("kraken: %w for %s", ErrNoRate, currency)
+}
+
+// Rate returns the price of bitcoin in currency at t.
+func (k *Kraken) Rate(ctx context.Context, currency string,
+	t time.Time) (float64, error) {
+	currency = strings.ToUpper(currency)
+	query := url.Values{"pair": {"XBT" + currency}}
+	path := "/0/public/Ticker"
+	if !t.IsZero() {
+		day := int64(24 * time.Hour / time.Second)
+		query.Set("interval", "1440")
+		query.Set("since", strconv.FormatInt(t.Unix()-day, 10))
+		path = "/0/public/OHLC"
+	}
+
+	var resp krakenResponse
+	if err := getJSON(ctx, k.client, "kraken",
+		k.url+path+"?"+query.Encode(), &resp); err != nil {
+		return 0, err
+	}
+	result, err := resp.pair(currency)
+	if err != nil {
+		return 0, err
+	}
+
+	var price string
+	if t.IsZero() {
+		var ticker struct {
+			// C is the price and volume of the last trade.
+			C []string `json:"c"`
+		}
+		if err := json.Unmarshal(result, &ticker); err != nil {
+			return 0, fmt.Errorf("kraken: %w", err)
+		}
+		if len(ticker.C) > 0 {
+			price = ticker.C[0]
+		}
+	} else {
+		// Each candle is its start time, open, high, low, close, volume
+		// weighted average price, volume and count.
+		var candles [][]interface{}
+		if err := json.Unmarshal(result, &candles); err != nil {
+			return 0, fmt.Errorf("kraken: %w", err)
+		}
+		for _, c := range candles {
+			if len(c) < 5 {
+				continue
+			}
+			start, ok := c[0].(float64)
+			if !ok {
+				continue
+			}
+			if begin := time.Unix(int64(start), 0); !t.Before(begin) &&
+				t.Before(begin.Add(24*time.Hour)) {
+				price, _ = c[4].(string)
+				break
+			}
+		}
+	}
+
+	p, err := strconv.ParseFloat(price, 64)
+	if err != nil || p <= 0 {
+		return 0, fmt.Errorf("kraken: %w for %s at %v", ErrNoRate, currency,
+			t)
+	}
+	return p, nil
+}
@@ -0,0 +1,76 @@
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client/fx"
+)
+
+func TestProviders(t *testing.T) {
+
+	at := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	candle := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Unix()
+	routes := map[string]string{
+		"/v2/prices/BTC-USD/spot": `{"data": {"amount": "61000.10",
+			"currency": "USD"}}`,
+		"/v2/prices/BTC-USD/spot?date=2024-03-01": `{"data": {
+			"amount": "62000.20", "currency": "USD"}}`,
+		"/simple/price?ids=bitcoin&vs_currencies=usd": `{"bitcoin":
+			{"usd": 61000.1}}`,
+		"/coins/bitcoin/history?date=01-03-2024&localization=false": `{
+			"market_data": {"current_price": {"usd": 62000.2}}}`,
+		"/0/public/Ticker?pair=XBTUSD": `{"error": [], "result": {
+			"XXBTZUSD": {"c": ["61000.1", "0.01"]}}}`,
+		fmt.Sprintf("/0/public/OHLC?interval=1440&pair=XBTUSD&since=%d",
+			at.Unix()-86400): fmt.Sprintf(`{"error": [], "result": {
+			"XXBTZUSD": [
+				[%d, "1", "1", "1", "1", "1", "1", 1],
+				[%d, "61500", "63000", "61000", "62000.2", "62000",
+					"100", 1000]],
+			"last": %d}}`, candle-86400, candle, candle),
+		"/0/public/Ticker?pair=XBTXYZ": `{"error":
+			["EQuery:Unknown asset pair"]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, ok := routes[r.URL.RequestURI()]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, body)
+		}))
+	defer server.Close()
+
+	providers := map[string]fx.RateProvider{
+		"coinbase":  fx.NewCoinbase(http.DefaultClient, server.URL),
+		"coingecko": fx.NewCoinGecko(http.DefaultClient, server.URL),
+		"kraken":    fx.NewKraken(http.DefaultClient, server.URL),
+	}
+	ctx := context.Background()
+	for name, p := range providers {
+		if price, err := p.Rate(ctx, "usd", time.Time{}); err != nil ||
+			price != 61000.1 {
+			t.Errorf("%s: unexpected current rate %v %v", name, price, err)
+		}
+		if price, err := p.Rate(ctx, "USD", at); err != nil ||
+			price != 62000.2 {
+			t.Errorf("%s: unexpected rate %v %v", name, price, err)
+		}
+		if _, err := p.Rate(ctx, "XYZ", time.Time{}); err == nil {
+			t.Errorf("%s: expected an error for an unknown currency", name)
+		}
+	}
+
+	// A pair Kraken reports as unknown is an error, not a missing rate.
+	_, err := providers["kraken"].Rate(ctx, "XYZ", time.Time{})
+	if err == nil || errors.Is(err, fx.ErrNoRate) {
+		t.Fatal("expected the Kraken error", err)
+	}
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// HookPolicy describes which hook URLs are acceptable. Every hook must use
+// https and name its host rather than using an IP address. If AllowedDomains
+// is not empty the host must also be one of the domains or a subdomain of one.
+type HookPolicy struct {
+	AllowedDomains []string
+}
+
+// HookViolation is a registered hook that breaks a HookPolicy. AccountID is
+// zero for hooks that are not registered to an account.
+type HookViolation struct {
+	AccountID int64
+	URL       string
+	Reason    string
+}
+
+// HookAuditError is returned from AuditHooks when registered hooks break the
+// policy.
+type HookAuditError struct {
+	Violations []HookViolation
+}
+
+func (e *HookAuditError) Error() string {
+	lines := make([]string, 0, len(e.Violations)+1)
+	lines = append(lines, fmt.Sprintf("%d hooks break policy",
+		len(e.Violations)))
+	for _, v := range e.Violations {
+		if v.AccountID != 0 {
+			lines = append(lines, fmt.Sprintf("  account %d: %s: %s",
+				v.AccountID, v.URL, v.Reason))
+		} else {
+			lines = append(lines, fmt.Sprintf("  %s: %s", v.URL, v.Reason))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Check returns the reason hookURL breaks the policy, or an empty string if it
+// is acceptable.
+func (p HookPolicy) Check(hookURL string) string {
+	u, err := url.Parse(hookURL)
+	if err != nil {
+		return "invalid URL"
+	}
+	if u.Scheme != "https" {
+		return "not https"
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return "no host"
+	}
+	if net.ParseIP(host) != nil {
+		return "IP address host"
+	}
+	if len(p.AllowedDomains) == 0 {
+		return ""
+	}
+	for _, domain := range p.AllowedDomains {
+		domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return ""
+		}
+	}
+	return "domain not allowed"
+}
+
+// AuditHooks checks the hooks registered with RTWire, and those registered to
+// each of accountIDs, against policy. It returns a *HookAuditError listing
+// every violation so that CI or service startup can fail when a stray or
+// insecure hook is found.
+func AuditHooks(ctx context.Context, c Client, policy HookPolicy,
+	accountIDs ...int64) error {
+
+	var violations []HookViolation
+	check := func(accountID int64, hooks []Hook) {
+		for _, hook := range hooks {
+			if reason := policy.Check(hook.URL); reason != "" {
+				violations = append(violations, HookViolation{
+					AccountID: accountID,
+					URL:       hook.URL,
+					Reason:    reason,
+				})
+			}
+		}
+	}
+
+	hooks, err := c.HooksContext(ctx)
+	if err != nil {
+		return err
+	}
+	check(0, hooks)
+
+	for _, id := range accountIDs {
+		hooks, err := c.AccountHooksContext(ctx, id)
+		if err != nil {
+			return err
+		}
+		check(id, hooks)
+	}
+
+	if len(violations) > 0 {
+		return &HookAuditError{Violations: violations}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestHookPolicyCheck(t *testing.T) {
+
+	policy := client.HookPolicy{AllowedDomains: []string{"example.com"}}
+	tests := []struct {
+		url    string
+		reason string
+	}{
+		{"https://example.com/hook", ""},
+		{"https://hooks.Example.com./hook", ""},
+		{"http://example.com/hook", "not https"},
+		{"https://10.0.0.1/hook", "IP address host"},
+		{"https://[::1]:8443/hook", "IP address host"},
+		{"https://notexample.com/hook", "domain not allowed"},
+		{"https:///hook", "no host"},
+		{"://", "invalid URL"},
+	}
+	for _, test := range tests {
+		if reason := policy.Check(test.url); reason != test.reason {
+			t.Errorf("%s: expected %q got %q", test.url, test.reason, reason)
+		}
+	}
+
+	if reason := (client.HookPolicy{}).Check("https://any.org/"); reason != "" {
+		t.Fatal("expected any domain to be allowed", reason)
+	}
+}
+
+func TestAuditHooks(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"GET /hooks/": `{"type": "hooks", "payload": [
+			{"url": "https://example.com/hook"},
+			{"url": "http://example.com/hook"}]}`,
+		"GET /accounts/2/hooks/": `{"type": "hooks", "payload": [
+			{"url": "https://stray.org/hook"}]}`,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	policy := client.HookPolicy{AllowedDomains: []string{"example.com"}}
+	err := client.AuditHooks(context.Background(), cl, policy, 2)
+
+	var auditErr *client.HookAuditError
+	if !errors.As(err, &auditErr) {
+		t.Fatal("expected audit error", err)
+	}
+	expected := []client.HookViolation{
+		{URL: "http://example.com/hook", Reason: "not https"},
+		{AccountID: 2, URL: "https://stray.org/hook",
+			Reason: "domain not allowed"},
+	}
+	if len(auditErr.Violations) != len(expected) {
+		t.Fatal("unexpected violations", auditErr.Violations)
+	}
+	for i, v := range auditErr.Violations {
+		if v != expected[i] {
+			t.Fatal("unexpected violation", v)
+		}
+	}
+}
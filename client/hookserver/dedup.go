@@ -0,0 +1,68 @@
+package hookserver
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// Store records the events a Handler has handled, so that an event delivered
+// several times, as RTWire may do, is only passed to its callback once. A
+// Store shared by several processes, such as one backed by a database, makes
+// this hold across them.
+type Store interface {
+	// Add records key and reports whether it was not recorded already.
+	Add(ctx context.Context, key string) (bool, error)
+	// Remove forgets key, so that the event can be handled again.
+	Remove(ctx context.Context, key string) error
+}
+
+// Key returns the key an event is recorded under in a Store. The pending and
+// released events of a credit have different keys, as each is passed to its
+// own callback.
+func Key(e client.TransactionEvent) string {
+	return strconv.FormatInt(e.ID, 10) + "/" + e.Status
+}
+
+// MemoryStore is a Store that keeps keys in memory for a fixed duration. It
+// only removes duplicates delivered to the same process.
+type MemoryStore struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	expires map[string]time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore keeping keys for ttl, which
+// should cover the time RTWire keeps retrying a delivery.
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, expires: map[string]time.Time{}}
+}
+
+// Add records key and drops expired keys.
+func (m *MemoryStore) Add(ctx context.Context, key string) (bool, error) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, expires := range m.expires {
+		if now.After(expires) {
+			delete(m.expires, k)
+		}
+	}
+	if _, ok := m.expires[key]; ok {
+		return false, nil
+	}
+	m.expires[key] = now.Add(m.ttl)
+	return true, nil
+}
+
+// Remove forgets key.
+func (m *MemoryStore) Remove(ctx context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.expires, key)
+	m.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,80 @@
+package hookserver_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/hookserver"
+)
+
+func TestHandlerStore(t *testing.T) {
+
+	var credits []string
+	fail := true
+	h := &hookserver.Handler{
+		Store: hookserver.NewMemoryStore(time.Hour),
+		OnPending: func(ctx context.Context, e client.TransactionEvent) error {
+			credits = append(credits, hookserver.Key(e))
+			return nil
+		},
+		OnCredit: func(ctx context.Context, e client.TransactionEvent) error {
+			if fail {
+				fail = false
+				return errors.New("failed")
+			}
+			credits = append(credits, hookserver.Key(e))
+			return nil
+		},
+	}
+	deliver := func() int {
+		r := httptest.NewRequest("POST", "/hook",
+			strings.NewReader(delivery))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// The failed credit is handled again, but the pending one is not.
+	if code := deliver(); code != http.StatusInternalServerError {
+		t.Fatal("unexpected status", code)
+	}
+	for i := 0; i < 2; i++ {
+		if code := deliver(); code != http.StatusOK {
+			t.Fatal("unexpected status", code)
+		}
+	}
+	if strings.Join(credits, ",") != "1/pending,1/" {
+		t.Fatal("unexpected credits", credits)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+
+	ctx := context.Background()
+	store := hookserver.NewMemoryStore(50 * time.Millisecond)
+
+	if added, err := store.Add(ctx, "1/"); !added || err != nil {
+		t.Fatal("expected key added", err)
+	}
+	if added, _ := store.Add(ctx, "1/"); added {
+		t.Fatal("expected duplicate key")
+	}
+	if err := store.Remove(ctx, "1/"); err != nil {
+		t.Fatal(err)
+	}
+	if added, _ := store.Add(ctx, "1/"); !added {
+		t.Fatal("expected removed key added")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if added, _ := store.Add(ctx, "1/"); !added {
+		t.Fatal("expected expired key added")
+	}
+}
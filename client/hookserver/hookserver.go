@@ -0,0 +1,144 @@
+// Package hookserver receives RTWire hook deliveries, checks them and passes
+// their transaction events to typed callbacks, so that a service does not
+// have to wrap client.Unmarshal itself:
+//
+//	h := &hookserver.Handler{
+//		Secret: secret,
+//		OnCredit: func(ctx context.Context, e client.TransactionEvent) error {
+//			return credit(ctx, e.ToAccountID, e.Value)
+//		},
+//	}
+//	http.Handle("/hook", h)
+//
+// A delivery is acknowledged with a 200 status once every callback has
+// returned nil. A delivery that is not a signed JSON POST, or cannot be
+// decoded, is refused with a 4xx status. If a callback fails a 500 status is
+// returned so that RTWire delivers the hook again.
+//
+// RTWire may deliver the same event several times. Set Store, for example to
+// a MemoryStore, to call each callback once for each event.
+package hookserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/rtwire/go/client"
+)
+
+// Func handles a transaction event from a hook delivery. An error causes
+// RTWire to deliver the hook, and so every event in it, again.
+type Func func(ctx context.Context, event client.TransactionEvent) error
+
+// Handler is an http.Handler for RTWire hook deliveries. Events without a
+// callback, and kinds of event other than transactions, are acknowledged and
+// dropped. Its fields must not be changed once it is serving.
+type Handler struct {
+	// Secret is the secret the hook was created with using client.HookSecret.
+	// Deliveries without a valid signature are refused. If Secret is empty
+	// signatures are not checked.
+	Secret string
+
+	// OnCredit is called for credits released to the account.
+	OnCredit Func
+	// OnPending is called for credits RTWire has seen but not released to
+	// the account yet. The same credit is later passed to OnCredit.
+	OnPending Func
+	// OnDebit is called for debits from the account.
+	OnDebit Func
+
+	// Store, if set, records the events passed to a callback so that
+	// duplicate deliveries of an event are acknowledged without calling it
+	// again. An event whose callback fails is removed, so that it is handled
+	// when RTWire delivers it again.
+	Store Store
+
+	// OnError, if set, is called with the error of each delivery that is not
+	// acknowledged or cannot be read, for example to log it.
+	OnError func(r *http.Request, err error)
+}
+
+// ServeHTTP checks the delivery in r and calls the callback for each of its
+// transaction events in order, stopping at the first error.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		h.fail(w, r, errors.New("method not allowed"),
+			http.StatusMethodNotAllowed)
+		return
+	}
+	if !client.IsJSON(r) {
+		h.fail(w, r, errors.New("incorrect content type"),
+			http.StatusUnsupportedMediaType)
+		return
+	}
+	if h.Secret != "" {
+		if err := client.VerifyHookSignature(r, h.Secret); err != nil {
+			h.fail(w, r, err, http.StatusUnauthorized)
+			return
+		}
+	}
+
+	events, err := client.UnmarshalEvents(r)
+	if err != nil {
+		h.fail(w, r, err, http.StatusBadRequest)
+		return
+	}
+	for _, event := range events {
+		tx, ok := event.(client.TransactionEvent)
+		if !ok {
+			continue
+		}
+		fn := h.callback(tx)
+		if fn == nil {
+			continue
+		}
+		if err := h.handle(r.Context(), fn, tx); err != nil {
+			h.fail(w, r, err, http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handle calls fn with event unless Store holds it already.
+func (h *Handler) handle(ctx context.Context, fn Func,
+	event client.TransactionEvent) error {
+	if h.Store == nil {
+		return fn(ctx, event)
+	}
+	key := Key(event)
+	added, err := h.Store.Add(ctx, key)
+	if err != nil || !added {
+		return err
+	}
+	if err := fn(ctx, event); err != nil {
+		if rerr := h.Store.Remove(ctx, key); rerr != nil {
+			return errors.Join(err, rerr)
+		}
+		return err
+	}
+	return nil
+}
+
+// callback returns the callback for event, or nil if there is none.
+func (h *Handler) callback(event client.TransactionEvent) Func {
+	switch {
+	case event.Status == "pending":
+		return h.OnPending
+	case event.Type == "credit":
+		return h.OnCredit
+	case event.Type == "debit":
+		return h.OnDebit
+	}
+	return nil
+}
+
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, err error,
+	code int) {
+	if h.OnError != nil {
+		h.OnError(r, err)
+	}
+	http.Error(w, http.StatusText(code), code)
+}
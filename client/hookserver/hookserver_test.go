@@ -0,0 +1,132 @@
+package hookserver_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/hookserver"
+)
+
+const secret = "0123456789abcdef"
+
+const delivery = `{"type": "transactions", "payload": [
+	{"id": 1, "type": "credit", "status": "pending"},
+	{"id": 1, "type": "credit"},
+	{"id": 2, "type": "debit"},
+	{"id": 3, "type": "transfer"}]}`
+
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHandler(t *testing.T) {
+
+	var calls []string
+	record := func(name string) hookserver.Func {
+		return func(ctx context.Context, e client.TransactionEvent) error {
+			calls = append(calls, name)
+			return nil
+		}
+	}
+	h := &hookserver.Handler{
+		Secret:    secret,
+		OnCredit:  record("credit"),
+		OnPending: record("pending"),
+		OnDebit:   record("debit"),
+	}
+
+	r := httptest.NewRequest("POST", "/hook", strings.NewReader(delivery))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set(client.HookSignatureHeader, sign(delivery))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatal("unexpected status", w.Code)
+	}
+	if strings.Join(calls, ",") != "pending,credit,debit" {
+		t.Fatal("unexpected calls", calls)
+	}
+}
+
+func TestHandlerStatus(t *testing.T) {
+
+	failed := errors.New("failed")
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		sig         string
+		err         error
+		code        int
+	}{
+		{"ok", "POST", "application/json", delivery, sign(delivery), nil,
+			http.StatusOK},
+		{"method", "GET", "application/json", "", "", nil,
+			http.StatusMethodNotAllowed},
+		{"charset", "POST", "application/json; charset=utf-8", delivery,
+			sign(delivery), nil, http.StatusOK},
+		{"content type", "POST", "text/plain", delivery, sign(delivery), nil,
+			http.StatusUnsupportedMediaType},
+		{"signature", "POST", "application/json", delivery, sign("other"),
+			nil, http.StatusUnauthorized},
+		{"body", "POST", "application/json", "{", sign("{"), nil,
+			http.StatusBadRequest},
+		{"callback", "POST", "application/json", delivery, sign(delivery),
+			failed, http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var errs []error
+			h := &hookserver.Handler{
+				Secret: secret,
+				OnCredit: func(context.Context, client.TransactionEvent) error {
+					return test.err
+				},
+				OnError: func(r *http.Request, err error) {
+					errs = append(errs, err)
+				},
+			}
+			r := httptest.NewRequest(test.method, "/hook",
+				strings.NewReader(test.body))
+			r.Header.Set("Content-Type", test.contentType)
+			r.Header.Set(client.HookSignatureHeader, test.sig)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != test.code {
+				t.Fatalf("expected %d got %d", test.code, w.Code)
+			}
+			if (len(errs) > 0) != (test.code != http.StatusOK) {
+				t.Fatal("unexpected errors", errs)
+			}
+			if test.err != nil && !errors.Is(errs[0], test.err) {
+				t.Fatal("unexpected error", errs[0])
+			}
+		})
+	}
+}
+
+func TestHandlerUnsigned(t *testing.T) {
+
+	h := &hookserver.Handler{}
+	r := httptest.NewRequest("POST", "/hook", strings.NewReader(
+		`{"type": "accounts", "payload": [{"id": 1}]}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatal("unexpected status", w.Code)
+	}
+}
@@ -0,0 +1,110 @@
+package hookserver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+var (
+	// ErrStreamFull is returned from Stream.Send when the stream's buffer
+	// stays full for longer than its wait.
+	ErrStreamFull = errors.New("hookserver: stream full")
+	// ErrStreamClosed is returned from Stream.Send once the stream is closed.
+	ErrStreamClosed = errors.New("hookserver: stream closed")
+)
+
+// Stream passes hook events to a channel, so that they can be received in a
+// select loop rather than in the goroutine serving the delivery. Its Send
+// method is a Func for the callbacks of a Handler:
+//
+//	s := hookserver.NewStream(100, time.Second)
+//	http.Handle("/hook", &hookserver.Handler{
+//		OnCredit: s.Send,
+//		OnDebit:  s.Send,
+//	})
+//	for e := range s.Events() {
+//		...
+//	}
+//
+// An event is acknowledged to RTWire once it is in the buffer, not once it
+// is received, so events still buffered when the process stops are lost.
+type Stream struct {
+	events chan client.TransactionEvent
+	wait   time.Duration
+
+	mu       sync.RWMutex
+	done     chan struct{}
+	closeOne sync.Once
+}
+
+// NewStream returns a Stream buffering up to buffer events. When the buffer
+// is full Send waits up to wait for room, so that a slow receiver holds back
+// deliveries rather than dropping them, and then fails so that RTWire
+// delivers the event again later. A negative wait waits as long as the
+// delivery's request does.
+func NewStream(buffer int, wait time.Duration) *Stream {
+	if buffer < 0 {
+		buffer = 0
+	}
+	return &Stream{
+		events: make(chan client.TransactionEvent, buffer),
+		wait:   wait,
+		done:   make(chan struct{}),
+	}
+}
+
+// Events returns the channel events are received from. It is closed by
+// Close.
+func (s *Stream) Events() <-chan client.TransactionEvent {
+	return s.events
+}
+
+// Send adds event to the stream, returning ErrStreamFull if there is no room
+// for it within the stream's wait, or the error of ctx if it is done first.
+func (s *Stream) Send(ctx context.Context,
+	event client.TransactionEvent) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	select {
+	case <-s.done:
+		return ErrStreamClosed
+	default:
+	}
+	select {
+	case s.events <- event:
+		return nil
+	default:
+	}
+
+	var timeout <-chan time.Time
+	if s.wait >= 0 {
+		t := time.NewTimer(s.wait)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case s.events <- event:
+		return nil
+	case <-timeout:
+		return ErrStreamFull
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.done:
+		return ErrStreamClosed
+	}
+}
+
+// Close stops the stream accepting events and closes its channel once events
+// being sent have returned. Buffered events can still be received.
+func (s *Stream) Close() {
+	s.closeOne.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		close(s.events)
+		s.mu.Unlock()
+	})
+}
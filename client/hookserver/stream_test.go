@@ -0,0 +1,89 @@
+package hookserver_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/hookserver"
+)
+
+func TestStream(t *testing.T) {
+
+	s := hookserver.NewStream(2, 10*time.Millisecond)
+	h := &hookserver.Handler{
+		OnPending: s.Send,
+		OnCredit:  s.Send,
+		OnDebit:   s.Send,
+	}
+	deliver := func() int {
+		r := httptest.NewRequest("POST", "/hook",
+			strings.NewReader(delivery))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// The third event finds the buffer full, so the delivery is retried.
+	if code := deliver(); code != http.StatusInternalServerError {
+		t.Fatal("unexpected status", code)
+	}
+	for _, id := range []string{"1/pending", "1/"} {
+		if e := <-s.Events(); hookserver.Key(e) != id {
+			t.Fatalf("expected %s got %s", id, hookserver.Key(e))
+		}
+	}
+
+	received := make(chan []int64)
+	go func() {
+		var ids []int64
+		for e := range s.Events() {
+			ids = append(ids, e.ID)
+		}
+		received <- ids
+	}()
+	if code := deliver(); code != http.StatusOK {
+		t.Fatal("unexpected status", code)
+	}
+	s.Close()
+	if ids := <-received; len(ids) != 3 || ids[2] != 2 {
+		t.Fatal("unexpected events", ids)
+	}
+
+	err := s.Send(context.Background(), client.TransactionEvent{})
+	if !errors.Is(err, hookserver.ErrStreamClosed) {
+		t.Fatal("expected closed", err)
+	}
+}
+
+func TestStreamWait(t *testing.T) {
+
+	s := hookserver.NewStream(0, -1)
+	ctx, cancel := context.WithTimeout(context.Background(),
+		20*time.Millisecond)
+	defer cancel()
+
+	// A negative wait blocks until the context is done.
+	err := s.Send(ctx, client.TransactionEvent{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected deadline exceeded", err)
+	}
+
+	go func() { <-s.Events() }()
+	if err := s.Send(context.Background(),
+		client.TransactionEvent{}); err != nil {
+		t.Fatal(err)
+	}
+
+	s = hookserver.NewStream(0, 10*time.Millisecond)
+	err = s.Send(context.Background(), client.TransactionEvent{})
+	if !errors.Is(err, hookserver.ErrStreamFull) {
+		t.Fatal("expected full", err)
+	}
+}
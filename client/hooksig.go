@@ -0,0 +1,51 @@
+package client
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HookSignatureHeader is the header RTWire signs deliveries to a hook
+// created with HookSecret in. It holds "sha256=" followed by the hex encoded
+// HMAC-SHA256 of the request body keyed with the secret.
+const HookSignatureHeader = "X-RTWire-Signature"
+
+// ErrHookSignature is returned from VerifyHookSignature if a delivery is not
+// signed with the hook's secret.
+var ErrHookSignature = errors.New("invalid hook signature")
+
+// VerifyHookSignature checks that r, a hook delivery, is signed with secret,
+// the secret given to HookSecret, and returns ErrHookSignature otherwise. The
+// body is read and replaced so that r can still be passed to Unmarshal or
+// UnmarshalEvents.
+func VerifyHookSignature(r *http.Request, secret string) error {
+	sig, ok := strings.CutPrefix(r.Header.Get(HookSignatureHeader),
+		"sha256=")
+	if !ok {
+		return ErrHookSignature
+	}
+	want, err := hex.DecodeString(sig)
+	if err != nil {
+		return ErrHookSignature
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), want) {
+		return ErrHookSignature
+	}
+	return nil
+}
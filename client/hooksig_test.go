@@ -0,0 +1,91 @@
+package client_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestCreateHookOptions(t *testing.T) {
+
+	var got client.Hook
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+				t.Error(err)
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	want := client.Hook{
+		URL:           "https://example.com/hook",
+		Secret:        "0123456789abcdef",
+		Authorization: "Bearer token",
+		ContentType:   "application/json; charset=utf-8",
+	}
+	if err := cl.CreateHook(want.URL, client.HookSecret(want.Secret),
+		client.HookAuthorization(want.Authorization),
+		client.HookContentType(want.ContentType)); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("expected %+v got %+v", want, got)
+	}
+}
+
+func TestVerifyHookSignature(t *testing.T) {
+
+	const secret = "0123456789abcdef"
+	body := `{"type": "transactions", "payload": []}`
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	valid := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name string
+		sig  string
+		err  error
+	}{
+		{"valid", valid, nil},
+		{"missing", "", client.ErrHookSignature},
+		{"no prefix", strings.TrimPrefix(valid, "sha256="),
+			client.ErrHookSignature},
+		{"not hex", "sha256=zz", client.ErrHookSignature},
+		{"wrong", "sha256=" + strings.Repeat("00", 32),
+			client.ErrHookSignature},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/hook", strings.NewReader(body))
+			if test.sig != "" {
+				r.Header.Set(client.HookSignatureHeader, test.sig)
+			}
+			err := client.VerifyHookSignature(r, secret)
+			if !errors.Is(err, test.err) || (err == nil) != (test.err == nil) {
+				t.Fatalf("expected %v got %v", test.err, err)
+			}
+			if test.err != nil {
+				return
+			}
+			// The body can still be read after it is verified.
+			b, err := io.ReadAll(r.Body)
+			if err != nil || string(b) != body {
+				t.Fatalf("unexpected body %q %v", b, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// HookURLError is returned, without registering the hook, when a hook URL
+// given to CreateHook or CreateAccountHook is invalid, breaks the policy set
+// with WithHookPolicy or fails the preflight check enabled with
+// WithHookPreflight. RTWire accepts any URL so without these checks a typo
+// registers successfully and then silently never delivers.
+type HookURLError struct {
+	URL    string
+	Reason string
+
+	// Err is the error returned by the preflight request, if it failed.
+	Err error
+}
+
+func (e *HookURLError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("invalid hook URL %q: %s: %v", e.URL, e.Reason,
+			e.Err)
+	}
+	return fmt.Sprintf("invalid hook URL %q: %s", e.URL, e.Reason)
+}
+
+func (e *HookURLError) Unwrap() error {
+	return e.Err
+}
+
+// WithHookPolicy refuses to register hooks whose URL breaks policy, for
+// example because it does not use https.
+func WithHookPolicy(policy HookPolicy) ClientOption {
+	return func(c *client) {
+		c.hookPolicy = &policy
+	}
+}
+
+// WithHookPreflight sends a HEAD request to a hook URL before registering it
+// and refuses the hook if no response is received. Any response, including an
+// error status, shows the URL is reachable as receivers often only accept
+// POST requests.
+func WithHookPreflight() ClientOption {
+	return func(c *client) {
+		c.hookPreflight = true
+	}
+}
+
+// checkHookURL returns a *HookURLError if hookURL is not an absolute http or
+// https URL, breaks the hook policy or fails the preflight check.
+func (c *client) checkHookURL(ctx context.Context, hookURL string) error {
+	u, err := url.Parse(hookURL)
+	switch {
+	case err != nil:
+		return &HookURLError{URL: hookURL, Reason: "invalid URL"}
+	case u.Scheme != "http" && u.Scheme != "https":
+		return &HookURLError{URL: hookURL,
+			Reason: "not an http or https URL"}
+	case u.Host == "":
+		return &HookURLError{URL: hookURL, Reason: "no host"}
+	}
+
+	if c.hookPolicy != nil {
+		if reason := c.hookPolicy.Check(hookURL); reason != "" {
+			return &HookURLError{URL: hookURL, Reason: reason}
+		}
+	}
+
+	if c.hookPreflight {
+		req, err := http.NewRequestWithContext(ctx, "HEAD", hookURL, nil)
+		if err != nil {
+			return &HookURLError{URL: hookURL, Reason: "invalid URL", Err: err}
+		}
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return &HookURLError{URL: hookURL, Reason: "unreachable", Err: err}
+		}
+		resp.Body.Close()
+	}
+	return nil
+}
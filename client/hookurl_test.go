@@ -0,0 +1,76 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestHookURLValidation(t *testing.T) {
+
+	registered := 0
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" {
+				registered++
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	for _, hookURL := range []string{
+		"example.com/hook",
+		"ftp://example.com/hook",
+		"https:///hook",
+		"http://%zz",
+	} {
+		err := cl.CreateHook(hookURL)
+		var urlErr *client.HookURLError
+		if !errors.As(err, &urlErr) || urlErr.URL != hookURL {
+			t.Fatalf("expected hook URL error for %q: %v", hookURL, err)
+		}
+	}
+	if err := cl.CreateAccountHook(1, "example.com/hook"); err == nil {
+		t.Fatal("expected account hook to be refused")
+	}
+	if registered != 0 {
+		t.Fatal("expected no hooks to be registered", registered)
+	}
+
+	if err := cl.CreateHook(server.URL + "/hook"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Policy.
+	cl = client.New(http.DefaultClient, url, "user", "pass",
+		client.WithHookPolicy(client.HookPolicy{}))
+	err := cl.CreateHook(server.URL + "/hook")
+	var urlErr *client.HookURLError
+	if !errors.As(err, &urlErr) || urlErr.Reason != "not https" {
+		t.Fatal("expected policy error", err)
+	}
+
+	// Preflight.
+	cl = client.New(http.DefaultClient, url, "user", "pass",
+		client.WithHookPreflight())
+	if err := cl.CreateHook(server.URL + "/hook"); err != nil {
+		t.Fatal(err)
+	}
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	err = cl.CreateHook(unreachable.URL + "/hook")
+	if !errors.As(err, &urlErr) || urlErr.Reason != "unreachable" ||
+		urlErr.Err == nil {
+		t.Fatal("expected preflight error", err)
+	}
+	if registered != 2 {
+		t.Fatal("expected two hooks to be registered", registered)
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// IDGenerator creates transaction IDs on the client. The IDs must be positive
+// and never repeat, across every process sharing the RTWire credentials.
+type IDGenerator interface {
+	NewID() (int64, error)
+}
+
+// WithIDGenerator makes CreateTransactionIDs return IDs from g instead of
+// asking RTWire for them, so IDs can encode information such as a shard or a
+// timestamp used by downstream systems. It must only be used with RTWire
+// deployments that accept client-chosen transaction IDs; others reject the
+// transfers and debits made with them.
+func WithIDGenerator(g IDGenerator) ClientOption {
+	return func(c *client) {
+		c.idGenerator = g
+	}
+}
+
+const (
+	snowflakeShardBits    = 10
+	snowflakeSequenceBits = 12
+
+	// MaxSnowflakeShard is the largest shard a Snowflake can be created for.
+	MaxSnowflakeShard = 1<<snowflakeShardBits - 1
+)
+
+// Snowflake is an IDGenerator creating 63 bit IDs from the milliseconds since
+// an epoch, a shard number and a sequence number, in that order, so IDs sort
+// by creation time. Up to 4096 IDs can be created per millisecond per shard.
+type Snowflake struct {
+	epoch time.Time
+	shard int64
+
+	mu       sync.Mutex
+	last     int64
+	sequence int64
+}
+
+// NewSnowflake returns a Snowflake for shard, which must be unique to the
+// creating process, counting time from epoch. Epoch must be in the past and
+// must never change once IDs have been used.
+func NewSnowflake(shard int64, epoch time.Time) (*Snowflake, error) {
+	if shard < 0 || shard > MaxSnowflakeShard {
+		return nil, errors.New("snowflake shard out of range")
+	}
+	if epoch.After(time.Now()) {
+		return nil, errors.New("snowflake epoch in the future")
+	}
+	return &Snowflake{epoch: epoch, shard: shard}, nil
+}
+
+// NewID returns the next ID, waiting for the next millisecond if the sequence
+// of the current one is exhausted. It fails if the clock has moved backwards
+// since the previous ID.
+func (s *Snowflake) NewID() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Since(s.epoch).Milliseconds()
+	switch {
+	case now < s.last:
+		return 0, errors.New("snowflake clock moved backwards")
+	case now == s.last:
+		s.sequence = (s.sequence + 1) & (1<<snowflakeSequenceBits - 1)
+		if s.sequence == 0 {
+			for now <= s.last {
+				time.Sleep(time.Millisecond / 10)
+				now = time.Since(s.epoch).Milliseconds()
+			}
+		}
+	default:
+		s.sequence = 0
+	}
+	s.last = now
+
+	id := now<<(snowflakeShardBits+snowflakeSequenceBits) |
+		s.shard<<snowflakeSequenceBits | s.sequence
+	if id <= 0 {
+		return 0, errors.New("snowflake epoch exhausted")
+	}
+	return id, nil
+}
+
+// ShardOf returns the shard encoded in an ID created by a Snowflake.
+func ShardOf(id int64) int64 {
+	return id >> snowflakeSequenceBits & MaxSnowflakeShard
+}
+
+// TimeOf returns the time, relative to epoch, encoded in an ID created by a
+// Snowflake.
+func TimeOf(id int64, epoch time.Time) time.Time {
+	ms := id >> (snowflakeShardBits + snowflakeSequenceBits)
+	return epoch.Add(time.Duration(ms) * time.Millisecond)
+}
@@ -0,0 +1,66 @@
+package client_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestSnowflake(t *testing.T) {
+
+	epoch := time.Now().Add(-time.Hour)
+	s, err := client.NewSnowflake(5, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[int64]bool{}
+	var last int64
+	for i := 0; i < 10000; i++ {
+		id, err := s.NewID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] || id <= last {
+			t.Fatal("expected unique increasing IDs", id, last)
+		}
+		seen[id] = true
+		last = id
+	}
+
+	if shard := client.ShardOf(last); shard != 5 {
+		t.Fatal("expected shard 5", shard)
+	}
+	if d := time.Since(client.TimeOf(last, epoch)); d < 0 || d > time.Second {
+		t.Fatal("expected recent ID time", d)
+	}
+
+	if _, err := client.NewSnowflake(client.MaxSnowflakeShard+1,
+		epoch); err == nil {
+		t.Fatal("expected shard out of range")
+	}
+	if _, err := client.NewSnowflake(1, time.Now().Add(time.Hour)); err == nil {
+		t.Fatal("expected future epoch to be refused")
+	}
+}
+
+func TestWithIDGenerator(t *testing.T) {
+
+	s, err := client.NewSnowflake(1, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The URL is never contacted.
+	cl := client.New(http.DefaultClient, "http://127.0.0.1:0", "user", "pass",
+		client.WithIDGenerator(s))
+
+	ids, err := cl.CreateTransactionIDs(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 3 || client.ShardOf(ids[2]) != 1 {
+		t.Fatal("expected generated IDs", ids)
+	}
+}
@@ -0,0 +1,183 @@
+package client
+
+import "strconv"
+
+// requestedLimit returns the limit set by options, or zero if none is set or
+// a TypeFilter is given, as filtered pages are shorter than the page the
+// server applied the limit to.
+func requestedLimit(options []option) int {
+	o, err := applyOptions(options)
+	if err != nil || o.types != 0 {
+		return 0
+	}
+	limit, _ := strconv.Atoi(o.query.Get("limit"))
+	return limit
+}
+
+// TransactionIterator pages through all the transactions of an account,
+// following the cursor returned by AccountTransactions.
+//
+// RTWire may return fewer transactions than requested with Limit() when the
+// requested limit exceeds the server maximum. A short page is therefore not
+// treated as the end of the results; iteration only ends when no cursor is
+// returned. The limit the server actually applied is available from
+// EffectiveLimit and is requested for the pages after it is detected.
+type TransactionIterator struct {
+	client    Client
+	accountID int64
+	options   []option
+	limit     int
+	effective int
+
+	next    string
+	started bool
+	page    []Transaction
+	tx      Transaction
+	err     error
+}
+
+// NewTransactionIterator returns an iterator over the transactions of
+// accountID. Options are passed to each AccountTransactions call. A Next()
+// option can be given to resume from a previous cursor.
+func NewTransactionIterator(c Client, accountID int64,
+	options ...option) *TransactionIterator {
+	return &TransactionIterator{
+		client:    c,
+		accountID: accountID,
+		options:   options,
+		limit:     requestedLimit(options),
+	}
+}
+
+// Next advances the iterator and reports whether a transaction is available
+// from Transaction. It returns false at the end of the results or on error.
+func (it *TransactionIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || (it.started && it.next == "") {
+			return false
+		}
+		it.fetch()
+	}
+	it.tx, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+func (it *TransactionIterator) fetch() {
+	options := it.options
+	if it.started {
+		options = append(options[:len(options):len(options)], Next(it.next))
+	}
+	if it.effective != 0 {
+		// Request the page size the server applies rather than the limit
+		// it refused.
+		options = append(options[:len(options):len(options)],
+			Limit(it.effective))
+	}
+
+	next, txns, err := it.client.AccountTransactions(it.accountID, options...)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.started = true
+
+	if next != "" && it.limit > 0 && len(txns) < it.limit {
+		it.effective = len(txns)
+	}
+	it.next = next
+	it.page = txns
+}
+
+// Transaction returns the current transaction.
+func (it *TransactionIterator) Transaction() Transaction {
+	return it.tx
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *TransactionIterator) Err() error {
+	return it.err
+}
+
+// Cursor returns the cursor of the page after the one currently being
+// returned. It can be passed to Next() to resume iteration later, skipping
+// any transactions of the current page not yet returned by Next.
+func (it *TransactionIterator) Cursor() string {
+	return it.next
+}
+
+// EffectiveLimit returns the page size the server applied if it was lower than
+// the requested limit, otherwise the requested limit. It is zero if no limit
+// was requested or a TypeFilter is given.
+func (it *TransactionIterator) EffectiveLimit() int {
+	if it.effective != 0 {
+		return it.effective
+	}
+	return it.limit
+}
+
+// AccountIterator pages through all accounts, following the cursor returned
+// by Accounts. Like TransactionIterator it only stops when no cursor is
+// returned, so pages shorter than the requested limit are followed.
+type AccountIterator struct {
+	client  Client
+	options []option
+
+	next    string
+	started bool
+	page    []Account
+	acc     Account
+	err     error
+}
+
+// NewAccountIterator returns an iterator over all accounts. Options are passed
+// to each Accounts call. A Next() option can be given to resume from a
+// previous cursor.
+func NewAccountIterator(c Client, options ...option) *AccountIterator {
+	return &AccountIterator{client: c, options: options}
+}
+
+// Next advances the iterator and reports whether an account is available from
+// Account. It returns false at the end of the results or on error.
+func (it *AccountIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.err != nil || (it.started && it.next == "") {
+			return false
+		}
+		it.fetch()
+	}
+	it.acc, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+func (it *AccountIterator) fetch() {
+	options := it.options
+	if it.started {
+		options = append(options[:len(options):len(options)], Next(it.next))
+	}
+
+	next, accs, err := it.client.Accounts(options...)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.started = true
+	it.next = next
+	it.page = accs
+}
+
+// Account returns the current account.
+func (it *AccountIterator) Account() Account {
+	return it.acc
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *AccountIterator) Err() error {
+	return it.err
+}
+
+// Cursor returns the cursor of the page after the one currently being
+// returned. It can be passed to Next() to resume iteration later, skipping
+// any accounts of the current page not yet returned by Next.
+func (it *AccountIterator) Cursor() string {
+	return it.next
+}
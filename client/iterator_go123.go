@@ -0,0 +1,20 @@
+//go:build go1.23
+
+package client
+
+import "iter"
+
+// All returns the remaining accounts for use with range. An error stopping
+// the iteration is yielded with a zero Account as the final element.
+func (it *AccountIterator) All() iter.Seq2[Account, error] {
+	return func(yield func(Account, error) bool) {
+		for it.Next() {
+			if !yield(it.acc, nil) {
+				return
+			}
+		}
+		if it.err != nil {
+			yield(Account{}, it.err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+//go:build go1.23
+
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestAccountIteratorAll(t *testing.T) {
+
+	server := newAccountsServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	var ids []int64
+	for acc, err := range client.NewAccountIterator(cl).All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, acc.ID)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Fatalf("expected three accounts %v", ids)
+	}
+
+	var err error
+	for _, err = range client.NewAccountIterator(cl,
+		client.Next("x")).All() {
+	}
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,158 @@
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestTransactionIterator(t *testing.T) {
+
+	// The server caps pages at two transactions regardless of the limit.
+	var limits []string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			limits = append(limits, r.URL.Query().Get("limit"))
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Query().Get("next") {
+			case "":
+				fmt.Fprint(w, `{"type": "transactions", "next": "b",
+					"payload": [{"id": 1}, {"id": 2}]}`)
+			case "b":
+				fmt.Fprint(w, `{"type": "transactions", "next": "c",
+					"payload": [{"id": 3}, {"id": 4}]}`)
+			case "c":
+				fmt.Fprint(w, `{"type": "transactions",
+					"payload": [{"id": 5}]}`)
+			}
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	it := client.NewTransactionIterator(cl, 1, client.Limit(5))
+	var ids []int64
+	for it.Next() {
+		ids = append(ids, it.Transaction().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ids) != 5 {
+		t.Fatalf("expected five transactions %v", ids)
+	}
+	if it.EffectiveLimit() != 2 {
+		t.Fatal("expected effective limit of two", it.EffectiveLimit())
+	}
+	// Later pages request the limit the server applied.
+	if fmt.Sprint(limits) != "[5 2 2]" {
+		t.Fatal("unexpected limits requested", limits)
+	}
+
+	// Resume from a cursor.
+	it = client.NewTransactionIterator(cl, 1, client.Limit(5), client.Next("c"))
+	if !it.Next() || it.Transaction().ID != 5 {
+		t.Fatal("expected to resume at transaction 5")
+	}
+	if it.Next() {
+		t.Fatal("expected end of transactions")
+	}
+}
+
+func TestTransactionIteratorTypeFilter(t *testing.T) {
+
+	// Pages are filtered after the server applied the limit.
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Query().Get("next") {
+			case "":
+				fmt.Fprint(w, `{"type": "transactions", "next": "b",
+					"payload": [{"id": 1, "type": "debit"},
+					{"id": 2, "type": "credit"}]}`)
+			case "b":
+				fmt.Fprint(w, `{"type": "transactions",
+					"payload": [{"id": 3, "type": "debit"}]}`)
+			}
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	it := client.NewTransactionIterator(cl, 1, client.Limit(2),
+		client.TypeFilter(client.Debit))
+	var ids []int64
+	for it.Next() {
+		ids = append(ids, it.Transaction().ID)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Fatalf("expected debits 1 and 3 %v", ids)
+	}
+	if it.EffectiveLimit() != 0 {
+		t.Fatal("expected no effective limit", it.EffectiveLimit())
+	}
+}
+
+// newAccountsServer serves three accounts over the pages "", "b" and "c".
+func newAccountsServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Query().Get("next") {
+			case "":
+				fmt.Fprint(w, `{"type": "accounts", "next": "b",
+					"payload": [{"id": 1}, {"id": 2}]}`)
+			case "b":
+				fmt.Fprint(w, `{"type": "accounts", "next": "c",
+					"payload": [{"id": 3}]}`)
+			case "c":
+				fmt.Fprint(w, `{"type": "accounts", "payload": []}`)
+			default:
+				http.Error(w, "bad cursor", http.StatusInternalServerError)
+			}
+		}))
+}
+
+func TestAccountIterator(t *testing.T) {
+
+	server := newAccountsServer()
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	var ids []int64
+	it := client.NewAccountIterator(cl)
+	for it.Next() {
+		ids = append(ids, it.Account().ID)
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[2] != 3 {
+		t.Fatalf("expected three accounts %v", ids)
+	}
+
+	// Resume from a cursor.
+	it = client.NewAccountIterator(cl, client.Next("b"))
+	if !it.Next() || it.Account().ID != 3 {
+		t.Fatal("expected to resume at account 3")
+	}
+	if it.Next() || it.Err() != nil {
+		t.Fatal("expected end of accounts", it.Err())
+	}
+
+	it = client.NewAccountIterator(cl, client.Next("x"))
+	if it.Next() || it.Err() == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrPartialResult is matched by the error returned alongside partial
+// results when the context of a listing ends before every page was read.
+var ErrPartialResult = errors.New("partial result")
+
+// PartialResultError is returned with the results gathered so far when the
+// context of AllTransactions or AllAccounts is done mid-pagination. Cursor
+// resumes the listing from the first page not read when passed to Next(), and
+// Err is the context's error.
+type PartialResultError struct {
+	Cursor string
+	Err    error
+}
+
+func (e *PartialResultError) Error() string {
+	return fmt.Sprintf("partial result: %v", e.Err)
+}
+
+// Is reports whether target is ErrPartialResult.
+func (e *PartialResultError) Is(target error) bool {
+	return target == ErrPartialResult
+}
+
+func (e *PartialResultError) Unwrap() error {
+	return e.Err
+}
+
+// AllTransactions returns every transaction of accountID, following the
+// cursor of AccountTransactions until no cursor is returned. Options are passed
+// to each call, and a Next() option starts from a previous cursor.
+//
+// If ctx is done before every page was read, as when a time-boxed report hits
+// its deadline, the transactions read so far are returned with a
+// *PartialResultError holding the cursor to resume from. Other errors are
+// returned without results.
+func AllTransactions(ctx context.Context, c Client, accountID int64,
+	options ...option) ([]Transaction, error) {
+	return collect(ctx, options, func(options []option) (string,
+		[]Transaction, error) {
+		return c.AccountTransactionsContext(ctx, accountID, options...)
+	})
+}
+
+// AllAccounts returns every account, following the cursor of Accounts as
+// AllTransactions does.
+func AllAccounts(ctx context.Context, c Client,
+	options ...option) ([]Account, error) {
+	return collect(ctx, options, func(options []option) (string, []Account,
+		error) {
+		return c.AccountsContext(ctx, options...)
+	})
+}
+
+// collect calls fetch for each page of a listing, starting at the cursor set
+// in options, if any.
+func collect[T any](ctx context.Context, options []option,
+	fetch func(options []option) (string, []T, error)) ([]T, error) {
+	o, err := applyOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	cursor := o.query.Get("next")
+
+	var all []T
+	for {
+		if err := ctx.Err(); err != nil {
+			return all, &PartialResultError{Cursor: cursor, Err: err}
+		}
+		pageOptions := options
+		if cursor != "" {
+			pageOptions = append(options[:len(options):len(options)],
+				Next(cursor))
+		}
+		next, page, err := fetch(pageOptions)
+		if err != nil {
+			if isContextErr(err) && ctx.Err() != nil {
+				return all, &PartialResultError{Cursor: cursor, Err: err}
+			}
+			return nil, err
+		}
+		all = append(all, page...)
+		if next == "" {
+			return all, nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,84 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/clientmock"
+)
+
+func TestAllTransactionsPartial(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	m := &clientmock.Client{
+		AccountTransactionsFunc: func(ctx context.Context, accountID int64,
+			options ...client.Option) (string, []client.Transaction, error) {
+			calls++
+			switch calls {
+			case 1:
+				return "p2", []client.Transaction{{ID: 1}, {ID: 2}}, nil
+			case 2:
+				return "p3", []client.Transaction{{ID: 3}}, nil
+			}
+			// The deadline of a report is hit while reading the third page.
+			cancel()
+			return "", nil, ctx.Err()
+		},
+	}
+
+	txns, err := client.AllTransactions(ctx, m, 1)
+	var partial *client.PartialResultError
+	if !errors.As(err, &partial) || !errors.Is(err, client.ErrPartialResult) ||
+		!errors.Is(err, context.Canceled) {
+		t.Fatal("expected partial result", err)
+	}
+	if len(txns) != 3 || partial.Cursor != "p3" {
+		t.Fatal("expected the pages read and their cursor", txns,
+			partial.Cursor)
+	}
+
+	// Other errors discard the results.
+	errFailed := errors.New("failed")
+	m.AccountsFunc = func(ctx context.Context,
+		options ...client.Option) (string, []client.Account, error) {
+		return "", nil, errFailed
+	}
+	if accs, err := client.AllAccounts(context.Background(),
+		m); accs != nil || err != errFailed {
+		t.Fatal("expected the error", accs, err)
+	}
+}
+
+func TestAllAccounts(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("next") == "p2" {
+				fmt.Fprint(w, `{"type": "accounts", "payload": [{"id": 2}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"type": "accounts", "next": "p2",
+				"payload": [{"id": 1}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	accs, err := client.AllAccounts(context.Background(), cl, client.Limit(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accs) != 2 || accs[0].ID != 1 || accs[1].ID != 2 {
+		t.Fatalf("expected every page %+v", accs)
+	}
+}
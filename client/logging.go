@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WithLogger logs each request sent to RTWire to logger, at debug level when
+// it succeeds and at info level when it fails or RTWire returns an error
+// status. Records include the method, path, status, duration, request ID and
+// the number of times the request has been retried. Credentials are never
+// logged, and nor are hook URLs, which may carry tokens of their own.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *client) {
+		c.logger = logger
+	}
+}
+
+// retriesKey is the context key holding the number of times a request has
+// been retried.
+type retriesKey struct{}
+
+func retriesFromContext(ctx context.Context) int {
+	n, _ := ctx.Value(retriesKey{}).(int)
+	return n
+}
+
+// logRequest logs the outcome of sending req.
+func (c *client) logRequest(req *http.Request, resp *http.Response, err error,
+	duration time.Duration) {
+
+	path := req.URL.Path
+	hook := hookSegment(path)
+	if hook != "" {
+		path = strings.Replace(path, hook, redacted, 1)
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", path),
+		slog.Duration("duration", duration),
+		slog.Int("retries", retriesFromContext(req.Context())),
+		slog.String("request_id", req.Header.Get(RequestIDHeader)),
+	}
+	if req.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", req.URL.RawQuery))
+	}
+
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelInfo
+		msg := err.Error()
+		if hook != "" {
+			// Errors from the http.Client quote the full URL.
+			msg = strings.ReplaceAll(msg, hook, redacted)
+		}
+		attrs = append(attrs, slog.String("error", msg))
+	} else {
+		if resp.StatusCode >= 400 {
+			level = slog.LevelInfo
+		}
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	c.logger.LogAttrs(req.Context(), level, "rtwire request", attrs...)
+}
+
+// redacted replaces the hook URLs of logged paths.
+const redacted = "REDACTED"
+
+// hookSegment returns the encoded hook URL in path, the segment following
+// "hooks", or "" if there is none.
+func hookSegment(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments[:len(segments)-1] {
+		if seg == "hooks" {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package client_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestWithLogger(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"GET /fees/": `{"type": "fees", "payload": []}`,
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf,
+		&slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "secret",
+		client.WithLogger(logger))
+
+	if _, err := cl.Fees(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cl.Account(1); err == nil {
+		t.Fatal("expected not found")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatal("expected two records", lines)
+	}
+	for _, want := range []string{"level=DEBUG", "method=GET",
+		"path=/v1/mainnet/fees/", "status=200", "retries=0", "duration="} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("expected %q in %s", want, lines[0])
+		}
+	}
+	if !strings.Contains(lines[1], "level=INFO") ||
+		!strings.Contains(lines[1], "status=404") {
+		t.Error("expected failed request at info level", lines[1])
+	}
+	if strings.Contains(buf.String(), "secret") {
+		t.Fatal("credentials were logged")
+	}
+}
+
+func TestWithLoggerRedactsHooks(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf,
+		&slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithLogger(logger))
+
+	const hook = "https://example.com/hook?token=abc"
+	cl.DeleteHook(hook)
+	cl.DeleteAccountHook(1, hook)
+
+	// A request that fails before a response quotes the URL in its error.
+	cl = client.New(http.DefaultClient, "http://127.0.0.1:0/v1/mainnet",
+		"user", "pass", client.WithLogger(logger))
+	cl.DeleteHook(hook)
+
+	encoded := base64.URLEncoding.EncodeToString([]byte(hook))
+	out := buf.String()
+	if strings.Contains(out, encoded) || strings.Contains(out, "token") {
+		t.Fatal("hook URL was logged", out)
+	}
+	for _, want := range []string{"path=/v1/mainnet/hooks/REDACTED",
+		"path=/v1/mainnet/accounts/1/hooks/REDACTED", "error="} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in %s", want, out)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// Period is the interval over which a Budget counts requests.
+type Period int
+
+const (
+	// Daily budgets reset at midnight UTC.
+	Daily Period = iota
+
+	// Monthly budgets reset at midnight UTC on the first of the month.
+	Monthly
+)
+
+// start returns the start of the period holding t.
+func (p Period) start(t time.Time) time.Time {
+	t = t.UTC()
+	if p == Monthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// next returns the start of the period after the one starting at start.
+func (p Period) next(start time.Time) time.Time {
+	if p == Monthly {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// Usage is the use of a Budget in its current period.
+type Usage struct {
+	Limit     int64
+	Used      int64
+	Remaining int64
+
+	// Reset is when the current period ends and Used returns to zero.
+	Reset time.Time
+
+	// Endpoints holds the requests of the period by method and endpoint,
+	// for example "GET /v1/mainnet/accounts/:id".
+	Endpoints map[string]int64
+}
+
+// Budget counts requests against a limit per period, such as the monthly
+// request allowance of an RTWire plan, so that batch jobs can throttle
+// themselves before the allowance runs out. A Budget only counts requests; it
+// does not refuse them.
+//
+// A Budget is added to a client with client.WithMiddleware:
+//
+//	b := metrics.NewBudget(100000, metrics.Monthly)
+//	b.OnThreshold(0.9, func(u metrics.Usage) { log.Print("budget low") })
+//	cl := client.New(nil, client.MainNetURL, user, pass,
+//		client.WithMiddleware(b.Middleware()))
+type Budget struct {
+	limit  int64
+	period Period
+
+	mu         sync.Mutex
+	start      time.Time
+	used       int64
+	endpoints  map[string]int64
+	thresholds []*threshold
+}
+
+type threshold struct {
+	fraction float64
+	fn       func(Usage)
+	fired    bool
+}
+
+// NewBudget returns a Budget allowing limit requests per period.
+func NewBudget(limit int64, period Period) *Budget {
+	return &Budget{
+		limit:     limit,
+		period:    period,
+		start:     period.start(time.Now()),
+		endpoints: map[string]int64{},
+	}
+}
+
+// OnThreshold calls fn once per period, when the requests made reach fraction
+// of the limit, for example 0.9 to be warned when 90% of the budget is used.
+// fn is called by the goroutine making the request that reached the
+// threshold, before the request is sent.
+func (b *Budget) OnThreshold(fraction float64, fn func(Usage)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.thresholds = append(b.thresholds, &threshold{fraction: fraction, fn: fn})
+}
+
+// Usage returns the use of the budget in the current period.
+func (b *Budget) Usage() Usage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.roll(time.Now())
+	return b.usage()
+}
+
+// Remaining returns the number of requests left in the current period. It is
+// negative once the limit has been exceeded.
+func (b *Budget) Remaining() int64 {
+	return b.Usage().Remaining
+}
+
+// Middleware returns a client.Middleware that counts every request against the
+// budget, including retries.
+func (b *Budget) Middleware() client.Middleware {
+	return func(next client.RoundTripperFunc) client.RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			b.record(req.Method + " " + Endpoint(req.URL.Path))
+			return next(req)
+		}
+	}
+}
+
+func (b *Budget) record(endpoint string) {
+	b.mu.Lock()
+	b.roll(time.Now())
+	b.used++
+	b.endpoints[endpoint]++
+
+	var fire []func(Usage)
+	for _, t := range b.thresholds {
+		if !t.fired && float64(b.used) >= t.fraction*float64(b.limit) {
+			t.fired = true
+			fire = append(fire, t.fn)
+		}
+	}
+	var u Usage
+	if len(fire) > 0 {
+		u = b.usage()
+	}
+	b.mu.Unlock()
+
+	for _, fn := range fire {
+		fn(u)
+	}
+}
+
+// roll starts a new period if now is past the current one.
+func (b *Budget) roll(now time.Time) {
+	start := b.period.start(now)
+	if !start.After(b.start) {
+		return
+	}
+	b.start = start
+	b.used = 0
+	b.endpoints = map[string]int64{}
+	for _, t := range b.thresholds {
+		t.fired = false
+	}
+}
+
+func (b *Budget) usage() Usage {
+	endpoints := make(map[string]int64, len(b.endpoints))
+	for k, n := range b.endpoints {
+		endpoints[k] = n
+	}
+	return Usage{
+		Limit:     b.limit,
+		Used:      b.used,
+		Remaining: b.limit - b.used,
+		Reset:     b.period.next(b.start),
+		Endpoints: endpoints,
+	}
+}
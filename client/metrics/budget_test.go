@@ -0,0 +1,54 @@
+package metrics_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/metrics"
+)
+
+func TestBudget(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "accounts", "payload": [{"id": 1}]}`)
+		}))
+	defer server.Close()
+
+	b := metrics.NewBudget(4, metrics.Monthly)
+	var alerts []metrics.Usage
+	b.OnThreshold(0.5, func(u metrics.Usage) {
+		alerts = append(alerts, u)
+	})
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithMiddleware(b.Middleware()))
+
+	for _, id := range []int64{1, 2, 3} {
+		if _, err := cl.Account(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(alerts) != 1 || alerts[0].Used != 2 {
+		t.Fatalf("expected one alert at half the budget %+v", alerts)
+	}
+	u := b.Usage()
+	if u.Used != 3 || u.Remaining != 1 || b.Remaining() != 1 {
+		t.Fatalf("unexpected usage %+v", u)
+	}
+	if n := u.Endpoints["GET /v1/mainnet/accounts/:id"]; n != 3 {
+		t.Fatalf("expected requests by endpoint %+v", u.Endpoints)
+	}
+	now := time.Now().UTC()
+	reset := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	if !u.Reset.Equal(reset) {
+		t.Fatalf("expected reset at %v got %v", reset, u.Reset)
+	}
+}
@@ -0,0 +1,244 @@
+// Package metrics records RTWire request metrics and serves them in the
+// Prometheus text exposition format.
+//
+// A Collector is added to a client with client.WithMiddleware and served on
+// an HTTP endpoint scraped by Prometheus:
+//
+//	m := metrics.NewCollector()
+//	cl := client.New(nil, client.MainNetURL, user, pass,
+//		client.WithMiddleware(m.Middleware()))
+//	http.Handle("/metrics", m)
+//
+// Requests are labelled by method and endpoint. Endpoints are request paths
+// with numeric segments, such as account IDs, replaced by ":id" to keep the
+// number of series bounded.
+//
+// A Budget, added in the same way, counts requests against a daily or monthly
+// limit, such as that of an RTWire plan.
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the request latency
+// histogram buckets.
+var DefaultBuckets = []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Collector records request counts, error counts and latencies.
+type Collector struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	errors    map[errorKey]uint64
+	latencies map[endpointKey]*histogram
+}
+
+type endpointKey struct {
+	method, endpoint string
+}
+
+type requestKey struct {
+	endpointKey
+	status string
+}
+
+type errorKey struct {
+	endpointKey
+	code string
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewCollector returns a Collector using DefaultBuckets.
+func NewCollector() *Collector {
+	return NewCollectorBuckets(DefaultBuckets)
+}
+
+// NewCollectorBuckets returns a Collector whose latency histograms use the
+// given bucket upper bounds in seconds, which must be in increasing order.
+func NewCollectorBuckets(buckets []float64) *Collector {
+	return &Collector{
+		buckets:   buckets,
+		requests:  map[requestKey]uint64{},
+		errors:    map[errorKey]uint64{},
+		latencies: map[endpointKey]*histogram{},
+	}
+}
+
+// Middleware returns a client.Middleware that records every request.
+func (c *Collector) Middleware() client.Middleware {
+	return func(next client.RoundTripperFunc) client.RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			elapsed := time.Since(start)
+
+			key := endpointKey{req.Method, Endpoint(req.URL.Path)}
+			status, code := "", ""
+			switch {
+			case err != nil:
+				status, code = "error", "network"
+			case resp.StatusCode >= 400:
+				status = strconv.Itoa(resp.StatusCode)
+				code = errorCode(resp)
+			default:
+				status = strconv.Itoa(resp.StatusCode)
+			}
+			c.record(key, status, code, elapsed)
+			return resp, err
+		}
+	}
+}
+
+// errorCode returns the RTWire error code of resp, or its status code if the
+// body has none. The body is restored so it can still be read by the client.
+func errorCode(resp *http.Response) string {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err == nil {
+		var obj struct {
+			Type    string `json:"type"`
+			Payload []struct {
+				Code string `json:"code"`
+			} `json:"payload"`
+		}
+		if json.Unmarshal(body, &obj) == nil && obj.Type == "errors" &&
+			len(obj.Payload) > 0 && obj.Payload[0].Code != "" {
+			return obj.Payload[0].Code
+		}
+	}
+	return strconv.Itoa(resp.StatusCode)
+}
+
+func (c *Collector) record(key endpointKey, status, code string,
+	elapsed time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests[requestKey{key, status}]++
+	if code != "" {
+		c.errors[errorKey{key, code}]++
+	}
+
+	h, ok := c.latencies[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		c.latencies[key] = h
+	}
+	seconds := elapsed.Seconds()
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// Endpoint returns path with numeric segments replaced by ":id" and the
+// encoded hook URL following a hooks segment replaced by ":url".
+func Endpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+			segments[i] = ":id"
+		} else if i > 0 && segments[i-1] == "hooks" && s != "" {
+			segments[i] = ":url"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// ServeHTTP writes the collected metrics in the Prometheus text format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the collected metrics to w in the Prometheus text format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var b bytes.Buffer
+
+	b.WriteString("# HELP rtwire_requests_total RTWire requests by status.\n")
+	b.WriteString("# TYPE rtwire_requests_total counter\n")
+	lines := make([]string, 0, len(c.requests))
+	for k, n := range c.requests {
+		lines = append(lines, fmt.Sprintf("rtwire_requests_total{%s,"+
+			"status=%s} %d\n", labels(k.endpointKey), quote(k.status), n))
+	}
+	writeSorted(&b, lines)
+
+	b.WriteString("# HELP rtwire_request_errors_total RTWire request " +
+		"errors by error code.\n")
+	b.WriteString("# TYPE rtwire_request_errors_total counter\n")
+	lines = lines[:0]
+	for k, n := range c.errors {
+		lines = append(lines, fmt.Sprintf("rtwire_request_errors_total{%s,"+
+			"code=%s} %d\n", labels(k.endpointKey), quote(k.code), n))
+	}
+	writeSorted(&b, lines)
+
+	b.WriteString("# HELP rtwire_request_duration_seconds RTWire request " +
+		"latency.\n")
+	b.WriteString("# TYPE rtwire_request_duration_seconds histogram\n")
+	lines = lines[:0]
+	for k, h := range c.latencies {
+		l := labels(k)
+		var s strings.Builder
+		for i, bound := range c.buckets {
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			fmt.Fprintf(&s, "rtwire_request_duration_seconds_bucket{%s,"+
+				"le=%s} %d\n", l, quote(le), h.counts[i])
+		}
+		fmt.Fprintf(&s, "rtwire_request_duration_seconds_bucket{%s,"+
+			"le=\"+Inf\"} %d\n", l, h.count)
+		fmt.Fprintf(&s, "rtwire_request_duration_seconds_sum{%s} %g\n",
+			l, h.sum)
+		fmt.Fprintf(&s, "rtwire_request_duration_seconds_count{%s} %d\n",
+			l, h.count)
+		lines = append(lines, s.String())
+	}
+	writeSorted(&b, lines)
+
+	return b.WriteTo(w)
+}
+
+func labels(k endpointKey) string {
+	return fmt.Sprintf("method=%s,endpoint=%s", quote(k.method),
+		quote(k.endpoint))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote quotes a label value as required by the Prometheus text format.
+func quote(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
+
+func writeSorted(b *bytes.Buffer, lines []string) {
+	sort.Strings(lines)
+	for _, line := range lines {
+		b.WriteString(line)
+	}
+}
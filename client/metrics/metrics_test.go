@@ -0,0 +1,87 @@
+package metrics_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/metrics"
+)
+
+func TestCollector(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == "PUT" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"type": "errors", "payload": [
+					{"code": "insufficient funds",
+					"message": "insufficient funds"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"type": "accounts", "payload": [{"id": 1}]}`)
+		}))
+	defer server.Close()
+
+	m := metrics.NewCollectorBuckets([]float64{1})
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithMiddleware(m.Middleware()))
+
+	for _, id := range []int64{1, 2} {
+		if _, err := cl.Account(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := cl.Transfer(1, 2, 3, 4); err == nil ||
+		err.Error() != "insufficient funds" {
+		t.Fatal("expected the error body to still be decoded", err)
+	}
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	out := rec.Body.String()
+
+	for _, want := range []string{
+		`rtwire_requests_total{method="GET",` +
+			`endpoint="/v1/mainnet/accounts/:id",status="200"} 2`,
+		`rtwire_requests_total{method="PUT",` +
+			`endpoint="/v1/mainnet/transactions/",status="400"} 1`,
+		`rtwire_request_errors_total{method="PUT",` +
+			`endpoint="/v1/mainnet/transactions/",` +
+			`code="insufficient funds"} 1`,
+		`rtwire_request_duration_seconds_bucket{method="GET",` +
+			`endpoint="/v1/mainnet/accounts/:id",le="1"} 2`,
+		`rtwire_request_duration_seconds_bucket{method="GET",` +
+			`endpoint="/v1/mainnet/accounts/:id",le="+Inf"} 2`,
+		`rtwire_request_duration_seconds_count{method="GET",` +
+			`endpoint="/v1/mainnet/accounts/:id"} 2`,
+	} {
+		if !strings.Contains(out, want+"\n") {
+			t.Errorf("expected %s in\n%s", want, out)
+		}
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/v1/mainnet/accounts/12", "/v1/mainnet/accounts/:id"},
+		{"/v1/mainnet/hooks/", "/v1/mainnet/hooks/"},
+		{"/v1/mainnet/hooks/aHR0cHM6Ly9leGFtcGxlLmNvbQ==",
+			"/v1/mainnet/hooks/:url"},
+		{"/v1/mainnet/accounts/3/hooks/aHR0cHM6Ly9leGFtcGxlLmNvbQ==",
+			"/v1/mainnet/accounts/:id/hooks/:url"},
+	}
+	for _, test := range tests {
+		if got := metrics.Endpoint(test.path); got != test.want {
+			t.Errorf("Endpoint(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
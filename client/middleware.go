@@ -0,0 +1,27 @@
+package client
+
+import "net/http"
+
+// RoundTripperFunc sends an HTTP request and returns its response, like
+// http.RoundTripper.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the sending of each request to RTWire. A middleware may
+// modify the request, for example to add headers, observe the response or
+// error, or return without calling next.
+type Middleware func(next RoundTripperFunc) RoundTripperFunc
+
+// WithMiddleware adds middleware around every request the client sends. The
+// first middleware given is the outermost and sees requests first. Requests
+// reach middleware with their headers and credentials already set, and once
+// per attempt after any rate limiting or circuit breaking.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
@@ -0,0 +1,66 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestWithMiddleware(t *testing.T) {
+
+	var stamp string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			stamp = r.Header.Get("X-Stamp")
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "fees", "payload": []}`)
+		}))
+	defer server.Close()
+
+	var calls []string
+	trace := func(name string) client.Middleware {
+		return func(next client.RoundTripperFunc) client.RoundTripperFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				if user, _, ok := req.BasicAuth(); !ok || user != "user" {
+					t.Error("expected credentials to be set")
+				}
+				req.Header.Add("X-Stamp", name)
+				resp, err := next(req)
+				calls = append(calls, name+" done")
+				return resp, err
+			}
+		}
+	}
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithMiddleware(trace("a"), trace("b")))
+
+	if _, err := cl.Fees(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"a", "b", "b done", "a done"}
+	if fmt.Sprint(calls) != fmt.Sprint(expected) {
+		t.Fatal("unexpected call order", calls)
+	}
+	if stamp != "a" {
+		t.Fatal("expected header from middleware", stamp)
+	}
+
+	errBlocked := errors.New("blocked")
+	block := func(client.RoundTripperFunc) client.RoundTripperFunc {
+		return func(*http.Request) (*http.Response, error) {
+			return nil, errBlocked
+		}
+	}
+	cl = client.New(http.DefaultClient, url, "user", "pass",
+		client.WithMiddleware(block))
+	if _, err := cl.Fees(); err != errBlocked {
+		t.Fatal("expected middleware error", err)
+	}
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Network is a bitcoin network served by RTWire and the prefixes of its
+// addresses, used to check the addresses given to a client.
+type Network struct {
+	// Name is the last element of the path of the network's RTWire URL,
+	// such as "testnet3".
+	Name string
+
+	// HRP is the human readable part of the network's bech32 addresses, such
+	// as "tb".
+	HRP string
+
+	// P2PKH and P2SH are the base58check version bytes of the network's
+	// addresses.
+	P2PKH, P2SH byte
+}
+
+// The networks whose RTWire URLs are recognized by New. Signet and RegTest are
+// only served by self-hosted or mock RTWire instances.
+var (
+	MainNet  = Network{"mainnet", "bc", 0x00, 0x05}
+	TestNet3 = Network{"testnet3", "tb", 0x6f, 0xc4}
+	Signet   = Network{"signet", "tb", 0x6f, 0xc4}
+	RegTest  = Network{"regtest", "bcrt", 0x6f, 0xc4}
+)
+
+// URL returns the RTWire URL of n under base, for example
+// RegTest.URL("http://localhost:8080/v1").
+func (n Network) URL(base string) string {
+	return strings.TrimSuffix(base, "/") + "/" + n.Name
+}
+
+// Validate returns a *ValidationError if n cannot describe a network: its name
+// must be a single path element and its prefixes must tell its addresses
+// apart.
+func (n Network) Validate() error {
+	switch {
+	case n.Name == "" || strings.Contains(n.Name, "/"):
+		return &ValidationError{"network", "name must be one path element"}
+	case n.HRP == "" || len(n.HRP) > 83:
+		return &ValidationError{"network", "hrp must be 1 to 83 characters"}
+	case strings.ToLower(n.HRP) != n.HRP:
+		return &ValidationError{"network", "hrp must be lower case"}
+	case n.P2PKH == n.P2SH:
+		return &ValidationError{"network",
+			"p2pkh and p2sh versions must differ"}
+	}
+	for _, r := range n.HRP {
+		if r < 33 || r > 126 {
+			return &ValidationError{"network",
+				"hrp must be printable ASCII"}
+		}
+	}
+	return nil
+}
+
+// WithNetwork sets the network whose addresses the client accepts, for a
+// client whose URL does not end with the name of a known network, such as a
+// self-hosted RTWire. n should pass Validate.
+func WithNetwork(n Network) ClientOption {
+	return func(c *client) {
+		c.network = &n
+	}
+}
+
+// networkOf returns the network of an RTWire URL, such as MainNetURL, from the
+// last element of its path, or nil if it names no known network.
+func networkOf(url string) *Network {
+	url = strings.TrimSuffix(url, "/")
+	for _, n := range []*Network{&MainNet, &TestNet3, &Signet, &RegTest} {
+		if strings.HasSuffix(url, "/"+n.Name) {
+			return n
+		}
+	}
+	return nil
+}
+
+// checkNetwork checks that addr is a valid address for network n.
+func (n *Network) checkNetwork(addr string) error {
+	lower := strings.ToLower(addr)
+	if i := strings.LastIndexByte(lower, '1'); i > 0 &&
+		checkSegwit(addr) == nil {
+		if lower[:i] != n.HRP {
+			return fmt.Errorf("not a %s address", n.Name)
+		}
+		return nil
+	}
+	version, err := base58Address(addr)
+	if err != nil {
+		return err
+	}
+	if version != n.P2PKH && version != n.P2SH {
+		return fmt.Errorf("not a %s address", n.Name)
+	}
+	return nil
+}
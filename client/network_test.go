@@ -0,0 +1,74 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestNetwork(t *testing.T) {
+
+	if url := client.RegTest.URL("http://localhost:8080/v1/"); url !=
+		"http://localhost:8080/v1/regtest" {
+		t.Fatal("unexpected URL", url)
+	}
+
+	hc := &http.Client{Transport: client.RoundTripperFunc(
+		func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("sent")
+		})}
+	regtest := client.New(hc, client.RegTest.URL("http://localhost/v1"),
+		"user", "pass")
+	liquid := client.Network{Name: "liquid", HRP: "ex", P2PKH: 0x39,
+		P2SH: 0x27}
+	custom := client.New(hc, "http://localhost/v1/custom", "user", "pass",
+		client.WithNetwork(liquid))
+
+	tests := []struct {
+		cl    client.Client
+		addr  string
+		valid bool
+	}{
+		{regtest, "bcrt1qw508d6qejxtdg4y5r3zarvary0c5xw7kygt080", true},
+		{regtest, "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", true},
+		{regtest, "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx", false},
+		{regtest, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", false},
+		{custom, "ex1qw508d6qejxtdg4y5r3zarvary0c5xw7kxw5fx4", true},
+		{custom, "ex1qw508d6qejxtdg4y5r3zarvary0c5xw7kxw5fx5", false},
+		{custom, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false},
+	}
+	for _, test := range tests {
+		_, err := test.cl.Debit(1, 1, test.addr, 1)
+		if invalid := errors.Is(err, client.ErrInvalidAddress); invalid ==
+			test.valid {
+			t.Errorf("%s: unexpected error %v", test.addr, err)
+		}
+	}
+}
+
+func TestNetworkValidate(t *testing.T) {
+
+	for _, n := range []client.Network{client.MainNet, client.TestNet3,
+		client.Signet, client.RegTest} {
+		if err := n.Validate(); err != nil {
+			t.Errorf("%s: %v", n.Name, err)
+		}
+	}
+
+	invalid := []client.Network{
+		{Name: "", HRP: "bc", P2PKH: 0x00, P2SH: 0x05},
+		{Name: "v1/mainnet", HRP: "bc", P2PKH: 0x00, P2SH: 0x05},
+		{Name: "custom", HRP: "", P2PKH: 0x00, P2SH: 0x05},
+		{Name: "custom", HRP: "BC", P2PKH: 0x00, P2SH: 0x05},
+		{Name: "custom", HRP: "b c", P2PKH: 0x00, P2SH: 0x05},
+		{Name: "custom", HRP: "bc", P2PKH: 0x05, P2SH: 0x05},
+	}
+	for _, n := range invalid {
+		var verr *client.ValidationError
+		if err := n.Validate(); !errors.As(err, &verr) {
+			t.Errorf("%+v: expected ValidationError, got %v", n, err)
+		}
+	}
+}
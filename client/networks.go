@@ -0,0 +1,66 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+)
+
+// ErrUnknownNetwork is returned from Networks.Client for a network it has no
+// client of.
+var ErrUnknownNetwork = errors.New("unknown network")
+
+// Login holds the credentials of one network for NewNetworks. RTWire issues
+// separate credentials for each network.
+type Login struct {
+	User string
+	Pass string
+}
+
+// Networks holds a client of each network a service runs against, such as
+// MainNet and TestNet3, so that the transport, retries and metrics are
+// configured once. It is safe for concurrent use.
+type Networks struct {
+	clients map[Network]Client
+}
+
+// NewNetworks creates a client of each network in logins under base, for
+// example "https://api.rtwire.com/v1", as New(c, n.URL(base), ...) would.
+// Every client uses the same http.Client and options. An option creating
+// its state when called, such as WithRateLimit, shares that state, here a
+// single limit, between the networks; others, such as WithCircuitBreaker,
+// keep state per network.
+func NewNetworks(c *http.Client, base string, logins map[Network]Login,
+	options ...ClientOption) *Networks {
+
+	if c == nil {
+		c = &http.Client{}
+	}
+	nets := &Networks{clients: make(map[Network]Client, len(logins))}
+	for n, login := range logins {
+		opts := append(options[:len(options):len(options)], WithNetwork(n))
+		nets.clients[n] = New(c, n.URL(base), login.User, login.Pass,
+			opts...)
+	}
+	return nets
+}
+
+// Client returns the client of network n, or ErrUnknownNetwork if n was not
+// given to NewNetworks.
+func (nets *Networks) Client(n Network) (Client, error) {
+	c, ok := nets.clients[n]
+	if !ok {
+		return nil, ErrUnknownNetwork
+	}
+	return c, nil
+}
+
+// Networks returns the networks that have a client, ordered by name.
+func (nets *Networks) Networks() []Network {
+	ns := make([]Network, 0, len(nets.clients))
+	for n := range nets.clients {
+		ns = append(ns, n)
+	}
+	sort.Slice(ns, func(i, j int) bool { return ns[i].Name < ns[j].Name })
+	return ns
+}
@@ -0,0 +1,56 @@
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestNetworks(t *testing.T) {
+
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			user, _, _ := r.BasicAuth()
+			got = append(got, fmt.Sprintf("%s %s %s", r.URL.Path, user,
+				r.UserAgent()))
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "fees", "payload": []}`)
+		}))
+	defer server.Close()
+
+	nets := client.NewNetworks(nil, server.URL+"/v1",
+		map[client.Network]client.Login{
+			client.MainNet:  {User: "main", Pass: "pass"},
+			client.TestNet3: {User: "test", Pass: "pass"},
+		}, client.WithUserAgent("shop"))
+
+	if ns := nets.Networks(); len(ns) != 2 || ns[0] != client.MainNet ||
+		ns[1] != client.TestNet3 {
+		t.Fatalf("unexpected networks %+v", ns)
+	}
+
+	for _, n := range []client.Network{client.TestNet3, client.MainNet} {
+		cl, err := nets.Client(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cl.Fees(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{
+		"/v1/testnet3/fees/ test shop",
+		"/v1/mainnet/fees/ main shop",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected %q got %q", want, got)
+	}
+
+	if _, err := nets.Client(client.RegTest); err != client.ErrUnknownNetwork {
+		t.Fatal("expected ErrUnknownNetwork", err)
+	}
+}
@@ -0,0 +1,101 @@
+package onchain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// Chain is a Source that also knows the height of the best chain, which is
+// needed to count confirmations. Esplora implements Chain.
+type Chain interface {
+	Source
+	TipHeight(ctx context.Context) (int64, error)
+}
+
+// Confirmations returns the number of confirmations of the bitcoin
+// transaction with the given hash, or zero while it is unconfirmed. The chain
+// is queried with ctx.
+func Confirmations(ctx context.Context, chain Chain, hash string) (int64,
+	error) {
+	tx, err := chain.Tx(ctx, hash)
+	if err != nil {
+		return 0, err
+	}
+	if !tx.Confirmed {
+		return 0, nil
+	}
+	tip, err := chain.TipHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if n := tip - tx.BlockHeight + 1; n > 0 {
+		return n, nil
+	}
+	return 0, nil
+}
+
+// WaitForConfirmations polls c for the RTWire transaction txID and chain for
+// the bitcoin transactions listed in it, every poll, until one of them has at
+// least n confirmations. It then returns the RTWire transaction.
+//
+// The transaction not existing yet, having no hashes or having hashes chain
+// does not know are all treated as not confirmed yet. Any other error stops
+// polling. As confirmations may take hours ctx is not given a deadline and
+// should be cancelled by the caller when it stops waiting; it also cancels
+// the calls to c and chain in flight. A poll interval that is not positive is
+// refused with a *client.ValidationError.
+func WaitForConfirmations(ctx context.Context, c client.Client, chain Chain,
+	txID, n int64, poll time.Duration) (client.Transaction, error) {
+
+	if poll <= 0 {
+		return client.Transaction{}, &client.ValidationError{
+			Field:  "poll",
+			Reason: "must be positive",
+		}
+	}
+	for {
+		tx, ok, err := confirmed(ctx, c, chain, txID, n)
+		if err != nil || ok {
+			return tx, err
+		}
+
+		timer := time.NewTimer(poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return client.Transaction{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// confirmed reports whether one of the bitcoin transactions of txID has at
+// least n confirmations.
+func confirmed(ctx context.Context, c client.Client, chain Chain,
+	txID, n int64) (client.Transaction, bool, error) {
+
+	tx, err := c.TransactionContext(ctx, txID)
+	if errors.Is(err, client.ErrNotFound) {
+		return client.Transaction{}, false, nil
+	}
+	if err != nil {
+		return client.Transaction{}, false, err
+	}
+
+	for _, hash := range tx.TxHashes {
+		confs, err := Confirmations(ctx, chain, hash)
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return client.Transaction{}, false, err
+		}
+		if confs >= n {
+			return tx, true, nil
+		}
+	}
+	return client.Transaction{}, false, nil
+}
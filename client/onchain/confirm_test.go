@@ -0,0 +1,88 @@
+package onchain_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/clientmock"
+	"github.com/rtwire/go/client/onchain"
+)
+
+func TestWaitForConfirmations(t *testing.T) {
+
+	var (
+		mu  sync.Mutex
+		tip int64 = 99
+	)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch r.URL.Path {
+			case "/blocks/tip/height":
+				tip++
+				fmt.Fprint(w, tip)
+			case "/tx/aa":
+				fmt.Fprint(w, `{
+					"txid": "aa",
+					"status": {"confirmed": true, "block_height": 100}
+				}`)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+	defer server.Close()
+
+	chain := onchain.NewEsplora(http.DefaultClient, server.URL)
+
+	calls := 0
+	c := &clientmock.Client{
+		TransactionFunc: func(ctx context.Context, txID int64,
+			options ...client.Option) (client.Transaction, error) {
+			calls++
+			switch calls {
+			case 1:
+				return client.Transaction{}, client.ErrNotFound
+			case 2:
+				return client.Transaction{ID: txID}, nil
+			}
+			return client.Transaction{
+				ID:       txID,
+				TxHashes: []string{"bb", "aa"},
+			}, nil
+		},
+	}
+
+	tx, err := onchain.WaitForConfirmations(context.Background(), c, chain,
+		7, 3, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.ID != 7 {
+		t.Fatal("incorrect transaction", tx)
+	}
+	if tip != 102 {
+		t.Fatal("expected three confirmations at height", tip)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		10*time.Millisecond)
+	defer cancel()
+	if _, err := onchain.WaitForConfirmations(ctx, c, chain, 7, 1000,
+		time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected deadline exceeded", err)
+	}
+
+	var verr *client.ValidationError
+	if _, err := onchain.WaitForConfirmations(context.Background(), c, chain,
+		7, 3, 0); !errors.As(err, &verr) || verr.Field != "poll" {
+		t.Fatal("expected invalid poll", err)
+	}
+}
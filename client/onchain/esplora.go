@@ -0,0 +1,110 @@
+package onchain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/rtwire/go/client"
+)
+
+const (
+	// BlockstreamMainNetURL is the URL of Blockstream's public mainnet
+	// Esplora server.
+	BlockstreamMainNetURL = "https://blockstream.info/api"
+
+	// BlockstreamTestNet3URL is the URL of Blockstream's public testnet3
+	// Esplora server.
+	BlockstreamTestNet3URL = "https://blockstream.info/testnet/api"
+)
+
+// Esplora is a Source backed by an Esplora HTTP server. See
+// https://github.com/Blockstream/esplora/blob/master/API.md for more
+// information.
+type Esplora struct {
+	client *http.Client
+	url    string
+}
+
+// NewEsplora creates a Source that queries the Esplora server at url.
+func NewEsplora(c *http.Client, url string) *Esplora {
+	return &Esplora{
+		client: c,
+		url:    url,
+	}
+}
+
+// get makes a GET request for url, which is cancelled with ctx.
+func (e *Esplora) get(ctx context.Context, url string) (*http.Response,
+	error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return e.client.Do(req)
+}
+
+// Tx returns the transaction with the given hash.
+func (e *Esplora) Tx(ctx context.Context, hash string) (Tx, error) {
+	resp, err := e.get(ctx, fmt.Sprintf("%s/tx/%s", e.url, hash))
+	if err != nil {
+		return Tx{}, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return Tx{}, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return Tx{}, fmt.Errorf("esplora: %s: %s", hash, resp.Status)
+	}
+
+	var tx struct {
+		TxID string `json:"txid"`
+		Vout []struct {
+			Address string `json:"scriptpubkey_address"`
+			Value   int64  `json:"value"`
+		} `json:"vout"`
+		Status struct {
+			Confirmed   bool  `json:"confirmed"`
+			BlockHeight int64 `json:"block_height"`
+		} `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tx); err != nil {
+		return Tx{}, err
+	}
+
+	outputs := make([]Output, len(tx.Vout))
+	for i, out := range tx.Vout {
+		outputs[i] = Output{
+			Address: out.Address,
+			Value:   client.Amount(out.Value),
+		}
+	}
+	return Tx{
+		Hash:        tx.TxID,
+		Outputs:     outputs,
+		Confirmed:   tx.Status.Confirmed,
+		BlockHeight: tx.Status.BlockHeight,
+	}, nil
+}
+
+// TipHeight returns the height of the last block in the best chain.
+func (e *Esplora) TipHeight(ctx context.Context) (int64, error) {
+	resp, err := e.get(ctx, e.url+"/blocks/tip/height")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("esplora: tip height: %s", resp.Status)
+	}
+
+	var height int64
+	if err := json.NewDecoder(resp.Body).Decode(&height); err != nil {
+		return 0, err
+	}
+	return height, nil
+}
@@ -0,0 +1,121 @@
+// Package onchain verifies RTWire debits against an independent view of the
+// bitcoin blockchain.
+//
+// RTWire is a custodial service. The Transaction returned for a debit lists
+// the hashes of the bitcoin transactions that paid it out. This package fetches
+// those transactions from a Source, such as an Esplora server, and checks that
+// they really pay the requested address the requested value. It can also wait
+// for a transaction to reach a number of confirmations.
+package onchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rtwire/go/client"
+)
+
+// ErrNotFound is returned from a Source when a transaction hash is unknown.
+var ErrNotFound = errors.New("transaction not found")
+
+// Output is a single output of a bitcoin transaction.
+type Output struct {
+	Address string
+	Value   client.Amount
+}
+
+// Tx is a bitcoin transaction as seen by a Source.
+type Tx struct {
+	Hash        string
+	Outputs     []Output
+	Confirmed   bool
+	BlockHeight int64
+}
+
+// Source retrieves bitcoin transactions by hash. Esplora implements Source
+// and an Electrum server can be used by implementing it over
+// blockchain.transaction.get.
+type Source interface {
+	Tx(ctx context.Context, hash string) (Tx, error)
+}
+
+// Debit is a debit made through RTWire along with the address and value that
+// were requested when it was made.
+type Debit struct {
+	Transaction client.Transaction
+	Address     string
+	Value       client.Amount
+}
+
+// Mismatch describes a debit that could not be verified on chain.
+type Mismatch struct {
+	TxID   int64
+	Reason string
+}
+
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("tx %d: %s", m.TxID, m.Reason)
+}
+
+// Verify checks that one of the bitcoin transactions listed in d pays d.Address
+// d.Value satoshi at the transaction's output index. A *Mismatch is returned if
+// it does not. Transactions are fetched with ctx.
+func Verify(ctx context.Context, src Source, d Debit) error {
+	return verify(ctx, src, d, map[string]Tx{})
+}
+
+// Reconcile verifies every debit in ds, fetching each bitcoin transaction only
+// once, and returns a Mismatch for each debit that could not be verified.
+// Errors from src other than ErrNotFound abort the reconciliation.
+func Reconcile(ctx context.Context, src Source, ds []Debit) ([]Mismatch,
+	error) {
+	seen := map[string]Tx{}
+	var mismatches []Mismatch
+	for _, d := range ds {
+		err := verify(ctx, src, d, seen)
+		if m, ok := err.(*Mismatch); ok {
+			mismatches = append(mismatches, *m)
+		} else if err != nil {
+			return mismatches, err
+		}
+	}
+	return mismatches, nil
+}
+
+func verify(ctx context.Context, src Source, d Debit,
+	seen map[string]Tx) error {
+	tx := d.Transaction
+	if len(tx.TxHashes) == 0 {
+		return &Mismatch{TxID: tx.ID, Reason: "no transaction hashes"}
+	}
+
+	for _, hash := range tx.TxHashes {
+		onchain, ok := seen[hash]
+		if !ok {
+			var err error
+			onchain, err = src.Tx(ctx, hash)
+			if err == ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			seen[hash] = onchain
+		}
+
+		i := tx.TxOutIndex
+		if i < 0 || i >= int64(len(onchain.Outputs)) {
+			continue
+		}
+		out := onchain.Outputs[i]
+		if out.Address == d.Address && out.Value == d.Value {
+			return nil
+		}
+	}
+	return &Mismatch{
+		TxID: tx.ID,
+		Reason: fmt.Sprintf("no output %d paying %d to %s",
+			tx.TxOutIndex, d.Value, d.Address),
+	}
+}
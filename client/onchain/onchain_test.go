@@ -0,0 +1,109 @@
+package onchain_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/onchain"
+)
+
+const debitAddr = "12aXxEWgTYZgAiGC81Tqu1cSiDUSy3embt"
+
+func TestReconcile(t *testing.T) {
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Path != "/tx/aa" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprintf(w, `{
+				"txid": "aa",
+				"vout": [
+					{"scriptpubkey_address": "change", "value": 100},
+					{"scriptpubkey_address": %q, "value": 5}
+				],
+				"status": {"confirmed": true, "block_height": 10}
+			}`, debitAddr)
+		}))
+	defer server.Close()
+
+	src := onchain.NewEsplora(http.DefaultClient, server.URL)
+	ctx := context.Background()
+
+	good := onchain.Debit{
+		Transaction: client.Transaction{
+			ID:         1,
+			TxHashes:   []string{"aa"},
+			TxOutIndex: 1,
+		},
+		Address: debitAddr,
+		Value:   5,
+	}
+	if err := onchain.Verify(ctx, src, good); err != nil {
+		t.Fatal(err)
+	}
+
+	wrongValue := good
+	wrongValue.Transaction.ID = 2
+	wrongValue.Value = 6
+
+	unknownHash := good
+	unknownHash.Transaction.ID = 3
+	unknownHash.Transaction.TxHashes = []string{"bb"}
+
+	requests = 0
+	mismatches, err := onchain.Reconcile(ctx, src,
+		[]onchain.Debit{good, wrongValue, unknownHash})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mismatches) != 2 {
+		t.Fatalf("expected two mismatches %+v", mismatches)
+	}
+	if mismatches[0].TxID != 2 || mismatches[1].TxID != 3 {
+		t.Fatalf("incorrect mismatches %+v", mismatches)
+	}
+
+	// Each hash should only have been fetched once.
+	if requests != 2 {
+		t.Fatal("expected two requests", requests)
+	}
+}
+
+func TestEsploraContext(t *testing.T) {
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}))
+	defer server.Close()
+	defer close(release)
+
+	src := onchain.NewEsplora(http.DefaultClient, server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(),
+		50*time.Millisecond)
+	defer cancel()
+
+	if _, err := src.Tx(ctx, "aa"); !errors.Is(err,
+		context.DeadlineExceeded) {
+		t.Fatal("expected deadline exceeded", err)
+	}
+	if _, err := src.TipHeight(ctx); !errors.Is(err,
+		context.DeadlineExceeded) {
+		t.Fatal("expected deadline exceeded", err)
+	}
+}
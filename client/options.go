@@ -0,0 +1,56 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// ownHTTPClient returns the client's http.Client, copying it first if it was
+// supplied by the caller so that options never modify a shared client.
+func (c *client) ownHTTPClient() *http.Client {
+	if !c.ownsHTTPClient {
+		hc := *c.client
+		c.client = &hc
+		c.ownsHTTPClient = true
+	}
+	return c.client
+}
+
+// WithHTTPClient replaces the http.Client passed to New.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *client) {
+		c.client = hc
+		c.ownsHTTPClient = false
+	}
+}
+
+// WithTimeout bounds the time taken by each HTTP request, including reading
+// the response body.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *client) {
+		c.ownHTTPClient().Timeout = timeout
+	}
+}
+
+// WithTransport sets the http.RoundTripper used to make requests.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *client) {
+		c.ownHTTPClient().Transport = rt
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request.
+func WithUserAgent(userAgent string) ClientOption {
+	return WithBaseHeader("User-Agent", userAgent)
+}
+
+// WithBaseHeader adds a header sent with each request. Headers set by the
+// client itself, such as Accept and Authorization, take precedence.
+func WithBaseHeader(key, value string) ClientOption {
+	return func(c *client) {
+		if c.header == nil {
+			c.header = http.Header{}
+		}
+		c.header.Add(key, value)
+	}
+}
@@ -0,0 +1,62 @@
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestOptions(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("User-Agent") != "test-agent" {
+				t.Errorf("incorrect user agent %q", r.Header.Get("User-Agent"))
+			}
+			if r.Header.Get("X-Tenant") != "a" {
+				t.Errorf("base header not set")
+			}
+			if r.URL.Path == "/v1/mainnet/accounts/2" {
+				time.Sleep(100 * time.Millisecond)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "accounts", "payload": [{"id": 1}]}`)
+		}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithTimeout(50*time.Millisecond),
+		client.WithTransport(transport),
+		client.WithUserAgent("test-agent"),
+		client.WithBaseHeader("X-Tenant", "a"))
+
+	if _, err := cl.Account(1); err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 1 {
+		t.Fatal("expected transport to be used")
+	}
+
+	if _, err := cl.Account(2); err == nil {
+		t.Fatal("expected timeout")
+	}
+
+	if http.DefaultClient.Timeout != 0 || http.DefaultClient.Transport != nil {
+		t.Fatal("shared http.Client modified")
+	}
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// PendingBalance returns the satoshi that have been detected by RTWire on
+// their way to accountID but not yet credited to it, so that a UI can show
+// incoming funds next to Account's Balance. RTWire does not report the pending
+// balance of an account, so it is summed from the account's pending
+// transactions, following the cursor of AccountTransactions with Pending
+// until every page has been read.
+func PendingBalance(ctx context.Context, c Client, accountID int64) (Amount,
+	error) {
+	var (
+		pending Amount
+		next    string
+	)
+	for {
+		options := []option{Pending()}
+		if next != "" {
+			options = append(options, Next(next))
+		}
+		var (
+			txns []Transaction
+			err  error
+		)
+		next, txns, err = c.AccountTransactionsContext(ctx, accountID,
+			options...)
+		if err != nil {
+			return 0, fmt.Errorf("pending balance of %d: %w", accountID, err)
+		}
+		for _, tx := range txns {
+			if tx.ToAccountID == accountID {
+				pending += tx.Value
+			}
+		}
+		if next == "" {
+			return pending, nil
+		}
+	}
+}
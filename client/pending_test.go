@@ -0,0 +1,46 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestPendingBalance(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("status") != "pending" {
+				t.Error("expected pending transactions", r.URL)
+			}
+			switch r.URL.Query().Get("next") {
+			case "":
+				fmt.Fprint(w, `{"type": "transactions", "next": "p2",
+					"payload": [
+					{"id": 1, "type": "credit", "toAccountID": 4, "value": 10},
+					{"id": 2, "type": "credit", "toAccountID": 4, "value": 20}]}`)
+			case "p2":
+				fmt.Fprint(w, `{"type": "transactions", "payload": [
+					{"id": 3, "type": "credit", "toAccountID": 4, "value": 5},
+					{"id": 4, "type": "debit", "fromAccountID": 4,
+					"value": 7}]}`)
+			}
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	pending, err := client.PendingBalance(context.Background(), cl, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != 35 {
+		t.Fatal("expected incoming pending credits summed", pending)
+	}
+}
@@ -0,0 +1,429 @@
+package qr
+
+// version describes the blocks of a QR code version at error correction
+// level M.
+type version struct {
+	ecPerBlock int
+	blocks     []int // data codewords of each block
+	alignment  []int // centres of the alignment patterns
+}
+
+// versions lists versions 1 to 10 at level M, as given in ISO/IEC 18004.
+var versions = []version{
+	{10, []int{16}, nil},
+	{16, []int{28}, []int{6, 18}},
+	{26, []int{44}, []int{6, 22}},
+	{18, []int{32, 32}, []int{6, 26}},
+	{24, []int{43, 43}, []int{6, 30}},
+	{16, []int{27, 27, 27, 27}, []int{6, 34}},
+	{18, []int{31, 31, 31, 31}, []int{6, 22, 38}},
+	{22, []int{38, 38, 39, 39}, []int{6, 24, 42}},
+	{22, []int{36, 36, 36, 37, 37}, []int{6, 26, 46}},
+	{26, []int{43, 43, 43, 43, 44}, []int{6, 28, 50}},
+}
+
+func (v version) dataCodewords() int {
+	n := 0
+	for _, b := range v.blocks {
+		n += b
+	}
+	return n
+}
+
+// Encode returns the QR code of text.
+func Encode(text string) (*Code, error) {
+	for i, v := range versions {
+		countBits := 8
+		if i+1 >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+8*len(text) > 8*v.dataCodewords() {
+			continue
+		}
+		data := encodeData(text, countBits, v.dataCodewords())
+		return newCode(i+1, v, interleave(v, data)), nil
+	}
+	return nil, ErrTooLong
+}
+
+// bitWriter appends bits to a byte slice, most significant bit first.
+type bitWriter struct {
+	data []byte
+	n    int // bits written
+}
+
+func (w *bitWriter) write(value uint, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.data = append(w.data, 0)
+		}
+		if value>>uint(i)&1 == 1 {
+			w.data[w.n/8] |= 0x80 >> uint(w.n%8)
+		}
+		w.n++
+	}
+}
+
+// encodeData returns the data codewords of text in byte mode, padded to
+// capacity codewords.
+func encodeData(text string, countBits, capacity int) []byte {
+	w := &bitWriter{}
+	w.write(0x4, 4) // byte mode
+	w.write(uint(len(text)), countBits)
+	for i := 0; i < len(text); i++ {
+		w.write(uint(text[i]), 8)
+	}
+	// Terminate with up to four zero bits and pad to a whole codeword.
+	terminator := 8*capacity - w.n
+	if terminator > 4 {
+		terminator = 4
+	}
+	w.write(0, terminator)
+	if w.n%8 != 0 {
+		w.write(0, 8-w.n%8)
+	}
+	for pad := byte(0xec); len(w.data) < capacity; pad ^= 0xec ^ 0x11 {
+		w.data = append(w.data, pad)
+	}
+	return w.data
+}
+
+// interleave splits data into the blocks of v, appends their error
+// correction codewords and interleaves the blocks.
+func interleave(v version, data []byte) []byte {
+	divisor := rsDivisor(v.ecPerBlock)
+	var blocks, ecs [][]byte
+	for _, n := range v.blocks {
+		blocks = append(blocks, data[:n])
+		ecs = append(ecs, rsRemainder(data[:n], divisor))
+		data = data[n:]
+	}
+
+	var out []byte
+	longest := v.blocks[len(v.blocks)-1]
+	for i := 0; i < longest; i++ {
+		for _, b := range blocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, ec := range ecs {
+			out = append(out, ec[i])
+		}
+	}
+	return out
+}
+
+// gfMul multiplies x and y in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMul(x, y byte) byte {
+	var z uint
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11d
+		z ^= uint(y>>uint(i)&1) * uint(x)
+	}
+	return byte(z)
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of degree n,
+// highest coefficient first and without its leading one.
+func rsDivisor(n int) []byte {
+	result := make([]byte, n)
+	result[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < n {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return result
+}
+
+// rsRemainder returns the error correction codewords of data.
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMul(d, factor)
+		}
+	}
+	return result
+}
+
+// matrix is a code being drawn. Function modules, the patterns and format
+// and version information, are marked so data and masks skip them.
+type matrix struct {
+	size     int
+	dark     []bool
+	function []bool
+}
+
+func (m *matrix) set(x, y int, dark bool) {
+	m.dark[y*m.size+x] = dark
+	m.function[y*m.size+x] = true
+}
+
+// newCode draws a code of the numbered version from its codewords, choosing
+// the mask with the lowest penalty.
+func newCode(number int, v version, codewords []byte) *Code {
+	size := 17 + 4*number
+	m := &matrix{size: size, dark: make([]bool, size*size),
+		function: make([]bool, size*size)}
+	m.drawPatterns(number, v)
+	m.drawCodewords(codewords)
+
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormat(mask)
+		if p := m.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		m.applyMask(mask) // masks undo themselves
+	}
+	m.applyMask(best)
+	m.drawFormat(best)
+	return &Code{size: size, modules: m.dark}
+}
+
+func (m *matrix) drawPatterns(number int, v version) {
+	for i := 0; i < m.size; i++ {
+		m.set(6, i, i%2 == 0)
+		m.set(i, 6, i%2 == 0)
+	}
+	m.drawFinder(3, 3)
+	m.drawFinder(m.size-4, 3)
+	m.drawFinder(3, m.size-4)
+
+	last := len(v.alignment) - 1
+	for i, x := range v.alignment {
+		for j, y := range v.alignment {
+			// Alignment patterns don't overlap the finder patterns.
+			if i == 0 && j == 0 || i == 0 && j == last ||
+				i == last && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					m.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format information, drawn once the mask is chosen.
+	m.drawFormat(0)
+
+	if number >= 7 {
+		rem := number
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1f25
+		}
+		bits := number<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := bits>>uint(i)&1 == 1
+			a, b := m.size-11+i%3, i/3
+			m.set(a, b, dark)
+			m.set(b, a, dark)
+		}
+	}
+}
+
+// drawFinder draws a finder pattern and its separator centred on x, y.
+func (m *matrix) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= m.size || yy < 0 || yy >= m.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			m.set(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawFormat draws both copies of the format information for mask.
+func (m *matrix) drawFormat(mask int) {
+	data := mask // level M is 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>uint(i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		m.set(8, i, bit(i))
+	}
+	m.set(8, 7, bit(6))
+	m.set(8, 8, bit(7))
+	m.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		m.set(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.set(8, m.size-15+i, bit(i))
+	}
+	m.set(8, m.size-8, true)
+}
+
+// drawCodewords places codewords in the zigzag order of the standard, in
+// pairs of columns from the right, skipping function modules.
+func (m *matrix) drawCodewords(codewords []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < m.size; vert++ {
+			y := vert
+			if upward {
+				y = m.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if m.function[y*m.size+x] || i >= len(codewords)*8 {
+					continue
+				}
+				m.dark[y*m.size+x] = codewords[i/8]>>uint(7-i%8)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts the data modules selected by mask.
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !m.function[y*m.size+x] {
+				m.dark[y*m.size+x] = !m.dark[y*m.size+x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the masked code is to read, as defined by the
+// standard: long runs, 2x2 blocks, finder-like patterns and imbalance
+// between dark and light modules.
+func (m *matrix) penalty() int {
+	result := 0
+	for _, vertical := range []bool{false, true} {
+		for a := 0; a < m.size; a++ {
+			runDark, run := false, 0
+			var history [7]int
+			for b := 0; b < m.size; b++ {
+				x, y := b, a
+				if vertical {
+					x, y = a, b
+				}
+				dark := m.dark[y*m.size+x]
+				if dark == runDark {
+					run++
+					if run == 5 {
+						result += 3
+					} else if run > 5 {
+						result++
+					}
+					continue
+				}
+				m.addHistory(run, &history)
+				if !runDark {
+					result += finderPatterns(&history) * 40
+				}
+				runDark, run = dark, 1
+			}
+			if runDark {
+				m.addHistory(run, &history)
+				run = 0
+			}
+			m.addHistory(run+m.size, &history)
+			result += finderPatterns(&history) * 40
+		}
+	}
+
+	dark := 0
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			c := m.dark[y*m.size+x]
+			if c {
+				dark++
+			}
+			if x+1 < m.size && y+1 < m.size &&
+				c == m.dark[y*m.size+x+1] &&
+				c == m.dark[(y+1)*m.size+x] &&
+				c == m.dark[(y+1)*m.size+x+1] {
+				result += 3
+			}
+		}
+	}
+	total := m.size * m.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return result + k*10
+}
+
+// addHistory records a run in history, treating the light border as part of
+// a first light run.
+func (m *matrix) addHistory(run int, history *[7]int) {
+	if history[0] == 0 {
+		run += m.size
+	}
+	copy(history[1:], history[:6])
+	history[0] = run
+}
+
+// finderPatterns counts the 1:1:3:1:1 patterns, with four light modules on
+// one side, ending at the latest run of history.
+func finderPatterns(history *[7]int) int {
+	n := history[1]
+	core := n > 0 && history[2] == n && history[3] == n*3 &&
+		history[4] == n && history[5] == n
+	count := 0
+	if core && history[0] >= n*4 && history[6] >= n {
+		count++
+	}
+	if core && history[6] >= n*4 && history[0] >= n {
+		count++
+	}
+	return count
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
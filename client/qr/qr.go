@@ -0,0 +1,109 @@
+// Package qr renders payment URIs as QR codes, for example to show a deposit
+// address created with CreateAddress on a checkout page:
+//
+//	addr, err := cl.CreateAddress(accountID)
+//	...
+//	code, err := qr.Payment(client.PaymentURI{Address: addr, Amount: 50000})
+//	...
+//	code.WritePNG(w, 8)
+//
+// Codes are encoded in byte mode with error correction level M, which
+// recovers from up to 15% of the code being damaged or obscured, in the
+// smallest of versions 1 to 10 that fits. That holds up to 213 bytes, enough
+// for a URI with an address, amount, label and short message.
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/rtwire/go/client"
+)
+
+// ErrTooLong is returned from Encode for text that doesn't fit in a version 10
+// code.
+var ErrTooLong = errors.New("qr: text too long")
+
+// quietZone is the width, in modules, of the light border around a code.
+const quietZone = 4
+
+// Code is a QR code.
+type Code struct {
+	size    int
+	modules []bool // dark modules, row by row
+}
+
+// Payment returns the QR code of uri.
+func Payment(uri client.PaymentURI) (*Code, error) {
+	return Encode(uri.String())
+}
+
+// Size returns the width and height of c in modules, excluding the quiet
+// zone.
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Dark reports whether the module at column x and row y of c is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y*c.size+x]
+}
+
+// Image returns c with its quiet zone, drawn scale pixels per module.
+func (c *Code) Image(scale int) image.Image {
+	if scale < 1 {
+		scale = 1
+	}
+	n := (c.size + 2*quietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, n, n),
+		color.Palette{color.White, color.Black})
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if !c.Dark(x, y) {
+				continue
+			}
+			px, py := (x+quietZone)*scale, (y+quietZone)*scale
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(px+dx, py+dy, 1)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG writes c to w as a PNG image, scale pixels per module.
+func (c *Code) WritePNG(w io.Writer, scale int) error {
+	return png.Encode(w, c.Image(scale))
+}
+
+// WriteSVG writes c to w as an SVG image, scale pixels per module. The image
+// has a viewBox, so it can also be scaled with CSS.
+func (c *Code) WriteSVG(w io.Writer, scale int) error {
+	if scale < 1 {
+		scale = 1
+	}
+	n := c.size + 2*quietZone
+	var path strings.Builder
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.Dark(x, y) {
+				fmt.Fprintf(&path, "M%d,%dh1v1h-1z", x+quietZone,
+					y+quietZone)
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" `+
+		`width="%d" height="%d" viewBox="0 0 %d %d" `+
+		`shape-rendering="crispEdges">`+
+		`<rect width="100%%" height="100%%" fill="#fff"/>`+
+		`<path d="%s" fill="#000"/></svg>`,
+		n*scale, n*scale, n, n, path.String())
+	return err
+}
@@ -0,0 +1,85 @@
+package qr_test
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/qr"
+)
+
+func TestEncode(t *testing.T) {
+
+	tests := []struct {
+		length int
+		size   int
+	}{
+		{0, 21},
+		{14, 21}, // the most version 1 holds
+		{15, 25},
+		{100, 41},
+		{213, 57}, // the most version 10 holds
+	}
+	for _, test := range tests {
+		code, err := qr.Encode(strings.Repeat("a", test.length))
+		if err != nil {
+			t.Fatal(test.length, err)
+		}
+		if code.Size() != test.size {
+			t.Fatal("unexpected size", test.length, code.Size())
+		}
+		// Each corner but the bottom right has a finder pattern, whose
+		// centre is dark and ring of light modules surrounds it.
+		n := code.Size()
+		for _, c := range [][2]int{{3, 3}, {n - 4, 3}, {3, n - 4}} {
+			if !code.Dark(c[0], c[1]) || code.Dark(c[0]+2, c[1]) ||
+				!code.Dark(c[0]+3, c[1]) {
+				t.Fatal("expected finder pattern at", c)
+			}
+		}
+	}
+
+	if _, err := qr.Encode(strings.Repeat("a",
+		214)); !errors.Is(err, qr.ErrTooLong) {
+		t.Fatal("expected text to be too long", err)
+	}
+}
+
+func TestPayment(t *testing.T) {
+
+	code, err := qr.Payment(client.PaymentURI{
+		Address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		Amount:  50000,
+		Label:   "Shop",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := code.WritePNG(&buf, 4); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The image includes a four module quiet zone on each side.
+	if w := img.Bounds().Dx(); w != (code.Size()+8)*4 {
+		t.Fatal("unexpected width", w)
+	}
+	if r, _, _, _ := img.At(4*4, 4*4).RGBA(); r != 0 {
+		t.Fatal("expected the top left module to be dark")
+	}
+
+	buf.Reset()
+	if err := code.WriteSVG(&buf, 4); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `viewBox="0 0 45 45"`) {
+		t.Fatal("unexpected SVG", buf.String()[:200])
+	}
+}
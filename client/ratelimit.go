@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter limits the rate of requests made to RTWire. Wait blocks until a
+// request may be made or ctx is done. *rate.Limiter from
+// golang.org/x/time/rate implements Limiter.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithLimiter makes every request wait on l before being sent. The limit
+// applies across all goroutines sharing the Client.
+func WithLimiter(l Limiter) ClientOption {
+	return func(c *client) {
+		c.limiter = l
+	}
+}
+
+// WithRateLimit limits requests to perSecond requests per second, allowing
+// bursts of up to one second's worth of requests. A perSecond of zero or less
+// removes any limit.
+func WithRateLimit(perSecond float64) ClientOption {
+	if !(perSecond > 0) {
+		return WithLimiter(nil)
+	}
+	return WithLimiter(newTokenBucket(perSecond))
+}
+
+// tokenBucket is a Limiter which adds tokens at a fixed rate up to a burst
+// size, with each request waiting for and taking one token.
+type tokenBucket struct {
+	interval time.Duration
+	burst    float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(perSecond float64) *tokenBucket {
+	burst := perSecond
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		interval: time.Duration(float64(time.Second) / perSecond),
+		burst:    burst,
+		tokens:   burst,
+		last:     time.Now(),
+	}
+}
+
+// Wait reserves a token and sleeps until it is available. If ctx is done
+// first the reservation is returned to the bucket.
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	delay := time.Duration(-b.tokens * float64(b.interval))
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,95 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+type countingLimiter struct {
+	n   int32
+	err error
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.n, 1)
+	return l.err
+}
+
+func TestWithLimiter(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"GET /fees/": `{"type": "fees", "payload": []}`,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	limiter := &countingLimiter{}
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithLimiter(limiter))
+
+	if _, err := cl.Fees(); err != nil {
+		t.Fatal(err)
+	}
+	if limiter.n != 1 {
+		t.Fatal("expected one wait", limiter.n)
+	}
+
+	limiter.err = errors.New("limited")
+	if _, err := cl.Fees(); err != limiter.err {
+		t.Fatal("expected limiter error", err)
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"GET /fees/": `{"type": "fees", "payload": []}`,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithRateLimit(100))
+
+	// The first 100 requests use the burst, the other 50 are limited to 100
+	// per second.
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 150; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cl.Fees(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond {
+		t.Fatal("requests were not rate limited", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cl.FeesContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatal("expected canceled", err)
+	}
+
+	// A rate of zero removes the limit rather than blocking every request.
+	cl = client.New(http.DefaultClient, url, "user", "pass",
+		client.WithRateLimit(0))
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := cl.FeesContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
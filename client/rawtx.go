@@ -0,0 +1,203 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+)
+
+var errRawTxEncoding = errors.New("malformed raw transaction")
+
+// RawTransaction is a bitcoin transaction as serialized on the network,
+// decoded by the client rather than by RTWire. See RawTransaction on Client.
+type RawTransaction struct {
+	Hash     string // hash computed from Hex
+	Hex      string // serialized transaction, including any witnesses
+	Version  int32
+	Inputs   []RawInput
+	Outputs  []RawOutput
+	LockTime uint32
+}
+
+// RawInput is an input of a RawTransaction spending output Index of the
+// transaction with Hash.
+type RawInput struct {
+	Hash     string
+	Index    uint32
+	Sequence uint32
+}
+
+// RawOutput is an output of a RawTransaction paying Value satoshi to the
+// locking Script.
+type RawOutput struct {
+	Value  Amount
+	Script []byte
+}
+
+// PaysTo reports whether o pays address, a P2PKH, P2SH or segwit address of
+// MainNet or a test network.
+func (o RawOutput) PaysTo(address string) bool {
+	script, err := outputScript(address)
+	return err == nil && bytes.Equal(o.Script, script)
+}
+
+// outputScript returns the locking script paying addr.
+func outputScript(addr string) ([]byte, error) {
+	if checkSegwit(addr) == nil {
+		_, data, _, _ := decodeBech32(addr)
+		program, _ := convertBits(data[1:], 5, 8)
+		op := data[0]
+		if op > 0 {
+			op += 0x50 // OP_1 to OP_16
+		}
+		return append([]byte{op, byte(len(program))}, program...), nil
+	}
+
+	payload, err := decodeBase58Check(addr)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 21 {
+		return nil, errAddressEncoding
+	}
+	hash := payload[1:]
+	switch payload[0] {
+	case MainNet.P2PKH, TestNet3.P2PKH:
+		// OP_DUP OP_HASH160 hash OP_EQUALVERIFY OP_CHECKSIG
+		script := append([]byte{0x76, 0xa9, 0x14}, hash...)
+		return append(script, 0x88, 0xac), nil
+	case MainNet.P2SH, TestNet3.P2SH:
+		// OP_HASH160 hash OP_EQUAL
+		return append(append([]byte{0xa9, 0x14}, hash...), 0x87), nil
+	}
+	return nil, errAddressEncoding
+}
+
+// decodeRawTransaction decodes the hex serialization of a bitcoin
+// transaction, with or without the segwit marker and witnesses.
+func decodeRawTransaction(s string) (RawTransaction, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return RawTransaction{}, errRawTxEncoding
+	}
+	r := &txReader{b: b}
+	tx := RawTransaction{Hex: s, Version: int32(r.uint32())}
+
+	// The hash commits to the transaction without the marker, flag and
+	// witnesses added by BIP 144.
+	segwit := len(b) > 6 && b[4] == 0 && b[5] == 1
+	if segwit {
+		r.bytes(2)
+	}
+	start := r.pos
+
+	tx.Inputs = make([]RawInput, r.count())
+	for i := range tx.Inputs {
+		tx.Inputs[i] = RawInput{
+			Hash:  reversedHex(r.bytes(32)),
+			Index: r.uint32(),
+		}
+		r.bytes(r.varInt())
+		tx.Inputs[i].Sequence = r.uint32()
+	}
+	tx.Outputs = make([]RawOutput, r.count())
+	for i := range tx.Outputs {
+		tx.Outputs[i].Value = Amount(r.uint64())
+		tx.Outputs[i].Script = append([]byte(nil), r.bytes(r.varInt())...)
+	}
+	end := r.pos
+
+	if segwit {
+		for range tx.Inputs {
+			for n := r.varInt(); n > 0 && r.err == nil; n-- {
+				r.bytes(r.varInt())
+			}
+		}
+	}
+	tx.LockTime = r.uint32()
+	if r.err != nil || r.pos != len(b) {
+		return RawTransaction{}, errRawTxEncoding
+	}
+
+	stripped := append(append(b[:4:4], b[start:end]...), b[len(b)-4:]...)
+	first := sha256.Sum256(stripped)
+	second := sha256.Sum256(first[:])
+	tx.Hash = reversedHex(second[:])
+	return tx, nil
+}
+
+// reversedHex encodes b in reverse order, as bitcoin displays hashes.
+func reversedHex(b []byte) string {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[len(b)-1-i] = b[i]
+	}
+	return hex.EncodeToString(r)
+}
+
+// txReader reads the fields of a serialized transaction, recording the first
+// read past its end in err.
+type txReader struct {
+	b   []byte
+	pos int
+	err error
+}
+
+func (r *txReader) bytes(n uint64) []byte {
+	if r.err != nil || n > uint64(len(r.b)-r.pos) {
+		r.err = errRawTxEncoding
+		return nil
+	}
+	b := r.b[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return b
+}
+
+func (r *txReader) uint32() uint32 {
+	b := r.bytes(4)
+	if r.err != nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (r *txReader) uint64() uint64 {
+	b := r.bytes(8)
+	if r.err != nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+func (r *txReader) varInt() uint64 {
+	b := r.bytes(1)
+	if r.err != nil {
+		return 0
+	}
+	switch b[0] {
+	case 0xfd:
+		if b := r.bytes(2); r.err == nil {
+			return uint64(binary.LittleEndian.Uint16(b))
+		}
+	case 0xfe:
+		return uint64(r.uint32())
+	case 0xff:
+		return r.uint64()
+	default:
+		return uint64(b[0])
+	}
+	return 0
+}
+
+// count reads the number of inputs or outputs, bounded by the bytes left so
+// that a corrupt count cannot allocate more than the transaction's size.
+func (r *txReader) count() int {
+	n := r.varInt()
+	if n > uint64(len(r.b)-r.pos) {
+		r.err = errRawTxEncoding
+		return 0
+	}
+	return int(n)
+}
@@ -0,0 +1,100 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+// rawTxHex spends output 1 of rawTxPrev and pays 5000 satoshi to a P2PKH
+// address and 120000 satoshi to a P2WPKH address, with a segwit witness.
+const (
+	rawTxHex = "02000000000101000102030405060708090a0b0c0d0e0f10111213141516" +
+		"1718191a1b1c1d1e1f0100000000fdffffff0288130000000000001976a914" +
+		"62e907b15cbf27d5425399ebf6f0fb50ebb88f1888acc0d401000000000016" +
+		"0014751e76e8199196d454941c45d1b3a323f1433bd60202aabb01cc00350c00"
+	rawTxHash = "e72c04b586323e51a9bd368268aa2af9" +
+		"7319eb1f9719c9303e5a940f8b3d5356"
+	rawTxPrev = "1f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100"
+)
+
+func TestRawTransaction(t *testing.T) {
+
+	// The hex served for each hash.
+	served := map[string]string{
+		rawTxHash:               rawTxHex,
+		strings.Repeat("0", 64): strings.Replace(rawTxHex, "8813", "8913", 1),
+		strings.Repeat("1", 64): rawTxHex[:40],
+	}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			hash := strings.TrimPrefix(r.URL.Path,
+				"/v1/mainnet/rawtransactions/")
+			hex, ok := served[hash]
+			if !ok {
+				fmt.Fprint(w, `{"type": "rawTransactions", "payload": []}`)
+				return
+			}
+			fmt.Fprintf(w, `{"type": "rawTransactions",
+				"payload": [{"hex": %q}]}`, hex)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	tx, err := cl.RawTransaction(strings.ToUpper(rawTxHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Hash != rawTxHash || tx.Version != 2 || tx.LockTime != 800000 {
+		t.Fatalf("unexpected transaction %+v", tx)
+	}
+	if len(tx.Inputs) != 1 || tx.Inputs[0].Hash != rawTxPrev ||
+		tx.Inputs[0].Index != 1 || tx.Inputs[0].Sequence != 0xfffffffd {
+		t.Fatalf("unexpected inputs %+v", tx.Inputs)
+	}
+	if len(tx.Outputs) != 2 ||
+		tx.Outputs[0].Value != 5000 || tx.Outputs[1].Value != 120000 {
+		t.Fatalf("unexpected outputs %+v", tx.Outputs)
+	}
+
+	tests := []struct {
+		output  int
+		address string
+		pays    bool
+	}{
+		{0, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		{0, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false},
+		{1, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", true},
+		{1, "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", true},
+		{1, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", false},
+		{1, "not an address", false},
+	}
+	for _, test := range tests {
+		if got := tx.Outputs[test.output].PaysTo(test.address); got !=
+			test.pays {
+			t.Errorf("output %d paying %s: got %v", test.output,
+				test.address, got)
+		}
+	}
+
+	// A transaction must hash to the hash requested and decode fully.
+	if _, err := cl.RawTransaction(strings.Repeat("0", 64)); err == nil ||
+		!strings.Contains(err.Error(), "hashes to") {
+		t.Fatal("expected hash mismatch", err)
+	}
+	if _, err := cl.RawTransaction(strings.Repeat("1", 64)); err == nil {
+		t.Fatal("expected truncated transaction error")
+	}
+	if _, err := cl.RawTransaction(strings.Repeat("2", 64)); !errors.Is(err,
+		client.ErrNotFound) {
+		t.Fatal("expected ErrNotFound", err)
+	}
+}
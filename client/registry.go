@@ -0,0 +1,88 @@
+package client
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Registry holds the resources shared by the clients a process creates, for
+// example one per tenant: the http.Client, and so its connection pool, the
+// middleware every client is given, such as a metrics.Collector, and named
+// rate limiters. It is safe for concurrent use.
+//
+// Tenants are isolated from each other by giving them limiters of different
+// names, and share a limit by using the same name:
+//
+//	reg := client.NewRegistry(nil)
+//	reg.Use(collector.Middleware())
+//	cl := reg.New(client.MainNetURL, user, pass,
+//		reg.RateLimit("tenant-"+id, 5))
+type Registry struct {
+	client *http.Client
+
+	mu         sync.Mutex
+	middleware []Middleware
+	limiters   map[string]Limiter
+}
+
+// DefaultRegistry is a Registry for processes that need only one.
+var DefaultRegistry = NewRegistry(nil)
+
+// NewRegistry creates a Registry whose clients send their requests with c, or
+// a new http.Client if c is nil.
+func NewRegistry(c *http.Client) *Registry {
+	if c == nil {
+		c = &http.Client{}
+	}
+	return &Registry{client: c, limiters: map[string]Limiter{}}
+}
+
+// Use adds middleware to the clients created by r from now on, after the
+// middleware added before it.
+func (r *Registry) Use(middleware ...Middleware) {
+	r.mu.Lock()
+	r.middleware = append(r.middleware, middleware...)
+	r.mu.Unlock()
+}
+
+// Limiter returns the limiter named name, or l if there is none yet, in which
+// case l is registered under name. Clients given the same limiter share its
+// limit.
+func (r *Registry) Limiter(name string, l Limiter) Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.limiters[name]; ok {
+		return existing
+	}
+	r.limiters[name] = l
+	return l
+}
+
+// RateLimit is WithRateLimit for the limiter named name, which is created
+// with a limit of perSecond requests per second when first used. Later uses
+// of name share that limiter whatever their perSecond.
+func (r *Registry) RateLimit(name string, perSecond float64) ClientOption {
+	r.mu.Lock()
+	l, ok := r.limiters[name]
+	r.mu.Unlock()
+	if !ok {
+		var bucket Limiter
+		if perSecond > 0 {
+			bucket = newTokenBucket(perSecond)
+		}
+		l = r.Limiter(name, bucket)
+	}
+	return WithLimiter(l)
+}
+
+// New creates a client as New does, sending requests with the registry's
+// http.Client and given its middleware before options.
+func (r *Registry) New(url, user, pass string,
+	options ...ClientOption) Client {
+	r.mu.Lock()
+	middleware := append([]Middleware(nil), r.middleware...)
+	r.mu.Unlock()
+
+	opts := []ClientOption{WithMiddleware(middleware...)}
+	return New(r.client, url, user, pass, append(opts, options...)...)
+}
@@ -0,0 +1,63 @@
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestRegistry(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"GET /fees/": `{"type": "fees", "payload": []}`,
+	})
+	defer server.Close()
+
+	var sent int32
+	hc := &http.Client{Transport: client.RoundTripperFunc(
+		func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&sent, 1)
+			return http.DefaultTransport.RoundTrip(r)
+		})}
+	reg := client.NewRegistry(hc)
+
+	var seen int32
+	reg.Use(func(next client.RoundTripperFunc) client.RoundTripperFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&seen, 1)
+			return next(r)
+		}
+	})
+
+	shared := &countingLimiter{}
+	if l := reg.Limiter("shared", shared); l != shared {
+		t.Fatal("expected the limiter registered")
+	}
+	isolated := &countingLimiter{}
+	reg.Limiter("isolated", isolated)
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	tenants := []client.Client{
+		reg.New(url, "a", "pass", reg.RateLimit("shared", 1)),
+		reg.New(url, "b", "pass", reg.RateLimit("shared", 100)),
+		reg.New(url, "c", "pass", reg.RateLimit("isolated", 1)),
+	}
+	for _, cl := range tenants {
+		if _, err := cl.Fees(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if shared.n != 2 || isolated.n != 1 {
+		t.Fatal("expected limiters shared by name", shared.n, isolated.n)
+	}
+	if sent != 3 || seen != 3 {
+		t.Fatal("expected the http.Client and middleware shared", sent, seen)
+	}
+	if reg.Limiter("shared", &countingLimiter{}) != shared {
+		t.Fatal("expected the first limiter kept")
+	}
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader is the header carrying the ID that correlates a request with
+// RTWire's records of it.
+const RequestIDHeader = "X-Request-ID"
+
+// canonicalRequestIDHeader is the canonical form of RequestIDHeader.
+var canonicalRequestIDHeader = http.CanonicalHeaderKey(RequestIDHeader)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context that makes requests made with it send id as
+// their X-Request-ID, for example to reuse the ID of an incoming request.
+// Otherwise a random ID is generated for each request.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID set with WithRequestID, or an
+// empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestID returns the request ID in ctx, or a new random ID.
+func requestID(ctx context.Context) string {
+	if id := RequestIDFromContext(ctx); id != "" {
+		return id
+	}
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
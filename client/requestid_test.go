@@ -0,0 +1,50 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestRequestID(t *testing.T) {
+
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			ids = append(ids, r.Header.Get(client.RequestIDHeader))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"type": "errors",
+				"payload": [{"message": "test error"}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	var apiErr *client.APIError
+	for i := 0; i < 2; i++ {
+		_, err := cl.Fees()
+		if !errors.As(err, &apiErr) {
+			t.Fatal("expected APIError", err)
+		}
+		if len(ids[i]) != 32 || apiErr.RequestID != ids[i] {
+			t.Fatal("unexpected request ID", ids[i], apiErr.RequestID)
+		}
+	}
+	if ids[0] == ids[1] {
+		t.Fatal("expected a new ID per request")
+	}
+
+	ctx := client.WithRequestID(context.Background(), "support-123")
+	_, err := cl.FeesContext(ctx)
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "support-123" ||
+		ids[2] != "support-123" {
+		t.Fatal("expected request ID from context", ids[2], err)
+	}
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	retryBaseDelay = 100 * time.Millisecond
+	retryMaxDelay  = 2 * time.Second
+)
+
+// WithRetry retries requests up to max times when they fail with a network
+// error, a 5xx response or a 429 Too Many Requests response. Retries wait with
+// exponential backoff, or for as long as the response's Retry-After header
+// asks. Only GET, PUT and DELETE requests are retried as POST requests, such
+// as CreateAccount, are not idempotent. A retried Transfer or Debit whose
+// earlier attempt was applied, but whose response was lost, is answered with
+// ErrTxIDUsed by RTWire; the transaction is then fetched and the call succeeds
+// if it matches the request. ErrTxIDUsed is only returned if the transaction
+// differs, and the error fetching it if it could not be fetched. Retries can
+// be disabled for a single call with NoRetry.
+func WithRetry(max int) ClientOption {
+	return func(c *client) {
+		c.retries = max
+	}
+}
+
+// withRetries returns a copy of ctx recording that a request has been retried
+// n times.
+func withRetries(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, retriesKey{}, n)
+}
+
+// sendRetry sends req, retrying it as configured by WithRetry.
+func (c *client) sendRetry(req *http.Request) (*http.Response, []byte,
+	error) {
+
+	for n := 0; ; n++ {
+		resp, body, err := c.sendOnce(req)
+		if n >= c.retries || !shouldRetry(req, resp, err) {
+			return resp, body, err
+		}
+
+		delay := retryBaseDelay << uint(n)
+		if delay > retryMaxDelay {
+			delay = retryMaxDelay
+		}
+		if resp != nil {
+			after := parseRetryAfter(resp.Header.Get("Retry-After"))
+			if after > 0 {
+				delay = after
+			}
+		}
+		ctx := req.Context()
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, nil, ctx.Err()
+		case <-t.C:
+		}
+
+		callOptionsFromContext(ctx).retried = true
+		next := req.Clone(withRetries(ctx, n+1))
+		if req.GetBody != nil {
+			if next.Body, err = req.GetBody(); err != nil {
+				return nil, nil, err
+			}
+		}
+		req = next
+	}
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	switch req.Method {
+	case "GET", "PUT", "DELETE":
+	default:
+		return false
+	}
+	if err != nil {
+		return !isContextErr(err) && !errors.Is(err, ErrCircuitOpen)
+	}
+	return resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusTooManyRequests
+}
+
+// resolveTxIDUsed handles err from a Transfer or Debit request for txID. If the
+// request was retried and failed with ErrTxIDUsed an earlier attempt may have
+// been applied, so the transaction is fetched and returned if match reports it
+// is the one requested. Otherwise err is returned.
+func (c *client) resolveTxIDUsed(ctx context.Context, req *http.Request,
+	err error, txID int64, match func(tx Transaction) bool) (Transaction,
+	error) {
+
+	if !errors.Is(err, ErrTxIDUsed) ||
+		!callOptionsFromContext(req.Context()).retried {
+		return Transaction{}, err
+	}
+	tx, lookupErr := c.TransactionContext(ctx, txID)
+	if lookupErr != nil {
+		return Transaction{}, lookupErr
+	}
+	if !match(tx) {
+		return Transaction{}, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,156 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestWithRetry(t *testing.T) {
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests < 3 {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "fees", "payload": []}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithRetry(2))
+
+	if _, err := cl.Fees(); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Fatal("expected two retries", requests)
+	}
+
+	requests = 0
+	if _, err := cl.Fees(client.NoRetry()); err == nil {
+		t.Fatal("expected error without retry")
+	}
+	if requests != 1 {
+		t.Fatal("expected a single request", requests)
+	}
+
+	// POST requests aren't retried.
+	requests = 0
+	if err := cl.CreateHook("https://example.com"); err == nil {
+		t.Fatal("expected error")
+	}
+	if requests != 1 {
+		t.Fatal("expected a single request", requests)
+	}
+}
+
+func TestCallOptions(t *testing.T) {
+
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+			if r.URL.Path == "/v1/mainnet/fees/" {
+				time.Sleep(100 * time.Millisecond)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "hooks", "payload": []}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithBaseHeader("X-Tenant", "a"))
+
+	if _, err := cl.Hooks(client.CallHeader("X-Tenant", "b"),
+		client.CallHeader("Accept", "text/plain")); err != nil {
+		t.Fatal(err)
+	}
+	if v := header.Get("X-Tenant"); v != "b" {
+		t.Fatal("expected call header", v)
+	}
+	if v := header.Get("Accept"); v == "text/plain" {
+		t.Fatal("expected Accept not to be overridden")
+	}
+
+	if _, err := cl.Hooks(); err != nil {
+		t.Fatal(err)
+	}
+	if v := header.Get("X-Tenant"); v != "a" {
+		t.Fatal("expected client header", v)
+	}
+
+	_, err := cl.Fees(client.CallTimeout(10 * time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected deadline exceeded", err)
+	}
+}
+
+func TestRetryTxIDUsed(t *testing.T) {
+
+	var (
+		puts  int
+		value client.Amount = 10
+	)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.Method == "GET" {
+				fmt.Fprintf(w, `{"type": "transactions", "payload": [{
+					"id": 5, "type": "debit", "fromAccountID": 1,
+					"value": %d, "txHashes": ["ab"]}]}`, value)
+				return
+			}
+			puts++
+			if puts%2 == 1 {
+				// The debit is applied but its response is lost.
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"type": "errors",
+				"payload": [{"message": "txid used"}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithRetry(1))
+
+	const addr = "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
+	tx, err := cl.Debit(5, 1, addr, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxHashes) != 1 || tx.TxHashes[0] != "ab" {
+		t.Fatal("expected the applied debit", tx)
+	}
+
+	// A different transaction under the ID is still reported.
+	value = 11
+	if _, err := cl.Debit(5, 1, addr, 10); !errors.Is(err,
+		client.ErrTxIDUsed) {
+		t.Fatal("expected txid used", err)
+	}
+
+	// Without a retry ErrTxIDUsed is returned as is.
+	puts = 1
+	value = 10
+	if _, err := cl.Debit(5, 1, addr, 10); !errors.Is(err,
+		client.ErrTxIDUsed) {
+		t.Fatal("expected txid used", err)
+	}
+}
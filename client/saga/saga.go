@@ -0,0 +1,166 @@
+// Package saga runs multi-step payment flows, such as debiting an external
+// address, waiting for the debit to confirm, crediting an internal account
+// and notifying the customer, as a saga. Each step has a compensating step
+// that undoes it. If a step fails the steps already completed are compensated
+// in reverse order.
+//
+// Progress is saved to a Store before and after every step, so a flow
+// interrupted by a crash is resumed from where it stopped by calling Run again
+// with the same ID. A step may therefore be run more than once and must be
+// idempotent; RTWire transaction IDs make transfers and debits naturally so
+// when the ID is created in an earlier step and kept in the state's Data.
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned from Store.Load when no state is saved for an ID.
+var ErrNotFound = errors.New("saga not found")
+
+// Status is the progress of a saga.
+type Status string
+
+const (
+	// Running means steps are being run.
+	Running Status = "running"
+
+	// Completed means every step succeeded.
+	Completed Status = "completed"
+
+	// Compensating means a step failed and completed steps are being undone.
+	Compensating Status = "compensating"
+
+	// Compensated means a step failed and every completed step was undone.
+	Compensated Status = "compensated"
+)
+
+// State is the saved progress of one run of a saga.
+type State struct {
+	ID     string `json:"id"`
+	Status Status `json:"status"`
+
+	// Step is the index of the next step to run while Running, or the number
+	// of steps still to be compensated while Compensating.
+	Step int `json:"step"`
+
+	// Data is shared between steps, for example to pass a transaction ID
+	// created by one step to the next. Changes made by a step are saved with
+	// its progress.
+	Data map[string]string `json:"data"`
+
+	// FailedStep and Err are the name and error message of the step that
+	// failed, if any.
+	FailedStep string `json:"failedStep,omitempty"`
+	Err        string `json:"err,omitempty"`
+}
+
+// Step is one step of a saga. Compensate may be nil if the step has nothing to
+// undo.
+type Step struct {
+	Name       string
+	Do         func(ctx context.Context, state *State) error
+	Compensate func(ctx context.Context, state *State) error
+}
+
+// Store saves saga state.
+type Store interface {
+	Save(ctx context.Context, state State) error
+	Load(ctx context.Context, id string) (State, error)
+}
+
+// Saga is a sequence of steps whose progress is saved in Store.
+type Saga struct {
+	Steps []Step
+	Store Store
+}
+
+// StepError is returned from Run when a step failed and the completed steps
+// were compensated.
+type StepError struct {
+	Step string
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("saga step %s: %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Run runs the saga identified by id. If state for id has been saved the saga
+// is resumed, otherwise it is started with data. Run returns the final state,
+// with a *StepError if a step failed and was compensated. Other errors, such
+// as a failure to save progress or to compensate a step, leave the saga
+// unfinished to be resumed by a later call to Run.
+func (s *Saga) Run(ctx context.Context, id string,
+	data map[string]string) (State, error) {
+
+	state, err := s.Store.Load(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		if data == nil {
+			data = map[string]string{}
+		}
+		state = State{ID: id, Status: Running, Data: data}
+		err = s.Store.Save(ctx, state)
+	}
+	if err != nil {
+		return state, err
+	}
+
+	var stepErr error
+	for state.Status == Running && state.Step < len(s.Steps) {
+		step := s.Steps[state.Step]
+		if err := step.Do(ctx, &state); err != nil {
+			stepErr = err
+			state.Status = Compensating
+			state.FailedStep = step.Name
+			state.Err = err.Error()
+		} else {
+			state.Step++
+		}
+		if err := s.Store.Save(ctx, state); err != nil {
+			return state, err
+		}
+	}
+	if state.Status == Running {
+		state.Status = Completed
+		if err := s.Store.Save(ctx, state); err != nil {
+			return state, err
+		}
+	}
+
+	for state.Status == Compensating && state.Step > 0 {
+		step := s.Steps[state.Step-1]
+		if step.Compensate != nil {
+			if err := step.Compensate(ctx, &state); err != nil {
+				return state, fmt.Errorf("saga compensate %s: %w",
+					step.Name, err)
+			}
+		}
+		state.Step--
+		if err := s.Store.Save(ctx, state); err != nil {
+			return state, err
+		}
+	}
+	if state.Status == Compensating {
+		state.Status = Compensated
+		if err := s.Store.Save(ctx, state); err != nil {
+			return state, err
+		}
+	}
+
+	if state.Status == Compensated {
+		// The original error is only available in the run in which the
+		// step failed, so a resumed run reports its message.
+		if stepErr == nil {
+			stepErr = errors.New(state.Err)
+		}
+		return state, &StepError{Step: state.FailedStep, Err: stepErr}
+	}
+	return state, nil
+}
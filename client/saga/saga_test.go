@@ -0,0 +1,123 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rtwire/go/client/saga"
+)
+
+// steps returns steps that record their calls in log, with step fail
+// returning err.
+func steps(log *[]string, fail string, err error) []saga.Step {
+	var steps []saga.Step
+	for _, name := range []string{"debit", "confirm", "credit", "notify"} {
+		name := name
+		steps = append(steps, saga.Step{
+			Name: name,
+			Do: func(ctx context.Context, state *saga.State) error {
+				*log = append(*log, name)
+				if name == fail {
+					return err
+				}
+				state.Data[name] = "done"
+				return nil
+			},
+			Compensate: func(ctx context.Context, state *saga.State) error {
+				*log = append(*log, "undo "+name)
+				delete(state.Data, name)
+				return nil
+			},
+		})
+	}
+	return steps
+}
+
+func TestSagaCompleted(t *testing.T) {
+
+	var log []string
+	s := &saga.Saga{Steps: steps(&log, "", nil), Store: saga.NewMemoryStore()}
+
+	state, err := s.Run(context.Background(), "payout-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != saga.Completed || len(state.Data) != 4 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	// Running a completed saga again does nothing.
+	if _, err := s.Run(context.Background(), "payout-1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(log) != "[debit confirm credit notify]" {
+		t.Fatal("unexpected steps", log)
+	}
+}
+
+func TestSagaCompensated(t *testing.T) {
+
+	var log []string
+	errCredit := errors.New("credit failed")
+	s := &saga.Saga{
+		Steps: steps(&log, "credit", errCredit),
+		Store: saga.DirStore{Dir: t.TempDir()},
+	}
+
+	state, err := s.Run(context.Background(), "payout/2", nil)
+	var stepErr *saga.StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "credit" ||
+		!errors.Is(err, errCredit) {
+		t.Fatal("expected credit step error", err)
+	}
+	if state.Status != saga.Compensated || len(state.Data) != 0 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+	expected := "[debit confirm credit undo confirm undo debit]"
+	if fmt.Sprint(log) != expected {
+		t.Fatal("unexpected steps", log)
+	}
+
+	// Reloading the saga reports the same failure.
+	_, err = s.Run(context.Background(), "payout/2", nil)
+	if !errors.As(err, &stepErr) || err.Error() !=
+		"saga step credit: credit failed" {
+		t.Fatal("expected saved step error", err)
+	}
+}
+
+func TestSagaResume(t *testing.T) {
+
+	store := saga.DirStore{Dir: t.TempDir()}
+	ctx := context.Background()
+
+	// Simulate a crash after the first two steps completed.
+	err := store.Save(ctx, saga.State{
+		ID:     "payout-3",
+		Status: saga.Running,
+		Step:   2,
+		Data:   map[string]string{"debit": "done", "confirm": "done"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var log []string
+	s := &saga.Saga{Steps: steps(&log, "", nil), Store: store}
+	state, err := s.Run(ctx, "payout-3", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(log) != "[credit notify]" {
+		t.Fatal("expected only the remaining steps to run", log)
+	}
+	if state.Status != saga.Completed || len(state.Data) != 4 {
+		t.Fatalf("unexpected state %+v", state)
+	}
+
+	if _, err := store.Load(ctx, "unknown"); err != saga.ErrNotFound {
+		t.Fatal("expected not found", err)
+	}
+}
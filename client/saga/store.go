@@ -0,0 +1,111 @@
+package saga
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// MemoryStore is a Store that keeps state in memory. It is intended for tests
+// as state does not survive a restart.
+type MemoryStore struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: map[string]State{}}
+}
+
+// Save saves a copy of state.
+func (m *MemoryStore) Save(ctx context.Context, state State) error {
+	state.Data = copyData(state.Data)
+	m.mu.Lock()
+	m.states[state.ID] = state
+	m.mu.Unlock()
+	return nil
+}
+
+// Load returns the state saved for id.
+func (m *MemoryStore) Load(ctx context.Context, id string) (State, error) {
+	m.mu.Lock()
+	state, ok := m.states[id]
+	m.mu.Unlock()
+	if !ok {
+		return State{}, ErrNotFound
+	}
+	state.Data = copyData(state.Data)
+	return state, nil
+}
+
+func copyData(data map[string]string) map[string]string {
+	c := make(map[string]string, len(data))
+	for k, v := range data {
+		c[k] = v
+	}
+	return c
+}
+
+// DirStore is a Store that saves each saga's state as a JSON file in a
+// directory. Files are replaced atomically so a crash while saving leaves the
+// previous state intact.
+type DirStore struct {
+	Dir string
+}
+
+// path returns the file for id, encoding it so that any ID is a valid file
+// name.
+func (d DirStore) path(id string) string {
+	name := base64.RawURLEncoding.EncodeToString([]byte(id))
+	return filepath.Join(d.Dir, name+".json")
+}
+
+// Save writes state to its file.
+func (d DirStore) Save(ctx context.Context, state State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(d.Dir, ".saga-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), d.path(state.ID))
+}
+
+// Load reads the state saved for id.
+func (d DirStore) Load(ctx context.Context, id string) (State, error) {
+	data, err := os.ReadFile(d.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return State{}, ErrNotFound
+	}
+	if err != nil {
+		return State{}, err
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return State{}, err
+	}
+	return state, nil
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// SelfTest checks the RTWire write path end to end by transferring one
+// satoshi from accountA to accountB and back again using fresh transaction
+// IDs, then reading both transactions back. accountA must hold at least one
+// satoshi. On success both balances are as they were before the test,
+// barring other activity on the accounts.
+func SelfTest(ctx context.Context, c Client, accountA, accountB int64) error {
+	txIDs, err := c.CreateTransactionIDsContext(ctx, 2)
+	if err != nil {
+		return fmt.Errorf("self test: create transaction IDs: %w", err)
+	}
+
+	legs := []struct {
+		txID     int64
+		from, to int64
+	}{
+		{txIDs[0], accountA, accountB},
+		{txIDs[1], accountB, accountA},
+	}
+
+	for _, leg := range legs {
+		_, err := c.TransferContext(ctx, leg.txID, leg.from, leg.to, 1)
+		if err != nil {
+			return fmt.Errorf("self test: transfer %d from %d to %d: %w",
+				leg.txID, leg.from, leg.to, err)
+		}
+
+		tx, err := c.WaitForTransaction(ctx, leg.txID)
+		if err != nil {
+			return fmt.Errorf("self test: read transaction %d: %w",
+				leg.txID, err)
+		}
+		if tx.FromAccountID != leg.from || tx.ToAccountID != leg.to ||
+			tx.Value != 1 {
+			return fmt.Errorf("self test: transaction %d does not match "+
+				"transfer of 1 from %d to %d: %+v", leg.txID, leg.from,
+				leg.to, tx)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestSelfTest(t *testing.T) {
+
+	var (
+		mu   sync.Mutex
+		txns = map[int64]string{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+
+			switch r.Method {
+			case "POST":
+				fmt.Fprint(w, `{"type": "transactions",
+					"payload": [{"id": 1}, {"id": 2}]}`)
+			case "PUT":
+				var tx struct {
+					ID            int64 `json:"id"`
+					FromAccountID int64 `json:"fromAccountID"`
+					ToAccountID   int64 `json:"toAccountID"`
+					Value         int64 `json:"value"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&tx); err != nil {
+					t.Error(err)
+				}
+				txns[tx.ID] = fmt.Sprintf(`{"id": %d, "fromAccountID": %d,
+					"toAccountID": %d, "value": %d}`, tx.ID,
+					tx.FromAccountID, tx.ToAccountID, tx.Value)
+			case "GET":
+				var id int64
+				fmt.Sscanf(r.URL.Path, "/v1/mainnet/transactions/%d", &id)
+				tx, ok := txns[id]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				fmt.Fprintf(w, `{"type": "transactions", "payload": [%s]}`, tx)
+			}
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	if err := client.SelfTest(context.Background(), cl, 10, 11); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(txns) != 2 {
+		t.Fatal("expected two transfers", len(txns))
+	}
+}
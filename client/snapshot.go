@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+)
+
+// snapshotAttempts is the number of times AccountWithTransactions reads an
+// account before giving up with ErrInconsistentSnapshot.
+const snapshotAttempts = 3
+
+// AccountWithTransactions calls AccountWithTransactionsContext with a
+// background context.
+func (c *client) AccountWithTransactions(accountID int64, limit int) (
+	Account, []Transaction, error) {
+	return c.AccountWithTransactionsContext(context.Background(), accountID,
+		limit)
+}
+
+// AccountWithTransactionsContext returns an account and its most recent
+// transactions as of a single point in time. RTWire has no snapshot endpoint
+// so the account is read before and after listing its transactions. The read
+// is accepted if the balance did not change in between and the balance
+// recorded by the latest transaction matches it, otherwise it is retried. If
+// no consistent read is made after three attempts ErrInconsistentSnapshot is
+// returned.
+func (c *client) AccountWithTransactionsContext(ctx context.Context,
+	accountID int64, limit int) (Account, []Transaction, error) {
+
+	for i := 0; i < snapshotAttempts; i++ {
+		before, err := c.AccountContext(ctx, accountID)
+		if err != nil {
+			return Account{}, nil, err
+		}
+
+		_, txns, err := c.AccountTransactionsContext(ctx, accountID,
+			Limit(limit))
+		if err != nil {
+			return Account{}, nil, err
+		}
+
+		after, err := c.AccountContext(ctx, accountID)
+		if err != nil {
+			return Account{}, nil, err
+		}
+
+		if before.Balance == after.Balance &&
+			latestBalanceMatches(accountID, after.Balance, txns) {
+			return after, txns, nil
+		}
+	}
+	return Account{}, nil, ErrInconsistentSnapshot
+}
+
+// latestBalanceMatches reports whether the most recent transaction in txns
+// left accountID with balance.
+func latestBalanceMatches(accountID int64, balance Amount,
+	txns []Transaction) bool {
+	if len(txns) == 0 {
+		return true
+	}
+
+	latest := txns[0]
+	for _, tx := range txns[1:] {
+		if tx.Created.After(latest.Created) {
+			latest = tx
+		}
+	}
+
+	switch accountID {
+	case latest.ToAccountID:
+		return latest.ToAccountBalance == balance
+	case latest.FromAccountID:
+		return latest.FromAccountBalance == balance
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package client_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestAccountWithTransactions(t *testing.T) {
+
+	// The account is credited between the first listing and the second
+	// account read.
+	balances := []int64{5, 10, 10, 10, 10}
+	listings := []string{
+		`[{"id": 1, "toAccountID": 1, "toAccountBalance": 5,
+			"created": "2020-01-01T00:00:00Z"}]`,
+		`[{"id": 1, "toAccountID": 1, "toAccountBalance": 5,
+			"created": "2020-01-01T00:00:00Z"},
+		  {"id": 2, "toAccountID": 1, "toAccountBalance": 10,
+			"created": "2020-01-02T00:00:00Z"}]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/v1/mainnet/accounts/1":
+				fmt.Fprintf(w, `{"type": "accounts",
+					"payload": [{"id": 1, "balance": %d}]}`, balances[0])
+				balances = balances[1:]
+			case "/v1/mainnet/accounts/1/transactions/":
+				fmt.Fprintf(w, `{"type": "transactions", "payload": %s}`,
+					listings[0])
+				listings = listings[1:]
+			}
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	acc, txns, err := cl.AccountWithTransactions(1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if acc.Balance != 10 || len(txns) != 2 {
+		t.Fatalf("expected retried snapshot %+v %+v", acc, txns)
+	}
+	if len(balances) != 1 {
+		t.Fatal("expected two attempts")
+	}
+}
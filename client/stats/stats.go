@@ -0,0 +1,136 @@
+// Package stats computes account growth metrics, such as the number of
+// accounts created each day or week and the total balance held over time.
+//
+// RTWire does not report when an account was created, so growth is derived
+// from snapshots of the account listing taken periodically, for example by a
+// daily job running "rtwire accountstats -record". An account is counted as
+// created in the period of the first snapshot that includes it.
+package stats
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// Snapshot is the account listing at a point in time.
+type Snapshot struct {
+	Time     time.Time        `json:"time"`
+	Accounts []client.Account `json:"accounts"`
+}
+
+// TakeSnapshot lists every account of c.
+func TakeSnapshot(ctx context.Context, c client.Client) (Snapshot, error) {
+	s := Snapshot{Time: time.Now()}
+	next, accs, err := c.AccountsContext(ctx)
+	for {
+		if err != nil {
+			return Snapshot{}, err
+		}
+		s.Accounts = append(s.Accounts, accs...)
+		if next == "" {
+			return s, nil
+		}
+		next, accs, err = c.AccountsContext(ctx, client.Next(next))
+	}
+}
+
+// WriteSnapshot appends s to w as a line of JSON.
+func WriteSnapshot(w io.Writer, s Snapshot) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// ReadSnapshots reads snapshots written by WriteSnapshot.
+func ReadSnapshots(r io.Reader) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var s Snapshot
+		if err := dec.Decode(&s); err == io.EOF {
+			return snapshots, nil
+		} else if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+}
+
+// Period is the length of time metrics are grouped by.
+type Period int
+
+const (
+	// Day groups metrics by UTC calendar day.
+	Day Period = iota
+
+	// Week groups metrics by week, starting on Monday in UTC.
+	Week
+)
+
+// start returns the start of the period containing t.
+func (p Period) start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if p == Week {
+		// Weekday counts from Sunday, weeks start on Monday.
+		day = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+// Point holds the metrics of one period.
+type Point struct {
+	// Start is the start of the period.
+	Start time.Time
+
+	// Created is the number of accounts first seen in the period. Accounts
+	// in the earliest snapshot are not counted as they may have been created
+	// at any time before it.
+	Created int
+
+	// Accounts is the cumulative number of accounts seen by the end of the
+	// period.
+	Accounts int
+
+	// Balance is the total balance, in satoshi, of the accounts in the last
+	// snapshot of the period.
+	Balance client.Amount
+}
+
+// Growth returns the metrics of each period, in order, that has at least one
+// snapshot. Periods without a snapshot are omitted rather than reported as
+// having no new accounts.
+func Growth(snapshots []Snapshot, period Period) []Point {
+	snapshots = append([]Snapshot(nil), snapshots...)
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+
+	var points []Point
+	seen := map[int64]bool{}
+	for i, s := range snapshots {
+		start := period.start(s.Time)
+		if len(points) == 0 || !points[len(points)-1].Start.Equal(start) {
+			points = append(points, Point{Start: start})
+		}
+		p := &points[len(points)-1]
+
+		p.Balance = 0
+		for _, acc := range s.Accounts {
+			p.Balance += acc.Balance
+			if seen[acc.ID] {
+				continue
+			}
+			seen[acc.ID] = true
+			if i > 0 {
+				p.Created++
+			}
+		}
+		p.Accounts = len(seen)
+	}
+	return points
+}
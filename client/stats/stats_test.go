@@ -0,0 +1,98 @@
+package stats_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/stats"
+)
+
+func TestGrowth(t *testing.T) {
+
+	// Wednesday 14 October 2026.
+	wed := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	snapshots := []stats.Snapshot{
+		{Time: wed.AddDate(0, 0, 1), Accounts: []client.Account{
+			{ID: 1, Balance: 10}, {ID: 2, Balance: 20}, {ID: 3, Balance: 5},
+		}},
+		{Time: wed, Accounts: []client.Account{{ID: 1, Balance: 10}}},
+		{Time: wed.Add(8 * time.Hour), Accounts: []client.Account{
+			{ID: 1, Balance: 10}, {ID: 2, Balance: 0},
+		}},
+		{Time: wed.AddDate(0, 0, 5), Accounts: []client.Account{
+			{ID: 1, Balance: 0}, {ID: 2, Balance: 20}, {ID: 3, Balance: 5},
+			{ID: 4, Balance: 1},
+		}},
+	}
+
+	day := func(d int) time.Time {
+		return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC)
+	}
+	days := stats.Growth(snapshots, stats.Day)
+	expected := []stats.Point{
+		{Start: day(14), Created: 1, Accounts: 2, Balance: 10},
+		{Start: day(15), Created: 1, Accounts: 3, Balance: 35},
+		{Start: day(19), Created: 1, Accounts: 4, Balance: 26},
+	}
+	if !reflect.DeepEqual(days, expected) {
+		t.Fatalf("unexpected daily growth %+v", days)
+	}
+
+	weeks := stats.Growth(snapshots, stats.Week)
+	expected = []stats.Point{
+		{Start: day(12), Created: 2, Accounts: 3, Balance: 35},
+		{Start: day(19), Created: 1, Accounts: 4, Balance: 26},
+	}
+	if !reflect.DeepEqual(weeks, expected) {
+		t.Fatalf("unexpected weekly growth %+v", weeks)
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("next") == "" {
+				fmt.Fprint(w, `{"type": "accounts", "next": "b",
+					"payload": [{"id": 1, "balance": 5}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"type": "accounts",
+				"payload": [{"id": 2, "balance": 7}]}`)
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	s, err := stats.TakeSnapshot(context.Background(), cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Accounts) != 2 || s.Accounts[1].ID != 2 {
+		t.Fatalf("expected both pages %+v", s.Accounts)
+	}
+
+	buf := &bytes.Buffer{}
+	for i := 0; i < 2; i++ {
+		if err := stats.WriteSnapshot(buf, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshots, err := stats.ReadSnapshots(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 2 || !snapshots[1].Time.Equal(s.Time) ||
+		!reflect.DeepEqual(snapshots[1].Accounts, s.Accounts) {
+		t.Fatalf("unexpected snapshots %+v", snapshots)
+	}
+}
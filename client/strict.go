@@ -0,0 +1,160 @@
+package client
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// UnknownField describes a field in an RTWire response that has no
+// corresponding field in the client's types. Type is the Go type being
+// decoded and Field the JSON key.
+type UnknownField struct {
+	Type  string
+	Field string
+}
+
+// UnknownFieldError is returned in strict mode when a response contains a
+// field the client does not know about.
+type UnknownFieldError struct {
+	UnknownField
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("unknown field %q in %s", e.Field, e.Type)
+}
+
+// WithStrictDecoding configures the client to fail with an *UnknownFieldError
+// when a JSON response contains fields its types do not declare, rather than
+// silently dropping them.
+func WithStrictDecoding() ClientOption {
+	return func(c *client) {
+		c.json.strict = true
+	}
+}
+
+// WithUnknownFieldHandler configures the client to call fn for every unknown
+// field found in JSON responses without failing the call, so that additions
+// to the API can be detected and logged. It can be combined with
+// WithStrictDecoding, in which case fn is called before the error is returned.
+func WithUnknownFieldHandler(fn func(UnknownField)) ClientOption {
+	return func(c *client) {
+		c.json.onUnknown = fn
+	}
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf(
+		(*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// fieldDecoders holds the package's types whose UnmarshalJSON decodes their
+// JSON fields as encoding/json would, only changing how values are parsed, so
+// their fields are still checked.
+var fieldDecoders = map[reflect.Type]bool{
+	reflect.TypeOf(Address{}):          true,
+	reflect.TypeOf(Transaction{}):      true,
+	reflect.TypeOf(TransactionEvent{}): true,
+}
+
+// checkFields reports the fields of payload that would be dropped when
+// decoding into v.
+func (j *jsonCodec) checkFields(payload []byte, v interface{}) error {
+	var generic interface{}
+	if err := json.Unmarshal(payload, &generic); err != nil {
+		return err
+	}
+
+	var first *UnknownField
+	walkFields(generic, reflect.TypeOf(v), func(f UnknownField) {
+		if j.onUnknown != nil {
+			j.onUnknown(f)
+		}
+		if first == nil {
+			first = &f
+		}
+	})
+	if j.strict && first != nil {
+		return &UnknownFieldError{*first}
+	}
+	return nil
+}
+
+func walkFields(value interface{}, t reflect.Type, report func(UnknownField)) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if !fieldDecoders[t] && (reflect.PtrTo(t).Implements(jsonUnmarshalerType) ||
+		reflect.PtrTo(t).Implements(textUnmarshalerType)) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if values, ok := value.([]interface{}); ok {
+			for _, elem := range values {
+				walkFields(elem, t.Elem(), report)
+			}
+		}
+	case reflect.Map:
+		if values, ok := value.(map[string]interface{}); ok {
+			for _, elem := range values {
+				walkFields(elem, t.Elem(), report)
+			}
+		}
+	case reflect.Struct:
+		values, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, elem := range values {
+			field, ok := fields[strings.ToLower(key)]
+			if !ok {
+				report(UnknownField{Type: t.String(), Field: key})
+				continue
+			}
+			walkFields(elem, field.Type, report)
+		}
+	}
+}
+
+// jsonFields returns the fields of struct type t keyed by their lower cased
+// JSON names, including those promoted from embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for key, f := range jsonFields(embedded) {
+					if _, ok := fields[key]; !ok {
+						fields[key] = f
+					}
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field
+	}
+	return fields
+}
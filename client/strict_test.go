@@ -0,0 +1,64 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestStrictDecoding(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"GET /accounts/1": `{"type": "accounts",
+			"payload": [{"id": 1, "balance": 2, "nickname": "new"}]}`,
+		"GET /accounts/2": `{"type": "accounts",
+			"payload": [{"ID": 2, "Balance": 3}]}`,
+		"GET /transactions/5": `{"type": "transactions",
+			"payload": [{"id": 5, "created": "2020-01-02T03:04:05Z",
+			"newField": "x"}]}`,
+	})
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+
+	var unknown []client.UnknownField
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithUnknownFieldHandler(func(f client.UnknownField) {
+			unknown = append(unknown, f)
+		}))
+
+	acc, err := cl.Account(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Balance != 2 {
+		t.Fatal("incorrect balance")
+	}
+	if len(unknown) != 1 || unknown[0].Field != "nickname" ||
+		unknown[0].Type != "client.Account" {
+		t.Fatalf("expected nickname to be reported %+v", unknown)
+	}
+
+	cl = client.New(http.DefaultClient, url, "user", "pass",
+		client.WithStrictDecoding())
+
+	_, err = cl.Account(1)
+	var ferr *client.UnknownFieldError
+	if !errors.As(err, &ferr) || ferr.Field != "nickname" {
+		t.Fatal("expected unknown field error", err)
+	}
+
+	// Transactions are checked despite decoding their own timestamps.
+	if _, err := cl.Transaction(5); !errors.As(err, &ferr) ||
+		ferr.Field != "newField" || ferr.Type != "client.Transaction" {
+		t.Fatal("expected unknown transaction field error", err)
+	}
+
+	// Field names match case insensitively as with encoding/json.
+	if _, err := cl.Account(2); err != nil {
+		t.Fatal(err)
+	}
+}
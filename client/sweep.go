@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// sweepAttempts is the number of times a sweep reads the balance it moves.
+const sweepAttempts = 3
+
+// Sweep transfers the whole balance of fromAccountID to toAccountID, for
+// example to drain an account before it is closed, and returns the transfer.
+//
+// The balance is read and then transferred with txID. If a concurrent transfer
+// or debit spends from the account in between, RTWire refuses the transfer
+// with ErrInsufficientFunds and the balance is read again, up to three times.
+// Credits arriving after the balance is read stay in fromAccountID. Every
+// attempt uses txID, so the account is swept at most once. ErrInsufficientFunds
+// is returned if the account has no balance.
+func Sweep(ctx context.Context, c Client, txID, fromAccountID,
+	toAccountID int64, options ...option) (Transaction, error) {
+	tx, err := sweep(ctx, c, fromAccountID, func(value Amount) (Transaction,
+		error) {
+		return c.TransferContext(ctx, txID, fromAccountID, toAccountID, value,
+			options...)
+	})
+	if err != nil {
+		return Transaction{}, fmt.Errorf("sweep of %d to %d: %w",
+			fromAccountID, toAccountID, err)
+	}
+	return tx, nil
+}
+
+// SweepToAddress debits the whole balance of fromAccountID to toAddress, as
+// Sweep does for a transfer, and returns the debit. The miner fee is chosen
+// as for Debit, with the FeePerByte or ConfirmationTarget options.
+func SweepToAddress(ctx context.Context, c Client, txID, fromAccountID int64,
+	toAddress string, options ...option) (Transaction, error) {
+	tx, err := sweep(ctx, c, fromAccountID, func(value Amount) (Transaction,
+		error) {
+		return c.DebitContext(ctx, txID, fromAccountID, toAddress, value,
+			options...)
+	})
+	if err != nil {
+		return Transaction{}, fmt.Errorf("sweep of %d to %s: %w",
+			fromAccountID, toAddress, err)
+	}
+	return tx, nil
+}
+
+// sweep calls move with the balance of accountID, reading it again while
+// move fails with ErrInsufficientFunds.
+func sweep(ctx context.Context, c Client, accountID int64,
+	move func(value Amount) (Transaction, error)) (Transaction, error) {
+	var err error
+	for i := 0; i < sweepAttempts; i++ {
+		var acc Account
+		if acc, err = c.AccountContext(ctx, accountID); err != nil {
+			return Transaction{}, err
+		}
+		if acc.Balance <= 0 {
+			return Transaction{}, ErrInsufficientFunds
+		}
+		var tx Transaction
+		if tx, err = move(acc.Balance); !errors.Is(err,
+			ErrInsufficientFunds) {
+			return tx, err
+		}
+	}
+	return Transaction{}, err
+}
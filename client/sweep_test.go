@@ -0,0 +1,79 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/clientmock"
+)
+
+func TestSweep(t *testing.T) {
+
+	// A concurrent debit spends 40 satoshi after the first balance is read.
+	balances := []client.Amount{100, 60}
+	m := &clientmock.Client{
+		AccountFunc: func(ctx context.Context, accountID int64,
+			options ...client.Option) (client.Account, error) {
+			balance := balances[0]
+			if len(balances) > 1 {
+				balances = balances[1:]
+			}
+			return client.Account{ID: accountID, Balance: balance}, nil
+		},
+		TransferFunc: func(ctx context.Context, txID, from, to int64,
+			value client.Amount, options ...client.Option) (
+			client.Transaction, error) {
+			if value > 60 {
+				return client.Transaction{}, client.ErrInsufficientFunds
+			}
+			return client.Transaction{ID: txID, Type: "transfer",
+				FromAccountID: from, ToAccountID: to, Value: value}, nil
+		},
+	}
+
+	tx, err := client.Sweep(context.Background(), m, 9, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Value != 60 || tx.ID != 9 {
+		t.Fatal("expected the balance left to be swept", tx)
+	}
+	for _, call := range m.CallsTo("Transfer") {
+		if call.Args[0] != int64(9) {
+			t.Fatal("expected every attempt to use the same txID", call)
+		}
+	}
+
+	balances = []client.Amount{0}
+	if _, err := client.Sweep(context.Background(), m, 10, 1,
+		2); !errors.Is(err, client.ErrInsufficientFunds) {
+		t.Fatal("expected an empty account not to be swept", err)
+	}
+}
+
+func TestSweepToAddress(t *testing.T) {
+
+	m := &clientmock.Client{
+		AccountFunc: func(ctx context.Context, accountID int64,
+			options ...client.Option) (client.Account, error) {
+			return client.Account{ID: accountID, Balance: 5000}, nil
+		},
+		DebitFunc: func(ctx context.Context, txID, from int64, to string,
+			value client.Amount, options ...client.Option) (
+			client.Transaction, error) {
+			return client.Transaction{ID: txID, Type: "debit",
+				FromAccountID: from, Value: value}, nil
+		},
+	}
+
+	tx, err := client.SweepToAddress(context.Background(), m, 3, 1,
+		"1BoatSLRHtKNngkdXEeobR76b53LETtpyT", client.FeePerByte(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Value != 5000 {
+		t.Fatal("expected the whole balance to be debited", tx)
+	}
+}
@@ -0,0 +1,190 @@
+// Package tier keeps per-account tiers, such as limits and risk classes, in
+// account metadata and enforces policies by tier on the transfers and debits
+// made through a client, so that services don't hardcode the rules.
+//
+// A tier is set on an account with Set and enforced by adding Middleware to a
+// client:
+//
+//	tier.Set(ctx, cl, accountID, tier.Info{Tier: "basic", Risk: "low"})
+//
+//	limits := tier.Limits{"basic": 100000, "gold": 10000000}
+//	cl = client.WithCallMiddleware(cl, tier.Middleware(cl, limits, time.Minute))
+package tier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+// Metadata keys holding the tier of an account.
+const (
+	TierKey = "tier"
+	RiskKey = "risk"
+)
+
+// ErrLimitExceeded is matched by the error of a movement refused by Limits.
+var ErrLimitExceeded = errors.New("tier limit exceeded")
+
+// Info is the tier of an account.
+type Info struct {
+	Tier string
+	Risk string
+}
+
+// Of returns the tier held in the metadata of acc.
+func Of(acc client.Account) Info {
+	return Info{Tier: acc.Metadata[TierKey], Risk: acc.Metadata[RiskKey]}
+}
+
+// Set sets the tier of accountID to info, keeping the account's other
+// metadata. Empty fields of info are removed. The metadata is read and
+// written back, so concurrent changes to it may be lost.
+func Set(ctx context.Context, c client.Client, accountID int64,
+	info Info) (client.Account, error) {
+	acc, err := c.AccountContext(ctx, accountID)
+	if err != nil {
+		return client.Account{}, err
+	}
+	md := make(map[string]string, len(acc.Metadata)+2)
+	for k, v := range acc.Metadata {
+		md[k] = v
+	}
+	for key, value := range map[string]string{
+		TierKey: info.Tier,
+		RiskKey: info.Risk,
+	} {
+		if value == "" {
+			delete(md, key)
+		} else {
+			md[key] = value
+		}
+	}
+	return c.UpdateAccountContext(ctx, accountID, client.Metadata(md))
+}
+
+// Movement is a transfer or debit of Value satoshi from AccountID, checked by
+// a Policy before it is made.
+type Movement struct {
+	// Method is the client method moving the funds, such as "Transfer".
+	Method    string
+	AccountID int64
+	Value     client.Amount
+}
+
+// Policy decides whether a movement from an account of a tier may be made.
+type Policy interface {
+	Check(ctx context.Context, info Info, m Movement) error
+}
+
+// PolicyFunc adapts a function to the Policy interface.
+type PolicyFunc func(ctx context.Context, info Info, m Movement) error
+
+// Check calls f(ctx, info, m).
+func (f PolicyFunc) Check(ctx context.Context, info Info, m Movement) error {
+	return f(ctx, info, m)
+}
+
+// Limits is a Policy limiting the value of a single movement by tier. Tiers
+// without a limit, including accounts without a tier, are not limited unless
+// a limit is set for the empty tier.
+type Limits map[string]client.Amount
+
+// Check refuses m if its value exceeds the limit of info's tier.
+func (l Limits) Check(ctx context.Context, info Info, m Movement) error {
+	limit, ok := l[info.Tier]
+	if !ok || m.Value <= limit {
+		return nil
+	}
+	return fmt.Errorf("%w: %s of %d from account %d above %d for tier %q",
+		ErrLimitExceeded, m.Method, m.Value, m.AccountID, limit, info.Tier)
+}
+
+// Middleware returns a client.CallMiddleware that checks every Transfer, Debit
+// and DebitMany call with policy, given the tier of the sending account, and
+// refuses calls the policy returns an error for. Tiers are read from c and
+// cached for ttl, so a changed tier applies once its cached value expires.
+func Middleware(c client.Client, policy Policy,
+	ttl time.Duration) client.CallMiddleware {
+	cache := &cache{client: c, ttl: ttl, tiers: map[int64]cachedInfo{}}
+	return func(next client.CallHandler) client.CallHandler {
+		return func(ctx context.Context, call client.Call) error {
+			m, ok := movement(call)
+			if !ok {
+				return next(ctx, call)
+			}
+			info, err := cache.info(ctx, m.AccountID)
+			if err != nil {
+				return fmt.Errorf("tier of account %d: %w", m.AccountID, err)
+			}
+			if err := policy.Check(ctx, info, m); err != nil {
+				return err
+			}
+			return next(ctx, call)
+		}
+	}
+}
+
+// movement returns the movement made by call, if it moves funds.
+func movement(call client.Call) (Movement, bool) {
+	m := Movement{Method: call.Method}
+	if len(call.Args) < 3 {
+		return m, false
+	}
+	var ok bool
+	if m.AccountID, ok = call.Args[1].(int64); !ok {
+		return m, false
+	}
+	switch call.Method {
+	case "Transfer", "Debit":
+		if len(call.Args) < 4 {
+			return m, false
+		}
+		m.Value, ok = call.Args[3].(client.Amount)
+		return m, ok
+	case "DebitMany":
+		outputs, ok := call.Args[2].([]client.Output)
+		for _, out := range outputs {
+			m.Value += out.Value
+		}
+		return m, ok
+	}
+	return m, false
+}
+
+type cachedInfo struct {
+	info    Info
+	expires time.Time
+}
+
+// cache holds the tiers of accounts read from client.
+type cache struct {
+	client client.Client
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	tiers map[int64]cachedInfo
+}
+
+func (c *cache) info(ctx context.Context, accountID int64) (Info, error) {
+	c.mu.Lock()
+	cached, ok := c.tiers[accountID]
+	c.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.info, nil
+	}
+
+	acc, err := c.client.AccountContext(ctx, accountID)
+	if err != nil {
+		return Info{}, err
+	}
+	info := Of(acc)
+	c.mu.Lock()
+	c.tiers[accountID] = cachedInfo{info, time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return info, nil
+}
@@ -0,0 +1,100 @@
+package tier_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/clientmock"
+	"github.com/rtwire/go/client/tier"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	tiers := map[int64]map[string]string{
+		1: {tier.TierKey: "basic", "customer": "c1"},
+		2: {tier.TierKey: "gold"},
+	}
+	lookups := 0
+	m := &clientmock.Client{
+		AccountFunc: func(ctx context.Context, accountID int64,
+			options ...client.Option) (client.Account, error) {
+			lookups++
+			return client.Account{ID: accountID,
+				Metadata: tiers[accountID]}, nil
+		},
+		TransferFunc: func(ctx context.Context, txID, from, to int64,
+			value client.Amount, options ...client.Option) (
+			client.Transaction, error) {
+			return client.Transaction{ID: txID}, nil
+		},
+		DebitManyFunc: func(ctx context.Context, txID, from int64,
+			outputs []client.Output,
+			options ...client.Option) (client.Transaction, error) {
+			return client.Transaction{ID: txID}, nil
+		},
+	}
+	limits := tier.Limits{"basic": 100, "gold": 1000}
+	cl := client.WithCallMiddleware(m, tier.Middleware(m, limits, time.Hour))
+
+	if _, err := cl.Transfer(1, 1, 2, 100); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cl.Transfer(2, 1, 2, 101); !errors.Is(err,
+		tier.ErrLimitExceeded) {
+		t.Fatal("expected basic limit to be enforced", err)
+	}
+	if _, err := cl.Transfer(3, 2, 1, 500); err != nil {
+		t.Fatal(err)
+	}
+	outputs := []client.Output{{Value: 600}, {Value: 600}}
+	if _, err := cl.DebitMany(4, 2, outputs); !errors.Is(err,
+		tier.ErrLimitExceeded) {
+		t.Fatal("expected the total of the outputs to be limited", err)
+	}
+	if lookups != 2 {
+		t.Fatal("expected tiers to be cached", lookups)
+	}
+	if n := len(m.CallsTo("Transfer")); n != 2 {
+		t.Fatal("expected the refused transfer not to be made", n)
+	}
+}
+
+func TestSet(t *testing.T) {
+
+	var sent map[string]map[string]string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.Method {
+			case "GET":
+				fmt.Fprint(w, `{"type": "accounts", "payload": [{"id": 1,
+					"metadata": {"customer": "c1", "risk": "high"}}]}`)
+			case "PUT":
+				if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+					t.Error(err)
+				}
+				fmt.Fprint(w, `{"type": "accounts", "payload": [{"id": 1}]}`)
+			}
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	if _, err := tier.Set(context.Background(), cl, 1,
+		tier.Info{Tier: "gold"}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"customer": "c1", tier.TierKey: "gold"}
+	if !reflect.DeepEqual(sent["metadata"], want) {
+		t.Fatal("expected tier merged into metadata", sent)
+	}
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// timeLayouts are the formats accepted for timestamps, tried in order.
+// Timestamps without a zone are taken to be UTC.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseTime parses a JSON timestamp given as a string in one of timeLayouts
+// or as a number of seconds since the Unix epoch, returning it in UTC.
+func parseTime(data []byte) (time.Time, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		secs, err := strconv.ParseFloat(string(data), 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %s", data)
+		}
+		return time.Unix(0, int64(secs*float64(time.Second))).UTC(), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// UnmarshalJSON decodes a transaction, accepting any of the timestamp formats
+// RTWire has used for Created and normalizing it to UTC.
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	type transaction Transaction
+	aux := struct {
+		*transaction
+		Created json.RawMessage `json:"created"`
+	}{transaction: (*transaction)(t)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.Created) == 0 || string(aux.Created) == "null" {
+		return nil
+	}
+	created, err := parseTime(aux.Created)
+	if err != nil {
+		return err
+	}
+	t.Created = created
+	return nil
+}
+
+// UnmarshalJSON decodes an address, accepting the same timestamp formats for
+// Created and Expires as Transaction.
+func (a *Address) UnmarshalJSON(data []byte) error {
+	type address Address
+	aux := struct {
+		*address
+		Created json.RawMessage `json:"created"`
+		Expires json.RawMessage `json:"expires"`
+	}{address: (*address)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	for _, field := range []struct {
+		raw json.RawMessage
+		t   *time.Time
+	}{{aux.Created, &a.Created}, {aux.Expires, &a.Expires}} {
+		if len(field.raw) == 0 || string(field.raw) == "null" {
+			continue
+		}
+		t, err := parseTime(field.raw)
+		if err != nil {
+			return err
+		}
+		*field.t = t
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a transaction event. It is needed as the embedded
+// Transaction's UnmarshalJSON would otherwise decode the whole event, losing
+// Status.
+func (e *TransactionEvent) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &e.Transaction); err != nil {
+		return err
+	}
+	status := struct {
+		Status string `json:"status"`
+	}{}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+	e.Status = status.Status
+	return nil
+}
+
+// WithLocation converts the Created time of every transaction returned by the
+// client to loc, for example to display times in a customer's zone. Times
+// are in UTC otherwise.
+func WithLocation(loc *time.Location) ClientOption {
+	return func(c *client) {
+		c.location = loc
+	}
+}
+
+// localize converts the times held by v, the decoded payload of a response,
+// to the client's location.
+func (c *client) localize(v interface{}) {
+	switch v := v.(type) {
+	case *Transaction:
+		v.Created = v.Created.In(c.location)
+	case *[]Transaction:
+		for i := range *v {
+			(*v)[i].Created = (*v)[i].Created.In(c.location)
+		}
+	case *[]Address:
+		for i := range *v {
+			(*v)[i].Created = (*v)[i].Created.In(c.location)
+			if !(*v)[i].Expires.IsZero() {
+				(*v)[i].Expires = (*v)[i].Expires.In(c.location)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package client_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestTransactionCreated(t *testing.T) {
+
+	expected := time.Date(2026, 10, 14, 10, 30, 0, 0, time.UTC)
+	for _, created := range []string{
+		`"2026-10-14T10:30:00Z"`,
+		`"2026-10-14T12:30:00+02:00"`,
+		`"2026-10-14T10:30:00"`,
+		`"2026-10-14 10:30:00"`,
+		`"2026-10-14 05:30:00-05:00"`,
+		`1791973800`,
+	} {
+		var tx client.Transaction
+		data := fmt.Sprintf(`{"id": 1, "value": 5, "created": %s}`, created)
+		if err := json.Unmarshal([]byte(data), &tx); err != nil {
+			t.Fatal(created, err)
+		}
+		if !tx.Created.Equal(expected) || tx.Created.Location() != time.UTC {
+			t.Fatal("unexpected time for", created, tx.Created)
+		}
+		if tx.ID != 1 || tx.Value != 5 {
+			t.Fatal("expected other fields to be decoded", tx)
+		}
+	}
+
+	var tx client.Transaction
+	if err := json.Unmarshal([]byte(`{"created": "yesterday"}`),
+		&tx); err == nil {
+		t.Fatal("expected invalid time to be refused")
+	}
+}
+
+func TestWithLocation(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"type": "transactions", "payload": [
+				{"id": 1, "created": "2026-10-14T23:30:00Z"}]}`)
+		}))
+	defer server.Close()
+
+	loc := time.FixedZone("JST", 9*60*60)
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithLocation(loc))
+
+	_, txns, err := cl.AccountTransactions(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 1 || txns[0].Created.Location() != loc ||
+		txns[0].Created.Day() != 15 {
+		t.Fatal("expected time in location", txns)
+	}
+}
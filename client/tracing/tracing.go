@@ -0,0 +1,126 @@
+// Package tracing creates a trace span for each RTWire client call and
+// propagates the trace to RTWire in request headers.
+//
+// The package depends only on the small Tracer and Span interfaces so that it
+// can be used with OpenTelemetry or any other tracing system through an
+// adapter. An OpenTelemetry Tracer adapter only needs to call
+// trace.Tracer.Start, wrap the returned span, and inject headers with
+// otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header)).
+//
+// Both middleware are needed, one around client calls and one around the HTTP
+// requests they make:
+//
+//	cl := client.New(nil, client.MainNetURL, user, pass,
+//		client.WithMiddleware(tracing.Requests(t)))
+//	cl = client.WithCallMiddleware(cl, tracing.Calls(t))
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/rtwire/go/client"
+)
+
+// Attribute is a span attribute.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span is a trace span started by a Tracer.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans and propagates them across process boundaries.
+type Tracer interface {
+	// Start starts a span called name as a child of any span in ctx and
+	// returns a context holding the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+
+	// Inject adds the headers, such as traceparent, that propagate the span
+	// in ctx to header.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// argNames names the span attributes of each method's arguments. Options are
+// not recorded.
+var argNames = map[string][]string{
+	"CreateAccounts":          {"rtwire.count"},
+	"Account":                 {"rtwire.account_id"},
+	"UpdateAccount":           {"rtwire.account_id"},
+	"CloseAccount":            {"rtwire.account_id"},
+	"CreateAddress":           {"rtwire.account_id"},
+	"CreateAddresses":         {"rtwire.account_ids"},
+	"AccountAddresses":        {"rtwire.account_id"},
+	"AccountByAddress":        {"rtwire.address"},
+	"FundAddress":             {"rtwire.address", "rtwire.value"},
+	"CreateTransactionIDs":    {"rtwire.count"},
+	"Transaction":             {"rtwire.tx_id"},
+	"TransactionsByHash":      {"rtwire.tx_hash"},
+	"RawTransaction":          {"rtwire.tx_hash"},
+	"WaitForTransaction":      {"rtwire.tx_id"},
+	"AccountTransactions":     {"rtwire.account_id"},
+	"AccountWithTransactions": {"rtwire.account_id", "rtwire.limit"},
+	"Transfer": {"rtwire.tx_id", "rtwire.from_account_id",
+		"rtwire.to_account_id", "rtwire.value"},
+	"Debit": {"rtwire.tx_id", "rtwire.from_account_id",
+		"rtwire.to_address", "rtwire.value"},
+	"DebitMany":         {"rtwire.tx_id", "rtwire.from_account_id"},
+	"FeeForTarget":      {"rtwire.confirmation_target"},
+	"CreateHook":        {"rtwire.hook_url"},
+	"DeleteHook":        {"rtwire.hook_url"},
+	"CreateAccountHook": {"rtwire.account_id", "rtwire.hook_url"},
+	"AccountHooks":      {"rtwire.account_id"},
+	"DeleteAccountHook": {"rtwire.account_id", "rtwire.hook_url"},
+}
+
+// Calls returns a client.CallMiddleware that starts a span named after the
+// method, for example "rtwire.Transfer", for each call, with the call's
+// account IDs, transaction IDs and other arguments as attributes. Errors are
+// recorded on the span.
+func Calls(t Tracer) client.CallMiddleware {
+	return func(next client.CallHandler) client.CallHandler {
+		return func(ctx context.Context, call client.Call) error {
+			ctx, span := t.Start(ctx, "rtwire."+call.Method)
+			defer span.End()
+
+			names := argNames[call.Method]
+			attrs := make([]Attribute, 0, len(names))
+			for i, name := range names {
+				if i >= len(call.Args) {
+					break
+				}
+				v := call.Args[i]
+				// Values are recorded in satoshi, as tracers know int64.
+				if amount, ok := v.(client.Amount); ok {
+					v = int64(amount)
+				}
+				attrs = append(attrs, Attribute{name, v})
+			}
+			if len(attrs) > 0 {
+				span.SetAttributes(attrs...)
+			}
+
+			err := next(ctx, call)
+			if err != nil {
+				span.RecordError(err)
+			}
+			return err
+		}
+	}
+}
+
+// Requests returns a client.Middleware that adds the trace headers of the span
+// in each request's context to the request.
+func Requests(t Tracer) client.Middleware {
+	return func(next client.RoundTripperFunc) client.RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			t.Inject(req.Context(), req.Header)
+			return next(req)
+		}
+	}
+}
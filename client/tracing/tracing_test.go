@@ -0,0 +1,89 @@
+package tracing_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/tracing"
+)
+
+type spanKey struct{}
+
+type testSpan struct {
+	name  string
+	attrs []tracing.Attribute
+	err   error
+	ended bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...tracing.Attribute) {
+	s.attrs = append(s.attrs, attrs...)
+}
+func (s *testSpan) RecordError(err error) { s.err = err }
+func (s *testSpan) End()                  { s.ended = true }
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (
+	context.Context, tracing.Span) {
+	span := &testSpan{name: name}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (t *testTracer) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(spanKey{}).(*testSpan); ok {
+		header.Set("traceparent", span.name)
+	}
+}
+
+func TestTracing(t *testing.T) {
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("traceparent")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"type": "errors",
+				"payload": [{"message": "insufficient funds"}]}`)
+		}))
+	defer server.Close()
+
+	tracer := &testTracer{}
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithMiddleware(tracing.Requests(tracer)))
+	cl = client.WithCallMiddleware(cl, tracing.Calls(tracer))
+
+	_, err := cl.Transfer(1, 2, 3, 4)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatal("expected one span", tracer.spans)
+	}
+	span := tracer.spans[0]
+	if span.name != "rtwire.Transfer" || !span.ended || span.err != err {
+		t.Fatalf("unexpected span %+v", span)
+	}
+	expected := fmt.Sprint([]tracing.Attribute{
+		{"rtwire.tx_id", int64(1)},
+		{"rtwire.from_account_id", int64(2)},
+		{"rtwire.to_account_id", int64(3)},
+		{"rtwire.value", int64(4)},
+	})
+	if fmt.Sprint(span.attrs) != expected {
+		t.Fatal("unexpected attributes", span.attrs)
+	}
+	if traceparent != "rtwire.Transfer" {
+		t.Fatal("expected trace to be propagated", traceparent)
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// TransferRequest is one transfer of a TransferBatch.
+type TransferRequest struct {
+	TxID          int64
+	FromAccountID int64
+	ToAccountID   int64
+	Value         Amount
+}
+
+func (r TransferRequest) validate() error {
+	return validate(
+		validateID("txID", r.TxID),
+		validateID("fromAccountID", r.FromAccountID),
+		validateID("toAccountID", r.ToAccountID),
+		validateValue(r.Value),
+	)
+}
+
+// TransferBatch makes the transfers of reqs, in order, undoing them if one
+// fails. It is not atomic and saves no round trips: RTWire has no batch
+// endpoint, so each item is its own Transfer call, and a failure costs up to
+// as many calls again, plus one for reversal IDs and one to look up the
+// failed transfer. Other clients see the transfers made before a failure
+// until they are reversed.
+//
+// If a transfer fails the transfers already made are reversed, newest first,
+// by transfers in the opposite direction with fresh transaction IDs, and
+// marked BatchReverted. The failed item is BatchFailed and later items are
+// left not attempted.
+//
+// Every request is validated first and, if any is invalid, no transfer is
+// made: each invalid item is BatchFailed with its *ValidationError, reported
+// together by the result's Err.
+//
+// A transfer failing with an error that leaves its outcome unknown, such as a
+// timeout, may still have been applied. Its transaction is then fetched and,
+// if it was applied, it is reversed with the others and marked BatchReverted,
+// keeping its error. If it cannot be fetched TransferBatch returns an error
+// saying so as the item may remain applied.
+//
+// Reversals are made even if ctx is cancelled. If a reversal fails, for
+// example because the recipient has already spent the funds, the remaining
+// reversals are not attempted and TransferBatch returns an error alongside
+// the result. The rollback is then partial: items still BatchSucceeded remain
+// applied and must be resolved by hand.
+func TransferBatch(ctx context.Context, c Client,
+	reqs []TransferRequest) (BatchResult, error) {
+
+	res := newBatchResult(len(reqs))
+	invalid := false
+	for i, r := range reqs {
+		if err := r.validate(); err != nil {
+			res.set(i, r.TxID, err)
+			invalid = true
+		}
+	}
+	if invalid {
+		return res, nil
+	}
+
+	failed := -1
+	for i, r := range reqs {
+		_, err := c.TransferContext(ctx, r.TxID, r.FromAccountID,
+			r.ToAccountID, r.Value)
+		res.set(i, r.TxID, err)
+		if err != nil {
+			failed = i
+			break
+		}
+	}
+	if failed < 0 {
+		return res, nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	applied := failed
+	var unresolved error
+	ok, err := transferApplied(ctx, c, reqs[failed], res.Items[failed].Err)
+	switch {
+	case err != nil:
+		unresolved = fmt.Errorf("transfer batch: item %d may be applied: %w",
+			failed, err)
+	case ok:
+		applied++
+	}
+	if applied == 0 {
+		return res, unresolved
+	}
+
+	txIDs, err := c.CreateTransactionIDsContext(ctx, applied)
+	if err != nil {
+		return res, fmt.Errorf("transfer batch: create reversal IDs: %w", err)
+	}
+	for i := applied - 1; i >= 0; i-- {
+		r := reqs[i]
+		if _, err := c.TransferContext(ctx, txIDs[i], r.ToAccountID,
+			r.FromAccountID, r.Value); err != nil {
+			return res, fmt.Errorf("transfer batch: reverse item %d: %w", i,
+				err)
+		}
+		res.Items[i].Status = BatchReverted
+	}
+	return res, unresolved
+}
+
+// transferApplied reports whether r, whose transfer failed with err, was
+// applied regardless, as happens when the response to an applied transfer is
+// lost. Errors RTWire returns when refusing a transfer need no lookup.
+func transferApplied(ctx context.Context, c Client, r TransferRequest,
+	err error) (bool, error) {
+
+	var verr *ValidationError
+	if errors.As(err, &verr) || errors.Is(err, ErrInsufficientFunds) ||
+		errors.Is(err, ErrAccountClosed) || errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	tx, err := c.TransactionContext(ctx, r.TxID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	// A different transaction under the ID means r was never applied.
+	return tx.Type == "transfer" && tx.FromAccountID == r.FromAccountID &&
+		tx.ToAccountID == r.ToAccountID && tx.Value == r.Value, nil
+}
@@ -0,0 +1,220 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/clientmock"
+)
+
+func TestTransferBatch(t *testing.T) {
+
+	balances := map[int64]client.Amount{1: 100, 2: 0, 3: 0}
+	nextID := int64(100)
+	m := &clientmock.Client{
+		TransferFunc: func(ctx context.Context, txID, from, to int64,
+			value client.Amount, options ...client.Option) (
+			client.Transaction, error) {
+			if balances[from] < value {
+				return client.Transaction{}, client.ErrInsufficientFunds
+			}
+			balances[from] -= value
+			balances[to] += value
+			return client.Transaction{ID: txID}, nil
+		},
+		CreateTransactionIDsFunc: func(ctx context.Context, n int,
+			options ...client.Option) ([]int64, error) {
+			ids := make([]int64, n)
+			for i := range ids {
+				nextID++
+				ids[i] = nextID
+			}
+			return ids, nil
+		},
+	}
+
+	res, err := client.TransferBatch(context.Background(), m,
+		[]client.TransferRequest{
+			{TxID: 1, FromAccountID: 1, ToAccountID: 2, Value: 60},
+			{TxID: 2, FromAccountID: 2, ToAccountID: 3, Value: 10},
+			{TxID: 3, FromAccountID: 1, ToAccountID: 3, Value: 60},
+			{TxID: 4, FromAccountID: 1, ToAccountID: 3, Value: 1},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statuses []string
+	for _, item := range res.Items {
+		statuses = append(statuses, item.Status.String())
+	}
+	expected := "[reverted reverted failed not attempted]"
+	if fmt.Sprint(statuses) != expected {
+		t.Fatal("unexpected statuses", statuses)
+	}
+	if balances[1] != 100 || balances[2] != 0 || balances[3] != 0 {
+		t.Fatal("expected balances to be restored", balances)
+	}
+
+	res, err = client.TransferBatch(context.Background(), m,
+		[]client.TransferRequest{
+			{TxID: 5, FromAccountID: 1, ToAccountID: 2, Value: 60},
+			{TxID: 6, FromAccountID: 2, ToAccountID: 3, Value: 10},
+		})
+	if err != nil || res.Err() != nil {
+		t.Fatal(err, res.Err())
+	}
+	if balances[1] != 40 || balances[2] != 50 || balances[3] != 10 {
+		t.Fatal("expected transfers to be applied", balances)
+	}
+}
+
+func TestTransferBatchReversalFails(t *testing.T) {
+
+	balances := map[int64]client.Amount{1: 100, 2: 0, 3: 0}
+	m := &clientmock.Client{
+		TransferFunc: func(ctx context.Context, txID, from, to int64,
+			value client.Amount, options ...client.Option) (
+			client.Transaction, error) {
+			// Account 3 has spent what it received before the reversal.
+			if balances[from] < value || (txID >= 100 && from == 3) {
+				return client.Transaction{}, client.ErrInsufficientFunds
+			}
+			balances[from] -= value
+			balances[to] += value
+			return client.Transaction{ID: txID}, nil
+		},
+		CreateTransactionIDsFunc: func(ctx context.Context, n int,
+			options ...client.Option) ([]int64, error) {
+			return []int64{100, 101}[:n], nil
+		},
+	}
+
+	res, err := client.TransferBatch(context.Background(), m,
+		[]client.TransferRequest{
+			{TxID: 1, FromAccountID: 1, ToAccountID: 2, Value: 60},
+			{TxID: 2, FromAccountID: 2, ToAccountID: 3, Value: 60},
+			{TxID: 3, FromAccountID: 1, ToAccountID: 3, Value: 60},
+		})
+	if !errors.Is(err, client.ErrInsufficientFunds) {
+		t.Fatal("expected the reversal to fail", err)
+	}
+
+	// Neither transfer could be reversed, as reversals stop at the first
+	// failure, so both remain applied.
+	var statuses []string
+	for _, item := range res.Items {
+		statuses = append(statuses, item.Status.String())
+	}
+	if fmt.Sprint(statuses) != "[succeeded succeeded failed]" {
+		t.Fatal("unexpected statuses", statuses)
+	}
+	if balances[1] != 40 || balances[2] != 0 || balances[3] != 60 {
+		t.Fatal("expected the transfers to remain applied", balances)
+	}
+}
+
+func TestTransferBatchAmbiguous(t *testing.T) {
+
+	// The second transfer is applied but its response is lost.
+	var transfers [][2]int64
+	lookup := func(txID int64) (client.Transaction, error) {
+		return client.Transaction{ID: 2, Type: "transfer",
+			FromAccountID: 2, ToAccountID: 3, Value: 10}, nil
+	}
+	m := &clientmock.Client{
+		TransferFunc: func(ctx context.Context, txID, from, to int64,
+			value client.Amount, options ...client.Option) (
+			client.Transaction, error) {
+			transfers = append(transfers, [2]int64{from, to})
+			if txID == 2 {
+				return client.Transaction{}, context.DeadlineExceeded
+			}
+			return client.Transaction{ID: txID}, nil
+		},
+		TransactionFunc: func(ctx context.Context, txID int64,
+			options ...client.Option) (client.Transaction, error) {
+			return lookup(txID)
+		},
+		CreateTransactionIDsFunc: func(ctx context.Context, n int,
+			options ...client.Option) ([]int64, error) {
+			return make([]int64, n), nil
+		},
+	}
+	reqs := []client.TransferRequest{
+		{TxID: 1, FromAccountID: 1, ToAccountID: 2, Value: 10},
+		{TxID: 2, FromAccountID: 2, ToAccountID: 3, Value: 10},
+	}
+
+	res, err := client.TransferBatch(context.Background(), m, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Items[0].Status != client.BatchReverted ||
+		res.Items[1].Status != client.BatchReverted {
+		t.Fatal("expected both items reverted", res.Items)
+	}
+	expected := "[[1 2] [2 3] [3 2] [2 1]]"
+	if fmt.Sprint(transfers) != expected {
+		t.Fatal("unexpected transfers", transfers)
+	}
+
+	// A transfer that was not applied is left failed.
+	transfers = nil
+	lookup = func(txID int64) (client.Transaction, error) {
+		return client.Transaction{}, client.ErrNotFound
+	}
+	res, err = client.TransferBatch(context.Background(), m, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Items[1].Status != client.BatchFailed ||
+		fmt.Sprint(transfers) != "[[1 2] [2 3] [2 1]]" {
+		t.Fatal("expected only the first item reversed", res.Items,
+			transfers)
+	}
+
+	// If the outcome can't be found out the caller is told.
+	errLookup := errors.New("lookup failed")
+	lookup = func(txID int64) (client.Transaction, error) {
+		return client.Transaction{}, errLookup
+	}
+	if _, err := client.TransferBatch(context.Background(), m,
+		reqs); !errors.Is(err, errLookup) {
+		t.Fatal("expected lookup error", err)
+	}
+}
+
+func TestTransferBatchInvalid(t *testing.T) {
+
+	m := &clientmock.Client{}
+	reqs := []client.TransferRequest{
+		{TxID: 1, FromAccountID: 1, ToAccountID: 2, Value: 10},
+		{TxID: 2, FromAccountID: 0, ToAccountID: 3, Value: 10},
+		{TxID: 3, FromAccountID: 2, ToAccountID: 3, Value: -1},
+	}
+	res, err := client.TransferBatch(context.Background(), m, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := m.Calls(); len(calls) != 0 {
+		t.Fatal("expected no transfers", calls)
+	}
+
+	var multi *client.MultiError
+	if !errors.As(res.Err(), &multi) || len(multi.Items) != 3 {
+		t.Fatal("expected every item in the error", res.Err())
+	}
+	var vErr *client.ValidationError
+	if !errors.As(multi.Items[1].Err, &vErr) ||
+		vErr.Field != "fromAccountID" {
+		t.Fatal("expected invalid from account", multi.Items[1])
+	}
+	if multi.Items[0].Status != client.BatchNotAttempted ||
+		multi.Items[2].Status != client.BatchFailed {
+		t.Fatal("expected only invalid items failed", multi.Items)
+	}
+}
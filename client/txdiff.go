@@ -0,0 +1,67 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FieldDiff is a Transaction field whose value differs between two versions
+// of a transaction.
+type FieldDiff struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// TransactionDiff lists the fields that differ between two versions of a
+// transaction.
+type TransactionDiff []FieldDiff
+
+// String returns one line per field in the form "Value: 1000 -> 900", or
+// "no differences" if the diff is empty.
+func (d TransactionDiff) String() string {
+	if len(d) == 0 {
+		return "no differences"
+	}
+	lines := make([]string, len(d))
+	for i, f := range d {
+		lines[i] = fmt.Sprintf("%s: %s -> %s", f.Field, f.Old, f.New)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DiffTransactions compares every field of old and new, for example the same
+// transaction while pending and once credited, or as returned by two RTWire
+// environments. Created times are compared as instants so a transaction read
+// in different time zones is not reported as changed.
+func DiffTransactions(old, new Transaction) TransactionDiff {
+	var d TransactionDiff
+	add := func(field string, o, n interface{}) {
+		oldStr, newStr := fmt.Sprint(o), fmt.Sprint(n)
+		if oldStr != newStr {
+			d = append(d, FieldDiff{Field: field, Old: oldStr, New: newStr})
+		}
+	}
+
+	add("ID", old.ID, new.ID)
+	add("Type", old.Type, new.Type)
+	add("FromAccountID", old.FromAccountID, new.FromAccountID)
+	add("ToAccountID", old.ToAccountID, new.ToAccountID)
+	add("FromAccountBalance", old.FromAccountBalance, new.FromAccountBalance)
+	add("ToAccountBalance", old.ToAccountBalance, new.ToAccountBalance)
+	add("FromAccountTxID", old.FromAccountTxID, new.FromAccountTxID)
+	add("ToAccountTxID", old.ToAccountTxID, new.ToAccountTxID)
+	add("Value", old.Value, new.Value)
+	if !old.Created.Equal(new.Created) {
+		d = append(d, FieldDiff{
+			Field: "Created",
+			Old:   old.Created.UTC().Format(time.RFC3339Nano),
+			New:   new.Created.UTC().Format(time.RFC3339Nano),
+		})
+	}
+	add("TxHashes", strings.Join(old.TxHashes, ","),
+		strings.Join(new.TxHashes, ","))
+	add("TxOutIndex", old.TxOutIndex, new.TxOutIndex)
+	return d
+}
@@ -0,0 +1,43 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestDiffTransactions(t *testing.T) {
+
+	created := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	pending := client.Transaction{
+		ID:       1,
+		Type:     "credit",
+		Value:    1000,
+		Created:  created,
+		TxHashes: []string{"aa"},
+	}
+
+	same := pending
+	same.Created = created.In(time.FixedZone("CEST", 2*60*60))
+	if d := client.DiffTransactions(pending, same); len(d) != 0 {
+		t.Fatal("expected no differences", d)
+	}
+	if s := client.DiffTransactions(pending, same).String(); s !=
+		"no differences" {
+		t.Fatal(s)
+	}
+
+	final := pending
+	final.Value = 900
+	final.TxHashes = []string{"aa", "bb"}
+	d := client.DiffTransactions(pending, final)
+	expected := "Value: 0.00001000 BTC -> 0.00000900 BTC\n" +
+		"TxHashes: aa -> aa,bb"
+	if d.String() != expected {
+		t.Fatalf("unexpected diff %q", d.String())
+	}
+	if len(d) != 2 || d[0].Field != "Value" || d[0].Old != "0.00001000 BTC" {
+		t.Fatalf("unexpected fields %+v", d)
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxSupply is the maximum number of satoshi that can ever exist.
+const MaxSupply = 21000000 * 100000000
+
+// ValidationError is returned, without contacting RTWire, when an argument to
+// a Client method is invalid. Field names the argument.
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+func validateID(field string, id int64) error {
+	if id <= 0 {
+		return &ValidationError{field, "must be greater than zero"}
+	}
+	return nil
+}
+
+func validateCount(field string, n int) error {
+	if n < 0 {
+		return &ValidationError{field, "must not be negative"}
+	}
+	return nil
+}
+
+func validateValue(value Amount) error {
+	switch {
+	case value < 0:
+		return &ValidationError{"value", "must not be negative"}
+	case value > MaxSupply:
+		return &ValidationError{"value", "exceeds the bitcoin supply"}
+	}
+	return nil
+}
+
+func validateTxHash(field, hash string) error {
+	if len(hash) != 64 {
+		return &ValidationError{field, "must be 64 hexadecimal digits"}
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return &ValidationError{field, "must be 64 hexadecimal digits"}
+	}
+	return nil
+}
+
+// ErrInvalidAddress is matched by the ValidationError returned for a malformed
+// address, or an address for another network than the client's, such as a
+// testnet address given to a client of MainNetURL.
+var ErrInvalidAddress = errors.New("invalid address")
+
+// addressError is the ValidationError of an invalid address.
+type addressError struct {
+	*ValidationError
+}
+
+func (e addressError) Unwrap() error { return e.ValidationError }
+
+func (e addressError) Is(target error) bool {
+	return target == ErrInvalidAddress
+}
+
+// validateAddress checks addr and, unless n is nil, that it is for network n.
+func validateAddress(field, addr string, n *Network) error {
+	var err error
+	switch {
+	case n == nil:
+		err = checkAddress(addr)
+	case strings.HasPrefix(strings.ToLower(addr), n.HRP+"1"):
+		err = checkSegwit(addr)
+	default:
+		// Addresses of other networks fail here, whether base58check or
+		// segwit.
+		err = n.checkNetwork(addr)
+	}
+	if err != nil {
+		return addressError{&ValidationError{field, err.Error()}}
+	}
+	return nil
+}
+
+// validate returns the first error of errs.
+func validate(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
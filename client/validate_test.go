@@ -0,0 +1,145 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestValidation(t *testing.T) {
+
+	// Invalid calls must fail before any request is made.
+	requests := 0
+	hc := &http.Client{Transport: client.RoundTripperFunc(
+		func(*http.Request) (*http.Response, error) {
+			requests++
+			return nil, errors.New("unexpected request")
+		})}
+	cl := client.New(hc, client.MainNetURL, "user", "pass")
+
+	const addr = "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"
+	transfer := func(txID, from, to int64, value client.Amount) error {
+		_, err := cl.Transfer(txID, from, to, value)
+		return err
+	}
+	debit := func(txID, from int64, addr string,
+		value client.Amount) error {
+		_, err := cl.Debit(txID, from, addr, value)
+		return err
+	}
+	debitMany := func(outputs ...client.Output) error {
+		_, err := cl.DebitMany(1, 1, outputs)
+		return err
+	}
+	// 8785 outputs of MaxSupply wrap an int64 total back below MaxSupply.
+	wrapping := make([]client.Output, 8785)
+	for i := range wrapping {
+		wrapping[i] = client.Output{addr, client.MaxSupply}
+	}
+	updateAccount := func(accountID int64, options ...client.Option) error {
+		_, err := cl.UpdateAccount(accountID, options...)
+		return err
+	}
+	accountByAddress := func(address string) error {
+		_, err := cl.AccountByAddress(address)
+		return err
+	}
+	transactionsByHash := func(txHash string) error {
+		_, err := cl.TransactionsByHash(txHash)
+		return err
+	}
+	fundAddress := func(address string, value client.Amount) error {
+		return client.New(hc, client.TestNet3URL, "user", "pass").
+			FundAddress(address, value)
+	}
+	rawTransaction := func(txHash string) error {
+		_, err := cl.RawTransaction(txHash)
+		return err
+	}
+	accountHooks := func(accountID int64) error {
+		_, err := cl.AccountHooks(accountID)
+		return err
+	}
+	accounts := func(options ...client.Option) error {
+		_, _, err := cl.Accounts(options...)
+		return err
+	}
+	accountTransactions := func(options ...client.Option) error {
+		_, _, err := cl.AccountTransactions(1, options...)
+		return err
+	}
+	createAccounts := func(n int) error {
+		_, result := cl.CreateAccounts(n)
+		return result.Err()
+	}
+	createTransactionIDs := func(n int) error {
+		_, err := cl.CreateTransactionIDs(n)
+		return err
+	}
+	tests := []struct {
+		err   error
+		field string
+	}{
+		{transfer(0, 1, 2, 3), "txID"},
+		{transfer(1, -1, 2, 3), "fromAccountID"},
+		{transfer(1, 1, 0, 3), "toAccountID"},
+		{transfer(1, 1, 2, -3), "value"},
+		{transfer(1, 1, 2, client.MaxSupply+1), "value"},
+		{debit(1, 1, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", 3), "toAddress"},
+		{debit(1, 1, "", 3), "toAddress"},
+		{debit(1, 0, addr, 3), "fromAccountID"},
+		{accountByAddress("1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb"), "address"},
+		{transactionsByHash("ab"), "txHash"},
+		{transactionsByHash(strings.Repeat("x", 64)), "txHash"},
+		{rawTransaction("ab"), "txHash"},
+		{fundAddress(addr, 10), "address"},
+		{fundAddress("mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", -1), "value"},
+		{debitMany(), "outputs"},
+		{debitMany(client.Output{addr, 3}, client.Output{"", 3}),
+			"outputs[1].address"},
+		{debitMany(client.Output{addr, -3}), "value"},
+		{debitMany(client.Output{addr, client.MaxSupply},
+			client.Output{addr, 1}), "value"},
+		// Outputs of the whole supply whose total wraps past MaxSupply.
+		{debitMany(wrapping...), "value"},
+		{cl.CreateAccountHook(0, "https://example.com/hook"), "accountID"},
+		{accountHooks(-1), "accountID"},
+		{updateAccount(0, client.Label("a")), "accountID"},
+		{updateAccount(1), "options"},
+		{cl.CloseAccount(-1), "accountID"},
+		{updateAccount(1, client.Metadata(map[string]string{"": "a"})),
+			"metadata"},
+		{cl.DeleteAccountHook(0, "https://example.com/hook"), "accountID"},
+		{cl.CreateHook("https://example.com/hook",
+			client.HookSecret("short")), "secret"},
+		{cl.CreateHook("https://example.com/hook",
+			client.HookAuthorization("Bearer a\r\nX-A: b")), "authorization"},
+		{cl.CreateHook("https://example.com/hook",
+			client.HookContentType("json")), "contentType"},
+		{accountTransactions(client.TypeFilter(0)), "types"},
+		{accounts(client.Order("up")), "order"},
+		{accounts(client.SortBy("name")), "sortBy"},
+		{createAccounts(-1), "n"},
+		{createTransactionIDs(-1), "n"},
+	}
+	for i, test := range tests {
+		var verr *client.ValidationError
+		if !errors.As(test.err, &verr) || verr.Field != test.field {
+			t.Errorf("%d: expected invalid %s, got %v", i, test.field,
+				test.err)
+		}
+	}
+
+	if _, err := cl.Account(0); err == nil {
+		t.Error("expected invalid account ID")
+	}
+	if _, err := cl.Transaction(-1); err == nil {
+		t.Error("expected invalid transaction ID")
+	}
+	if requests != 0 {
+		t.Fatal("invalid calls made requests", requests)
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"fmt"
+)
+
+// AddressVerifier checks that a deposit address returned by RTWire belongs to
+// the wallet expected for accountID, for example by deriving the address from
+// an extended public key with BIP32. RTWire does not currently expose
+// derivation information so the verifier must know the expected wallet
+// itself.
+type AddressVerifier interface {
+	VerifyAddress(accountID int64, address string) error
+}
+
+// AddressVerifierFunc adapts a function to the AddressVerifier interface.
+type AddressVerifierFunc func(accountID int64, address string) error
+
+// VerifyAddress calls f(accountID, address).
+func (f AddressVerifierFunc) VerifyAddress(accountID int64, address string) error {
+	return f(accountID, address)
+}
+
+// AddressVerificationError is returned from CreateAddress when the configured
+// AddressVerifier rejects the address returned by RTWire. The address must not
+// be handed out for deposits.
+type AddressVerificationError struct {
+	AccountID int64
+	Address   string
+	Err       error
+}
+
+func (e *AddressVerificationError) Error() string {
+	return fmt.Sprintf("address %s for account %d failed verification: %v",
+		e.Address, e.AccountID, e.Err)
+}
+
+func (e *AddressVerificationError) Unwrap() error {
+	return e.Err
+}
+
+// WithAddressVerifier configures the client to check every address returned by
+// CreateAddress with v, protecting against an address being substituted
+// between RTWire and the client.
+func WithAddressVerifier(v AddressVerifier) ClientOption {
+	return func(c *client) {
+		c.addressVerifier = v
+	}
+}
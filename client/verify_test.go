@@ -0,0 +1,49 @@
+package client_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestAddressVerifier(t *testing.T) {
+
+	server := newRoutesServer(map[string]string{
+		"POST /accounts/1/addresses/": `{"type": "addresses",
+			"payload": [{"address": "expected"}]}`,
+		"POST /accounts/2/addresses/": `{"type": "addresses",
+			"payload": [{"address": "substituted"}]}`,
+	})
+	defer server.Close()
+
+	errUnknown := errors.New("unknown address")
+	verifier := client.AddressVerifierFunc(func(id int64, addr string) error {
+		if addr != "expected" {
+			return errUnknown
+		}
+		return nil
+	})
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass",
+		client.WithAddressVerifier(verifier))
+
+	if _, err := cl.CreateAddress(1); err != nil {
+		t.Fatal(err)
+	}
+
+	addr, err := cl.CreateAddress(2)
+	if addr != "" {
+		t.Fatal("expected no address")
+	}
+	var verr *client.AddressVerificationError
+	if !errors.As(err, &verr) || verr.Address != "substituted" {
+		t.Fatal("expected verification error", err)
+	}
+	if !errors.Is(err, errUnknown) {
+		t.Fatal("expected verifier error to be wrapped")
+	}
+}
@@ -0,0 +1,182 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// watchOverlap is how far before the newest transaction seen a Watcher keeps
+// looking, for transactions listed later than others created at about the
+// same time.
+const watchOverlap = time.Minute
+
+// Watcher polls the transactions of a set of accounts and passes each new one
+// to a handler as the TransactionEvent a hook would have been sent, for
+// services that cannot receive hook deliveries, for example behind NAT. A
+// credit is passed once with a "pending" status while it is listed by
+// AccountTransactions with Pending, and again once it is released.
+//
+// The first poll only passes transactions created after since, or every
+// transaction if since is zero, and the pending credits. A Watcher keeps what
+// it has seen in memory, so a restarted Watcher passes the pending credits
+// again. The handler should therefore be idempotent, as for hooks.
+type Watcher struct {
+	client   Client
+	handler  func(context.Context, TransactionEvent) error
+	accounts map[int64]*watchState
+
+	mu sync.Mutex
+}
+
+type watchState struct {
+	after   time.Time
+	seen    map[int64]time.Time
+	pending map[int64]bool
+}
+
+// NewWatcher returns a Watcher passing the transactions of accountIDs created
+// after since to handler.
+func NewWatcher(c Client, accountIDs []int64, since time.Time,
+	handler func(context.Context, TransactionEvent) error) *Watcher {
+	w := &Watcher{
+		client:   c,
+		handler:  handler,
+		accounts: map[int64]*watchState{},
+	}
+	for _, id := range accountIDs {
+		w.accounts[id] = &watchState{
+			after:   since,
+			seen:    map[int64]time.Time{},
+			pending: map[int64]bool{},
+		}
+	}
+	return w
+}
+
+// Poll lists the transactions of each account once and passes the new ones to
+// the handler, oldest first. If the handler fails the account's remaining
+// transactions are left for the next Poll, and the error is returned after
+// the other accounts are polled.
+func (w *Watcher) Poll(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var first error
+	for _, id := range sortedIDs(w.accounts) {
+		if err := w.poll(ctx, id, w.accounts[id]); err != nil &&
+			first == nil {
+			first = fmt.Errorf("watch account %d: %w", id, err)
+		}
+	}
+	return first
+}
+
+// Run polls every interval until ctx is done, returning the first error from
+// Poll.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if err := w.Poll(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (w *Watcher) poll(ctx context.Context, accountID int64,
+	s *watchState) error {
+	pending, err := AllTransactions(ctx, w.client, accountID, Pending())
+	if err != nil {
+		return err
+	}
+	listed := map[int64]bool{}
+	for _, tx := range pending {
+		listed[tx.ID] = true
+		if s.pending[tx.ID] {
+			continue
+		}
+		if err := w.handler(ctx, TransactionEvent{tx, "pending"}); err != nil {
+			return err
+		}
+		s.pending[tx.ID] = true
+	}
+	for id := range s.pending {
+		if !listed[id] {
+			delete(s.pending, id)
+		}
+	}
+
+	released, err := w.released(ctx, accountID, s)
+	if err != nil {
+		return err
+	}
+	for i := len(released) - 1; i >= 0; i-- {
+		tx := released[i]
+		if err := w.handler(ctx, TransactionEvent{tx, ""}); err != nil {
+			return err
+		}
+		s.seen[tx.ID] = tx.Created
+		if after := tx.Created.Add(-watchOverlap); after.After(s.after) {
+			s.after = after
+		}
+	}
+	for id, created := range s.seen {
+		if created.Before(s.after) {
+			delete(s.seen, id)
+		}
+	}
+	return nil
+}
+
+// released returns the transactions of accountID created after s.after that
+// have not been seen, newest first.
+func (w *Watcher) released(ctx context.Context, accountID int64,
+	s *watchState) ([]Transaction, error) {
+	var (
+		txns []Transaction
+		next string
+	)
+	for {
+		options := []option{SortBy(SortByCreated), Descending()}
+		if next != "" {
+			options = append(options, Next(next))
+		}
+		var (
+			page []Transaction
+			err  error
+		)
+		next, page, err = w.client.AccountTransactionsContext(ctx, accountID,
+			options...)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range page {
+			if !tx.Created.After(s.after) {
+				return txns, nil
+			}
+			if _, ok := s.seen[tx.ID]; !ok {
+				txns = append(txns, tx)
+			}
+		}
+		if next == "" {
+			return txns, nil
+		}
+	}
+}
+
+func sortedIDs(accounts map[int64]*watchState) []int64 {
+	ids := make([]int64, 0, len(accounts))
+	for id := range accounts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
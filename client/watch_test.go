@@ -0,0 +1,81 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+func TestWatcher(t *testing.T) {
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tx := func(id int64, minutes int) client.Transaction {
+		return client.Transaction{ID: id, Type: "credit", ToAccountID: 4,
+			Created: since.Add(time.Duration(minutes) * time.Minute)}
+	}
+	var pending, released []client.Transaction
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			txns := released
+			if q.Get("status") == "pending" {
+				txns = pending
+			} else if q.Get("sortBy") != "created" || q.Get("order") != "desc" {
+				t.Error("expected newest transactions first", r.URL)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"type": "transactions", "payload": txns})
+		}))
+	defer server.Close()
+
+	url := fmt.Sprintf("%s/v1/mainnet", server.URL)
+	cl := client.New(http.DefaultClient, url, "user", "pass")
+
+	var events []string
+	fail := int64(0)
+	w := client.NewWatcher(cl, []int64{4}, since,
+		func(ctx context.Context, e client.TransactionEvent) error {
+			if e.ID == fail {
+				return errors.New("failed")
+			}
+			events = append(events, fmt.Sprintf("%d/%s", e.ID, e.Status))
+			return nil
+		})
+	poll := func(want string) {
+		t.Helper()
+		events = nil
+		if err := w.Poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(events, ","); got != want {
+			t.Fatalf("expected %q got %q", want, got)
+		}
+	}
+
+	// Transactions created before since are not passed.
+	pending = []client.Transaction{tx(5, 3)}
+	released = []client.Transaction{tx(3, 2), tx(2, 1), tx(1, -1)}
+	poll("5/pending,2/,3/")
+	poll("")
+
+	pending = nil
+	released = append([]client.Transaction{tx(5, 3)}, released...)
+	poll("5/")
+
+	released = append([]client.Transaction{tx(7, 5), tx(6, 4)}, released...)
+	fail = 6
+	if err := w.Poll(context.Background()); err == nil {
+		t.Fatal("expected handler error")
+	}
+	fail = 0
+	poll("6/,7/")
+}
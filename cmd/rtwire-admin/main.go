@@ -0,0 +1,279 @@
+// Command rtwire-admin serves a small read only web interface to an RTWire
+// account for teams without their own back office. It lists accounts and
+// balances, recent and pending transactions per account, and registered hooks
+// along with whether they are reachable.
+//
+// Credentials are read from the RTWIRE_USER and RTWIRE_PASS environment
+// variables. The interface has no authentication of its own and listens on
+// the loopback interface by default. Requests are refused unless their Host
+// is the listen address or localhost, so that web pages visited by the
+// operator cannot read it through DNS rebinding.
+//
+//	rtwire-admin [-addr 127.0.0.1:8080] [-url URL]
+package main
+
+import (
+	"context"
+	"flag"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rtwire/go/client"
+)
+
+const pageSize = 50
+
+var templates = template.Must(template.New("layout").Parse(`
+{{define "header"}}<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>RTWire admin</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { padding: 0.3em 1em; border-bottom: 1px solid #ddd; text-align: left; }
+.num { text-align: right; font-family: monospace; }
+.bad { color: #b00; }
+</style></head>
+<body><p><a href="/">Accounts</a> | <a href="/hooks">Hooks</a></p>
+{{end}}
+{{define "footer"}}</body></html>{{end}}
+
+{{define "accounts"}}{{template "header"}}
+<h1>Accounts</h1>
+<table><tr><th>ID</th><th class="num">Balance (sat)</th></tr>
+{{range .Accounts}}<tr>
+<td><a href="/accounts/{{.ID}}">{{.ID}}</a></td>
+<td class="num">{{.Balance}}</td></tr>
+{{end}}</table>
+{{if .Next}}<p><a href="/?next={{.Next}}">Next page</a></p>{{end}}
+{{template "footer"}}{{end}}
+
+{{define "account"}}{{template "header"}}
+<h1>Account {{.Account.ID}}</h1>
+<p>Balance: {{.Account.Balance}} sat</p>
+<h2>Pending transactions</h2>
+{{template "transactions" .Pending}}
+<h2>Recent transactions</h2>
+{{template "transactions" .Transactions}}
+{{if .Next}}<p><a href="/accounts/{{.Account.ID}}?next={{.Next}}">Older</a></p>{{end}}
+<h2>Account hooks</h2>
+<ul>{{range .Hooks}}<li>{{.URL}}</li>{{else}}<li>None</li>{{end}}</ul>
+{{template "footer"}}{{end}}
+
+{{define "transactions"}}<table>
+<tr><th>ID</th><th>Type</th><th>Created</th><th>From</th><th>To</th>
+<th class="num">Value (sat)</th></tr>
+{{range .}}<tr><td>{{.ID}}</td><td>{{.Type}}</td>
+<td>{{.Created.Format "2006-01-02 15:04:05"}}</td>
+<td>{{.FromAccountID}}</td><td>{{.ToAccountID}}</td>
+<td class="num">{{.Value}}</td></tr>
+{{else}}<tr><td colspan="6">None</td></tr>{{end}}
+</table>{{end}}
+
+{{define "hooks"}}{{template "header"}}
+<h1>Hooks</h1>
+<table><tr><th>URL</th><th>Status</th></tr>
+{{range .}}<tr><td>{{.URL}}</td>
+<td{{if .Err}} class="bad"{{end}}>{{if .Err}}{{.Err}}{{else}}{{.Status}}{{end}}</td></tr>
+{{else}}<tr><td colspan="2">None</td></tr>{{end}}
+</table>
+{{template "footer"}}{{end}}
+`))
+
+type server struct {
+	client client.Client
+	probe  *http.Client
+
+	// hosts holds the Host header values requests may use.
+	hosts map[string]bool
+}
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8080", "address to listen on")
+	url := flag.String("url", client.MainNetURL, "RTWire endpoint URL")
+	flag.Parse()
+
+	s := &server{
+		client: client.New(nil, *url,
+			os.Getenv("RTWIRE_USER"), os.Getenv("RTWIRE_PASS"),
+			client.WithTimeout(30*time.Second),
+			client.WithUserAgent("rtwire-admin")),
+		probe: &http.Client{Timeout: 5 * time.Second},
+		hosts: allowedHosts(*addr),
+	}
+
+	log.Printf("rtwire-admin listening on http://%s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, s))
+}
+
+// allowedHosts returns the Host header values accepted when listening on
+// addr: addr itself and the loopback names with its port.
+func allowedHosts(addr string) map[string]bool {
+	hosts := map[string]bool{strings.ToLower(addr): true}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return hosts
+	}
+	for _, host := range []string{"localhost", "127.0.0.1", "::1"} {
+		hosts[net.JoinHostPort(host, port)] = true
+	}
+	return hosts
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.hosts[strings.ToLower(r.Host)] {
+		http.Error(w, "unexpected host", http.StatusForbidden)
+		return
+	}
+	if r.Method != "GET" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch path := r.URL.Path; {
+	case path == "/":
+		s.accounts(w, r)
+	case path == "/hooks":
+		s.hooks(w, r)
+	case strings.HasPrefix(path, "/accounts/"):
+		id, err := strconv.ParseInt(strings.TrimPrefix(path, "/accounts/"),
+			10, 64)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		s.account(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := templates.ExecuteTemplate(w, name, data); err != nil {
+		log.Print(err)
+	}
+}
+
+func (s *server) accounts(w http.ResponseWriter, r *http.Request) {
+	var (
+		next string
+		accs []client.Account
+		err  error
+	)
+	if cursor := r.URL.Query().Get("next"); cursor != "" {
+		next, accs, err = s.client.AccountsContext(r.Context(),
+			client.Limit(pageSize), client.Next(cursor))
+	} else {
+		next, accs, err = s.client.AccountsContext(r.Context(),
+			client.Limit(pageSize))
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	render(w, "accounts", struct {
+		Accounts []client.Account
+		Next     string
+	}{accs, next})
+}
+
+func (s *server) account(w http.ResponseWriter, r *http.Request, id int64) {
+	ctx := r.Context()
+
+	acc, err := s.client.AccountContext(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	var (
+		next string
+		txns []client.Transaction
+	)
+	if cursor := r.URL.Query().Get("next"); cursor != "" {
+		next, txns, err = s.client.AccountTransactionsContext(ctx, id,
+			client.Limit(pageSize), client.Next(cursor))
+	} else {
+		next, txns, err = s.client.AccountTransactionsContext(ctx, id,
+			client.Limit(pageSize))
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	_, pending, err := s.client.AccountTransactionsContext(ctx, id,
+		client.Pending(), client.Limit(pageSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	hooks, err := s.client.AccountHooksContext(ctx, id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	render(w, "account", struct {
+		Account      client.Account
+		Transactions []client.Transaction
+		Pending      []client.Transaction
+		Next         string
+		Hooks        []client.Hook
+	}{acc, txns, pending, next, hooks})
+}
+
+type hookStatus struct {
+	URL    string
+	Status string
+	Err    error
+}
+
+// hooks lists the registered hooks and probes each to check it is reachable.
+// Any HTTP response counts as reachable since hook endpoints are not expected
+// to answer GET requests successfully.
+func (s *server) hooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := s.client.HooksContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	statuses := make([]hookStatus, len(hooks))
+	var wg sync.WaitGroup
+	for i, hook := range hooks {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			statuses[i] = s.probeHook(r.Context(), url)
+		}(i, hook.URL)
+	}
+	wg.Wait()
+
+	render(w, "hooks", statuses)
+}
+
+func (s *server) probeHook(ctx context.Context, url string) hookStatus {
+	status := hookStatus{URL: url}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	resp, err := s.probe.Do(req)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	resp.Body.Close()
+	status.Status = "reachable (" + resp.Status + ")"
+	return status
+}
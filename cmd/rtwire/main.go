@@ -0,0 +1,347 @@
+// Command rtwire is a command line interface to the RTWire HTTP endpoints.
+//
+// Credentials are read from the RTWIRE_USER and RTWIRE_PASS environment
+// variables. Amounts are parsed with client.ParseAmount in the unit given by
+// the mandatory -unit flag, so ambiguous amounts such as "1,000" are refused.
+//
+//	rtwire [-url URL] transfer -from ID -to ID -amount AMOUNT -unit btc|mbtc|sat
+//	rtwire [-url URL] debit -from ID -address ADDR -amount AMOUNT -unit btc|mbtc|sat [-feeperbyte SAT | -target BLOCKS]
+//	rtwire [-url URL] selftest -a ID -b ID [-timeout DURATION]
+//	rtwire [-url URL] audithooks [-allow DOMAINS] [-accounts IDS]
+//	rtwire [-url URL] accountstats -file FILE [-record] [-period day|week]
+//	rtwire [-url URL] txdiff -id ID -file FILE | -other URL
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rtwire/go/client"
+	"github.com/rtwire/go/client/stats"
+)
+
+type command struct {
+	usage string
+	run   func(cl client.Client, args []string) error
+}
+
+var commands = map[string]command{
+	"transfer": {
+		usage: "transfer -from ID -to ID -amount AMOUNT -unit btc|mbtc|sat",
+		run:   transfer,
+	},
+	"debit": {
+		usage: "debit -from ID -address ADDR -amount AMOUNT " +
+			"-unit btc|mbtc|sat [-feeperbyte SAT | -target BLOCKS]",
+		run: debit,
+	},
+	"selftest": {
+		usage: "selftest -a ID -b ID [-timeout DURATION]",
+		run:   selftest,
+	},
+	"audithooks": {
+		usage: "audithooks [-allow DOMAINS] [-accounts IDS]",
+		run:   auditHooks,
+	},
+	"accountstats": {
+		usage: "accountstats -file FILE [-record] [-period day|week]",
+		run:   accountStats,
+	},
+	"txdiff": {
+		usage: "txdiff -id ID -file FILE | -other URL",
+		run:   txDiff,
+	},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: rtwire [-url URL] command [arguments]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(os.Stderr, "  "+commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "\nflags:")
+	flag.PrintDefaults()
+}
+
+func main() {
+	url := flag.String("url", client.MainNetURL, "RTWire endpoint URL")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	cl := client.New(http.DefaultClient, *url,
+		os.Getenv("RTWIRE_USER"), os.Getenv("RTWIRE_PASS"))
+
+	if err := cmd.run(cl, flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "rtwire:", err)
+		os.Exit(1)
+	}
+}
+
+// amountFlag parses an amount flag in the given unit, refusing anything
+// ambiguous.
+func amountFlag(amount, unit string) (client.Amount, error) {
+	if unit == "" {
+		return 0, fmt.Errorf("-unit must be set")
+	}
+	u, err := client.ParseUnit(unit)
+	if err != nil {
+		return 0, err
+	}
+	value, err := client.ParseAmount(amount, u)
+	if err != nil {
+		return 0, err
+	}
+	if value == 0 {
+		return 0, fmt.Errorf("amount must be greater than zero")
+	}
+	return value, nil
+}
+
+// txID returns id if set, otherwise a freshly created transaction ID.
+func txID(cl client.Client, id int64) (int64, error) {
+	if id != 0 {
+		return id, nil
+	}
+	ids, err := cl.CreateTransactionIDs(1)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+func transfer(cl client.Client, args []string) error {
+	fs := flag.NewFlagSet("transfer", flag.ExitOnError)
+	id := fs.Int64("txid", 0, "transaction ID, created if not set")
+	from := fs.Int64("from", 0, "account ID to transfer from")
+	to := fs.Int64("to", 0, "account ID to transfer to")
+	amount := fs.String("amount", "", "amount to transfer")
+	unit := fs.String("unit", "", "unit of -amount: btc, mbtc or sat")
+	fs.Parse(args)
+
+	value, err := amountFlag(*amount, *unit)
+	if err != nil {
+		return err
+	}
+	tx, err := txID(cl, *id)
+	if err != nil {
+		return err
+	}
+	if _, err := cl.Transfer(tx, *from, *to, value); err != nil {
+		return err
+	}
+	fmt.Printf("transferred %d sat from %d to %d (txid %d)\n",
+		value, *from, *to, tx)
+	return nil
+}
+
+func debit(cl client.Client, args []string) error {
+	fs := flag.NewFlagSet("debit", flag.ExitOnError)
+	id := fs.Int64("txid", 0, "transaction ID, created if not set")
+	from := fs.Int64("from", 0, "account ID to debit")
+	address := fs.String("address", "", "bitcoin address to pay")
+	amount := fs.String("amount", "", "amount to debit")
+	unit := fs.String("unit", "", "unit of -amount: btc, mbtc or sat")
+	feePerByte := fs.Int64("feeperbyte", 0, "miner fee in satoshi per byte")
+	target := fs.Int("target", 0, "confirmation target in blocks")
+	fs.Parse(args)
+
+	value, err := amountFlag(*amount, *unit)
+	if err != nil {
+		return err
+	}
+	var options []client.Option
+	if *feePerByte != 0 {
+		options = append(options, client.FeePerByte(*feePerByte))
+	}
+	if *target != 0 {
+		options = append(options, client.ConfirmationTarget(*target))
+	}
+	tx, err := txID(cl, *id)
+	if err != nil {
+		return err
+	}
+	debit, err := cl.Debit(tx, *from, *address, value, options...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("debited %d sat from %d to %s (txid %d)\n",
+		value, *from, *address, tx)
+	for _, hash := range debit.TxHashes {
+		fmt.Println("bitcoin transaction", hash)
+	}
+	return nil
+}
+
+func selftest(cl client.Client, args []string) error {
+	fs := flag.NewFlagSet("selftest", flag.ExitOnError)
+	a := fs.Int64("a", 0, "account ID holding at least one satoshi")
+	b := fs.Int64("b", 0, "second account ID")
+	timeout := fs.Duration("timeout", 30*time.Second, "time allowed for the test")
+	fs.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if err := client.SelfTest(ctx, cl, *a, *b); err != nil {
+		return err
+	}
+	fmt.Println("ok")
+	return nil
+}
+
+// splitList splits a comma separated flag value, ignoring empty elements.
+func splitList(list string) []string {
+	var elems []string
+	for _, elem := range strings.Split(list, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			elems = append(elems, elem)
+		}
+	}
+	return elems
+}
+
+func auditHooks(cl client.Client, args []string) error {
+	fs := flag.NewFlagSet("audithooks", flag.ExitOnError)
+	allow := fs.String("allow", "", "comma separated domains hooks may use")
+	accounts := fs.String("accounts", "",
+		"comma separated account IDs whose hooks are also checked")
+	fs.Parse(args)
+
+	var ids []int64
+	for _, elem := range splitList(*accounts) {
+		id, err := strconv.ParseInt(elem, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid account ID %q", elem)
+		}
+		ids = append(ids, id)
+	}
+
+	policy := client.HookPolicy{AllowedDomains: splitList(*allow)}
+	if err := client.AuditHooks(context.Background(), cl, policy,
+		ids...); err != nil {
+		return err
+	}
+	fmt.Println("ok")
+	return nil
+}
+
+// accountStats reports account growth from the snapshots saved in a file,
+// first appending a new snapshot if -record is given.
+func accountStats(cl client.Client, args []string) error {
+	fs := flag.NewFlagSet("accountstats", flag.ExitOnError)
+	file := fs.String("file", "", "file holding account snapshots")
+	record := fs.Bool("record", false, "append a snapshot before reporting")
+	period := fs.String("period", "day", "period to group by: day or week")
+	fs.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("-file is required")
+	}
+	var p stats.Period
+	switch *period {
+	case "day":
+		p = stats.Day
+	case "week":
+		p = stats.Week
+	default:
+		return fmt.Errorf("unknown period %q", *period)
+	}
+
+	if *record {
+		s, err := stats.TakeSnapshot(context.Background(), cl)
+		if err != nil {
+			return err
+		}
+		f, err := os.OpenFile(*file, os.O_WRONLY|os.O_CREATE|os.O_APPEND,
+			0644)
+		if err != nil {
+			return err
+		}
+		if err := stats.WriteSnapshot(f, s); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	snapshots, err := stats.ReadSnapshots(f)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("period\tcreated\taccounts\tbalance")
+	for _, pt := range stats.Growth(snapshots, p) {
+		fmt.Printf("%s\t%d\t%d\t%d\n", pt.Start.Format("2006-01-02"),
+			pt.Created, pt.Accounts, pt.Balance)
+	}
+	return nil
+}
+
+// txDiff compares a transaction with an earlier copy saved as JSON, for
+// example while it was pending, or with the same transaction in another
+// RTWire environment.
+func txDiff(cl client.Client, args []string) error {
+	fs := flag.NewFlagSet("txdiff", flag.ExitOnError)
+	id := fs.Int64("id", 0, "transaction ID")
+	file := fs.String("file", "", "file holding an earlier copy as JSON")
+	other := fs.String("other", "", "URL of another RTWire environment")
+	fs.Parse(args)
+
+	var old client.Transaction
+	switch {
+	case *file != "" && *other == "":
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &old); err != nil {
+			return fmt.Errorf("%s: %v", *file, err)
+		}
+	case *other != "" && *file == "":
+		otherCl := client.New(http.DefaultClient, *other,
+			os.Getenv("RTWIRE_USER"), os.Getenv("RTWIRE_PASS"))
+		var err error
+		if old, err = otherCl.Transaction(*id); err != nil {
+			return fmt.Errorf("%s: %v", *other, err)
+		}
+	default:
+		return fmt.Errorf("one of -file or -other is required")
+	}
+
+	tx, err := cl.Transaction(*id)
+	if err != nil {
+		return err
+	}
+	fmt.Println(client.DiffTransactions(old, tx))
+	return nil
+}
@@ -0,0 +1,123 @@
+// Package sqs forwards RTWire hook events to an Amazon SQS FIFO queue so that
+// payment events can be consumed with standard AWS tooling.
+//
+// Each transaction event is sent with the transaction ID and status as its
+// deduplication ID, so a delivery RTWire retries is dropped by SQS rather than
+// queued twice, within SQS's five minute deduplication interval. Events are
+// grouped by transaction ID, keeping the events of a transaction in order.
+//
+// The package depends only on the small Queue interface so that it can be used
+// with any AWS SDK through an adapter. An adapter for aws-sdk-go-v2 only needs
+// to call sqs.Client.SendMessage with the queue URL, MessageBody,
+// MessageGroupId and MessageDeduplicationId set from the Message.
+//
+//	http.Handle("/rtwire", sqs.NewBridge(queue))
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/rtwire/go/client"
+)
+
+// Message is a message to send to a FIFO queue.
+type Message struct {
+	Body            string
+	GroupID         string
+	DeduplicationID string
+}
+
+// Queue sends messages to an SQS FIFO queue.
+type Queue interface {
+	SendMessage(ctx context.Context, m Message) error
+}
+
+// QueueFunc adapts a function to the Queue interface.
+type QueueFunc func(ctx context.Context, m Message) error
+
+// SendMessage calls f(ctx, m).
+func (f QueueFunc) SendMessage(ctx context.Context, m Message) error {
+	return f(ctx, m)
+}
+
+// Bridge receives RTWire hook deliveries and forwards their transaction events
+// to a Queue.
+type Bridge struct {
+	queue Queue
+}
+
+// NewBridge creates a Bridge that forwards events to queue.
+func NewBridge(queue Queue) *Bridge {
+	return &Bridge{queue: queue}
+}
+
+// Forward sends each of events to the queue, in order, as the JSON encoding of
+// the event. It stops at the first event that is invalid or could not be sent.
+func (b *Bridge) Forward(ctx context.Context,
+	events []client.TransactionEvent) error {
+	for _, e := range events {
+		m, err := message(e)
+		if err != nil {
+			return err
+		}
+		if err := b.queue.SendMessage(ctx, m); err != nil {
+			return fmt.Errorf("sqs: send transaction %d %s: %w", e.ID,
+				e.Status, err)
+		}
+	}
+	return nil
+}
+
+// message returns the message for e.
+func message(e client.TransactionEvent) (Message, error) {
+	if e.ID <= 0 {
+		return Message{}, errors.New("sqs: event without a transaction ID")
+	}
+	if e.Status == "" {
+		return Message{}, fmt.Errorf("sqs: transaction %d without a status",
+			e.ID)
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return Message{}, err
+	}
+	id := strconv.FormatInt(e.ID, 10)
+	return Message{
+		Body:            string(body),
+		GroupID:         id,
+		DeduplicationID: id + "-" + e.Status,
+	}, nil
+}
+
+// ServeHTTP receives RTWire hook deliveries and forwards their transaction
+// events. Other kinds of event are acknowledged and dropped. A 400 response is
+// returned for deliveries that can't be decoded or hold invalid events, and a
+// 500 response if an event could not be sent so that RTWire retries the
+// delivery; events already sent are then deduplicated by SQS.
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	events, err := client.UnmarshalEvents(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var txEvents []client.TransactionEvent
+	for _, e := range events {
+		if txEvent, ok := e.(client.TransactionEvent); ok {
+			txEvents = append(txEvents, txEvent)
+		}
+	}
+	for _, e := range txEvents {
+		if _, err := message(e); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := b.Forward(r.Context(), txEvents); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
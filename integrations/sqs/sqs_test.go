@@ -0,0 +1,69 @@
+package sqs_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rtwire/go/integrations/sqs"
+)
+
+func TestBridge(t *testing.T) {
+
+	var sent []sqs.Message
+	fail := false
+	bridge := sqs.NewBridge(sqs.QueueFunc(
+		func(ctx context.Context, m sqs.Message) error {
+			if fail {
+				return errors.New("unavailable")
+			}
+			sent = append(sent, m)
+			return nil
+		}))
+
+	deliver := func(body string) int {
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		bridge.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	const delivery = `{"type": "transactions", "payload": [
+		{"id": 7, "type": "credit", "value": 10, "status": "pending"},
+		{"id": 7, "type": "credit", "value": 10, "status": "credited"}]}`
+	if code := deliver(delivery); code != http.StatusOK {
+		t.Fatal("unexpected status", code)
+	}
+	if len(sent) != 2 || sent[0].GroupID != "7" ||
+		sent[0].DeduplicationID != "7-pending" ||
+		sent[1].DeduplicationID != "7-credited" {
+		t.Fatalf("unexpected messages %+v", sent)
+	}
+	if !strings.Contains(sent[0].Body, `"status":"pending"`) {
+		t.Fatal("expected the event as the body", sent[0].Body)
+	}
+
+	// Other events are acknowledged without being sent.
+	sent = nil
+	if code := deliver(`{"type": "accounts",
+		"payload": [{"id": 1}]}`); code != http.StatusOK || len(sent) != 0 {
+		t.Fatal("expected account event to be dropped", code, sent)
+	}
+
+	// Invalid events are refused before anything is sent.
+	if code := deliver(`{"type": "transactions", "payload": [
+		{"id": 8, "status": "pending"}, {"id": 9}]}`); code !=
+		http.StatusBadRequest || len(sent) != 0 {
+		t.Fatal("expected invalid delivery to be refused", code, sent)
+	}
+
+	// Failures to send ask RTWire to retry.
+	fail = true
+	if code := deliver(delivery); code != http.StatusInternalServerError {
+		t.Fatal("expected retry status", code)
+	}
+}